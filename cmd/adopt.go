@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v3"
+)
+
+var (
+	adoptCmd_namespace      string
+	adoptCmd_integrationIds []string
+	adoptCmd_monitoredPaths []string
+	adoptCmd_monitorIds     []string
+)
+
+func init() {
+	adoptCmd.Flags().
+		StringArrayVar(&adoptCmd_integrationIds, "integration", []string{}, "Adopt monitors by integration IDs. AND'ed with other scopes.")
+	adoptCmd.Flags().
+		StringArrayVar(&adoptCmd_monitoredPaths, "monitored", []string{}, "Adopt monitors by monitored asset paths. AND'ed with other scopes.")
+	adoptCmd.Flags().
+		StringArrayVar(&adoptCmd_monitorIds, "monitor", []string{}, "Adopt monitors by monitor IDs. AND'ed with other scopes.")
+	adoptCmd.Flags().StringVar(&adoptCmd_namespace, "namespace", "", "Namespace adopting the monitors, required when the file does not exist yet")
+
+	rootCmd.AddCommand(adoptCmd)
+}
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt [file]",
+	Short: "Adopt monitors created in the app into a YAML namespace",
+	Long: `Write monitors created in the app into a v1beta2 file, so that they are managed from YAML.
+
+The monitors keep their UUIDs as explicit ids. The next deploy of the file transfers them to its namespace
+instead of recreating them, so their history and incidents are kept.
+
+The file is created when it does not exist, otherwise the monitors are merged into it, keeping its comments,
+order and anchors. At least one scope is required.`,
+	Args: cobra.ExactArgs(1),
+	RunE: adoptMonitors,
+}
+
+func adoptMonitors(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	filePath := args[0]
+
+	if len(adoptCmd_integrationIds)+len(adoptCmd_monitoredPaths)+len(adoptCmd_monitorIds) == 0 {
+		return validationError(errors.New("❌ Provide at least one of --integration, --monitored or --monitor to select the monitors to adopt."))
+	}
+	// Only app monitors can be adopted.
+	scope := exportScope{
+		source:         "app",
+		integrationIds: adoptCmd_integrationIds,
+		monitoredPaths: adoptCmd_monitoredPaths,
+		monitorIds:     adoptCmd_monitorIds,
+	}
+
+	_, err := os.Stat(filePath)
+	exists := err == nil
+	if exists {
+		if err := checkAdoptingFile(filePath); err != nil {
+			return err
+		}
+	} else if adoptCmd_namespace == "" {
+		return validationError(fmt.Errorf("❌ '%s' does not exist, provide the --namespace of the file to create.", filePath))
+	}
+
+	conn, err := connectToApi(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprintf(logs, "Connected to API...\n\n")
+
+	workspace, err := fetchWorkspace(ctx, conn)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(logs, "🔍 Workspace: %s\nLooking for monitors to adopt\n\n", workspace)
+	events.emit(outputEvent{Event: eventType_Workspace, Workspace: workspace})
+
+	mgmtService := mgmt.NewMgmtRemoteService(ctx, conn).WithOutput(logs)
+	pathsConverter := newPathConverter(ctx, conn, workspace)
+
+	listScope, err := createListScope(pathsConverter, scope)
+	if err != nil {
+		return err
+	}
+	monitors, err := mgmtService.ListMonitors(listScope)
+	if err != nil {
+		return apiError(fmt.Errorf("❌ Error getting monitors: %v", err))
+	}
+	if len(monitors) == 0 {
+		return fmt.Errorf("❌ No app monitors found for the given scope: %+v", scope)
+	}
+	fmt.Fprintf(logs, "\n✅ Found %d app monitors. Adopting...\n", len(monitors))
+
+	if exists {
+		if err := mergeExport(pathsConverter, workspace, filePath, adoptCmd_namespace, yaml.MergeStrategy_Report, monitors); err != nil {
+			return err
+		}
+	} else {
+		yamlBytes, err := exportYaml(pathsConverter, workspace, scope, core.Version_V1Beta2, adoptCmd_namespace, monitors)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o770); err != nil {
+			return fmt.Errorf("❌ Error: Unable to create directory for file '%s'.", filePath)
+		}
+		if err := os.WriteFile(filePath, yamlBytes, 0o644); err != nil {
+			return fmt.Errorf("❌ Error writing YAML: %v", err)
+		}
+		events.emit(outputEvent{Event: eventType_Export, Namespace: adoptCmd_namespace, File: filePath, Monitors: len(monitors)})
+	}
+
+	fmt.Fprintf(logs, "✅ Adoption written to %s, deploy it to transfer the monitors to its namespace.\n", filePath)
+	return nil
+}
+
+// checkAdoptingFile only accepts v1beta2 files, whose entities keep the adopted monitors grouped like the export.
+func checkAdoptingFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var versionCheck core.Config
+	if err := goyaml.Unmarshal(content, &versionCheck); err != nil {
+		return validationError(fmt.Errorf("❌ Error parsing '%s': %v", filePath, err))
+	}
+	if versionCheck.Version != core.Version_V1Beta2 {
+		return validationError(fmt.Errorf("❌ '%s' is not a %s file, run the migrate command on it first.", filePath, core.Version_V1Beta2))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAdoptingFile(t *testing.T) {
+	dir := t.TempDir()
+
+	v1beta2File := filepath.Join(dir, "v1beta2.yaml")
+	require.NoError(t, os.WriteFile(v1beta2File, []byte("version: v1beta2\nnamespace: orders\nentities: []\n"), 0o644))
+	assert.NoError(t, checkAdoptingFile(v1beta2File))
+
+	v1beta1File := filepath.Join(dir, "v1beta1.yaml")
+	require.NoError(t, os.WriteFile(v1beta1File, []byte("namespace: orders\nmonitors: []\n"), 0o644))
+	err := checkAdoptingFile(v1beta1File)
+	assert.ErrorContains(t, err, "run the migrate command")
+	assert.Equal(t, ExitCode_Validation, exitCode(err))
+}
+
+func TestAdoptedMonitorsAreTransferred(t *testing.T) {
+	appMonitor := createMonitor("7d3b1e0a-2f4c-4a57-9a3e-5f0e0c1b2a3d", "", "pg::public::orders")
+	appMonitor.Name = "Orders volume"
+	appMonitor.Source = pb.MonitorDefinition_SOURCE_APP
+
+	converter := staticPathConverter{"pg::public::orders": "public.orders"}
+	content, err := exportYaml(converter, "workspace", exportScope{source: "app"}, core.Version_V1Beta2, "orders", []*pb.MonitorDefinition{appMonitor})
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "id: 7d3b1e0a-2f4c-4a57-9a3e-5f0e0c1b2a3d")
+
+	parser, err := yaml.NewVersionedParser(content)
+	require.NoError(t, err)
+	monitors, err := parser.ConvertToMonitorDefinitions()
+	require.NoError(t, err)
+	require.Len(t, monitors, 1)
+	monitors[0].MonitoredId = appMonitor.MonitoredId
+	assert.False(t, assignAndValidateUUIDs(standardOutput(), "workspace", "orders", monitors))
+
+	service := &fakeMgmtService{remote: map[string]*pb.MonitorDefinition{appMonitor.Id: appMonitor}}
+	changesOverview, err := service.ConfigChangesOverview(monitors, nil, nil, "orders")
+	require.NoError(t, err)
+	assert.Empty(t, changesOverview.MonitorsToCreate)
+	assert.Empty(t, changesOverview.MonitorsToDelete)
+	assert.Equal(t, []string{appMonitor.Id}, changesOverview.MonitorsManagedByApp)
+	require.Len(t, changesOverview.MonitorsChangesOverview, 1)
+	assert.Equal(t, "orders", changesOverview.MonitorsChangesOverview[0].NewDefinition.ConfigId)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyCmd_plan         string
+	applyCmd_autoConfirm  bool
+	applyCmd_maxDeletes   string
+	applyCmd_allowDeletes bool
+	applyCmd_noReset      bool
+)
+
+func init() {
+	applyCmd.Flags().StringVar(&applyCmd_plan, "plan", "", "Plan file written by the plan command")
+	applyCmd.Flags().BoolVar(&applyCmd_autoConfirm, "auto-confirm", false, "Automatically confirm all prompts (skip interactive confirmations)")
+	applyCmd.Flags().
+		StringVar(&applyCmd_maxDeletes, "max-deletes", "", "Refuse to apply a namespace deleting more monitors and tests than this number, or percentage like 25%")
+	applyCmd.Flags().BoolVar(&applyCmd_allowDeletes, "allow-deletes", false, "Allow deleting monitors and tests together with --auto-confirm")
+	applyCmd.Flags().BoolVar(&applyCmd_noReset, "no-reset", false, "Refuse to apply a plan whose changes would reset monitors")
+	applyCmd.MarkFlagRequired("plan")
+
+	rootCmd.AddCommand(applyCmd)
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply --plan <file>",
+	Short: "Deploy exactly the changes of a reviewed plan",
+	Long: `Apply a plan written by the plan command, without parsing any YAML.
+
+Before deploying, the remote monitors and tests of every planned namespace are fetched again.
+If any of them changed since the plan was computed, nothing is applied and the plan has to be recreated.`,
+	Args: cobra.NoArgs,
+	RunE: applyPlan,
+}
+
+func applyPlan(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	maxDeletes, err := parseMaxDeletes(applyCmd_maxDeletes)
+	if err != nil {
+		return err
+	}
+
+	plan, err := mgmt.ReadPlanFile(applyCmd_plan)
+	if err != nil {
+		return validationError(fmt.Errorf("❌ Error reading plan: %v", err))
+	}
+	fmt.Fprintf(logs, "📄 Plan %s computed at %s from %d files (%s)\n\n", applyCmd_plan, plan.CreatedAt, len(plan.Files), plan.InputHash)
+
+	conn, err := connectToApi(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprintf(logs, "Connected to API...\n\n")
+
+	workspace, err := fetchWorkspace(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if workspace != plan.Workspace {
+		return validationError(fmt.Errorf("❌ Plan was computed for workspace %s, connected to %s", plan.Workspace, workspace))
+	}
+	fmt.Fprintf(logs, "🔍 Workspace: %s\n\n", workspace)
+	events.emit(outputEvent{Event: eventType_Workspace, Workspace: workspace})
+
+	mgmtService := mgmt.NewMgmtRemoteService(ctx, conn).WithOutput(logs)
+
+	// Everything is checked before anything is applied, so that a drifted plan is never partially deployed.
+	driftDetected := false
+	for _, changesOverview := range plan.Namespaces {
+		if !changesOverview.HasChanges() {
+			continue
+		}
+
+		if breakingChanges := changesOverview.GetBreakingChanges(); len(breakingChanges) > 0 {
+			return breakingChangesError(fmt.Errorf("%+v\n❌ Breaking changes recorded in plan for namespace %s", breakingChanges, changesOverview.ConfigID))
+		}
+		if err := checkDeletes(changesOverview, maxDeletes, applyCmd_allowDeletes, applyCmd_autoConfirm); err != nil {
+			return err
+		}
+		if applyCmd_noReset {
+			if err := checkResets(changesOverview); err != nil {
+				return err
+			}
+		}
+
+		drift, err := mgmtService.DetectDrift(changesOverview)
+		if err != nil {
+			return apiError(fmt.Errorf("❌ Error fetching remote state of namespace %s: %v", changesOverview.ConfigID, err))
+		}
+		if len(drift) > 0 {
+			driftDetected = true
+			fmt.Fprintf(os.Stderr, "❌ Namespace '%s' has drifted from the plan:\n", changesOverview.ConfigID)
+			for _, d := range drift {
+				fmt.Fprintf(os.Stderr, "  - %s\n", d)
+			}
+		}
+	}
+	if driftDetected {
+		return validationError(fmt.Errorf("❌ Remote state changed since the plan was computed, please create a new plan"))
+	}
+
+	results := []namespaceResult{}
+	for _, changesOverview := range plan.Namespaces {
+		result := applyNamespace(mgmtService, changesOverview)
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", result.Err)
+		}
+		results = append(results, result)
+	}
+
+	printNamespacesSummary(results)
+	return namespacesError(results)
+}
+
+func applyNamespace(mgmtService mgmt.MgmtService, changesOverview *mgmt.ChangesOverview) namespaceResult {
+	namespace := changesOverview.ConfigID
+	changesOverview.Fprint(logs)
+	events.emit(outputEvent{Event: eventType_Changes, Namespace: namespace, Changes: changesOverview.Summary()})
+
+	if !changesOverview.HasChanges() {
+		return namespaceSucceeded(namespace, namespaceStatus_Unchanged)
+	}
+
+	if !applyCmd_autoConfirm {
+		if !confirm("Are you sure you want to apply this plan? (y/N)") {
+			return namespaceFailed(namespace, cancelledError(errors.New("❌ Deployment cancelled")))
+		}
+	} else {
+		fmt.Fprintln(logs, "✅ Auto-confirmed deployment!")
+	}
+
+	if err := mgmtService.DeployMonitors(changesOverview); err != nil {
+		return namespaceFailed(namespace, apiError(fmt.Errorf("❌ Error deploying monitors: %v", err)))
+	}
+
+	fmt.Fprintln(logs, "✅ Deployment complete!")
+	return namespaceSucceeded(namespace, namespaceStatus_Deployed)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/uuid"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/samber/lo"
+)
+
+// deletesLimit is the --max-deletes threshold, either a number of deletions
+// or a percentage of the monitors and tests a namespace manages before the deployment.
+type deletesLimit struct {
+	value   float64
+	percent bool
+	flag    string
+}
+
+// parseMaxDeletes returns nil when no limit is set.
+func parseMaxDeletes(value string) (*deletesLimit, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	invalid := validationError(fmt.Errorf("❌ Invalid --max-deletes '%s', expected a number like 10 or a percentage like 25%%", value))
+	if number, percent := strings.CutSuffix(value, "%"); percent {
+		limit, err := strconv.ParseFloat(number, 64)
+		if err != nil || limit < 0 || limit > 100 {
+			return nil, invalid
+		}
+		return &deletesLimit{value: limit, percent: true, flag: value}, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return nil, invalid
+	}
+	return &deletesLimit{value: float64(limit), flag: value}, nil
+}
+
+func (l *deletesLimit) exceeded(deletes, managed int) bool {
+	if l == nil {
+		return false
+	}
+	if l.percent {
+		return managed > 0 && float64(deletes)*100 > l.value*float64(managed)
+	}
+	return float64(deletes) > l.value
+}
+
+// checkDeletes refuses deployments deleting more than the limit, and deletions which would not be confirmed by anyone.
+func checkDeletes(changesOverview *mgmt.ChangesOverview, limit *deletesLimit, allowDeletes bool, autoConfirm bool) error {
+	counts := changesOverview.Summary().Counts
+	deletes := counts.MonitorsToDelete + counts.TestsToDelete
+	if deletes == 0 {
+		return nil
+	}
+
+	managed := deletes + counts.MonitorsToUpdate + counts.MonitorsUnchanged + counts.TestsToUpdate + counts.TestsUnchanged
+	if limit.exceeded(deletes, managed) {
+		return breakingChangesError(fmt.Errorf(
+			"❌ %d of the %d monitors and tests of namespace %s would be deleted, more than --max-deletes %s",
+			deletes, managed, changesOverview.ConfigID, limit.flag,
+		))
+	}
+
+	if autoConfirm && !allowDeletes {
+		return breakingChangesError(fmt.Errorf(
+			"❌ %d monitors and tests of namespace %s would be deleted, set --allow-deletes to delete them with --auto-confirm",
+			deletes, changesOverview.ConfigID,
+		))
+	}
+
+	return nil
+}
+
+// protectedMonitors returns the identities of the monitors declared with prevent_destroy in every namespace.
+func protectedMonitors(parsersByNamespace map[string][]*yaml.VersionedParser) []*pb.MonitorDefinition {
+	protected := []*pb.MonitorDefinition{}
+	for _, namespace := range sortedNamespaces(parsersByNamespace) {
+		for _, parser := range parsersByNamespace[namespace] {
+			protected = append(protected, parser.ConvertToProtectedMonitors()...)
+		}
+	}
+	return protected
+}
+
+// protectMonitors marks the planned deletions of protected monitors as breaking changes.
+//
+// A protected monitor is also looked up with the UUID it would have in the namespace of the overview,
+// so that it is kept when its file moved to another namespace, by mistake or without moved_from.
+// The namespace fails when the paths of protected monitors cannot be resolved, rather than deleting them.
+func protectMonitors(
+	pathsConverter paths.PathConverter,
+	workspace string,
+	changesOverview *mgmt.ChangesOverview,
+	protected []*pb.MonitorDefinition,
+) error {
+	if len(changesOverview.MonitorsToDelete) == 0 || len(protected) == 0 {
+		return nil
+	}
+
+	pathsToConvert := lo.Uniq(lo.FilterMap(protected, func(monitor *pb.MonitorDefinition, _ int) (string, bool) {
+		path := monitor.MonitoredId.GetSynqPath().GetPath()
+		return path, len(path) > 0
+	}))
+	// Unresolved paths are reported by the namespaces declaring them, protection only needs those which resolve.
+	resolvedPaths, err := pathsConverter.SimpleToPath(pathsToConvert)
+	if err != nil && err.Err != nil {
+		return apiError(fmt.Errorf("❌ Error resolving the paths of monitors declared with prevent_destroy: %v", err.Err))
+	}
+
+	uuidGenerator := uuid.NewUUIDGenerator(workspace)
+	changesOverview.PreventDestroy(lo.Map(protected, func(monitor *pb.MonitorDefinition, _ int) string {
+		path := monitor.MonitoredId.GetSynqPath().GetPath()
+		return uuidGenerator.GenerateMonitorUUID(
+			core.MonitorIdentity(monitor.Id, changesOverview.ConfigID, lo.CoalesceOrEmpty(resolvedPaths[path], path)),
+		)
+	}))
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/uuid"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaxDeletes(t *testing.T) {
+	limit, err := parseMaxDeletes("")
+	require.NoError(t, err)
+	assert.Nil(t, limit)
+
+	limit, err = parseMaxDeletes("10")
+	require.NoError(t, err)
+	assert.Equal(t, &deletesLimit{value: 10, flag: "10"}, limit)
+
+	limit, err = parseMaxDeletes("12.5%")
+	require.NoError(t, err)
+	assert.Equal(t, &deletesLimit{value: 12.5, percent: true, flag: "12.5%"}, limit)
+
+	for _, invalid := range []string{"-1", "1.5", "ten", "150%", "%"} {
+		_, err := parseMaxDeletes(invalid)
+		assert.Equal(t, ExitCode_Validation, exitCode(err), invalid)
+	}
+}
+
+func TestCheckDeletes(t *testing.T) {
+	service := newFakeMgmtService(
+		createMonitor("monitor1", "orders", "table1"),
+		createMonitor("monitor2", "orders", "table2"),
+		createMonitor("monitor3", "orders", "table3"),
+		createMonitor("monitor4", "orders", "table4"),
+	)
+	// Keeps monitor1 unchanged and deletes the three others.
+	changesOverview, err := service.ConfigChangesOverview([]*pb.MonitorDefinition{createMonitor("monitor1", "orders", "table1")}, nil, nil, "orders")
+	require.NoError(t, err)
+
+	limit := func(value string) *deletesLimit {
+		limit, err := parseMaxDeletes(value)
+		require.NoError(t, err)
+		return limit
+	}
+
+	assert.NoError(t, checkDeletes(changesOverview, nil, false, false))
+	assert.NoError(t, checkDeletes(changesOverview, limit("3"), false, false))
+	assert.NoError(t, checkDeletes(changesOverview, limit("75%"), true, true))
+
+	err = checkDeletes(changesOverview, limit("2"), true, true)
+	assert.Equal(t, ExitCode_BreakingChanges, exitCode(err))
+	assert.EqualError(t, err, "❌ 3 of the 4 monitors and tests of namespace orders would be deleted, more than --max-deletes 2")
+
+	err = checkDeletes(changesOverview, limit("50%"), false, false)
+	assert.Equal(t, ExitCode_BreakingChanges, exitCode(err))
+
+	err = checkDeletes(changesOverview, nil, false, true)
+	assert.Equal(t, ExitCode_BreakingChanges, exitCode(err))
+	assert.EqualError(t, err, "❌ 3 monitors and tests of namespace orders would be deleted, set --allow-deletes to delete them with --auto-confirm")
+
+	unchanged, err := service.ConfigChangesOverview(nil, nil, nil, "payments")
+	require.NoError(t, err)
+	assert.NoError(t, checkDeletes(unchanged, limit("0"), false, true))
+}
+
+func TestProtectMonitorsDeployedWithAnotherNamespace(t *testing.T) {
+	workspace := "test-workspace"
+	// The file of namespace billing is deployed with a typo in its namespace.
+	parser, err := yaml.NewVersionedParser([]byte(`version: v1beta2
+namespace: biling
+entities:
+  - id: pg::billing::invoices
+    time_partitioning_column: created_at
+    monitors:
+      - id: invoices_volume
+        type: volume
+        prevent_destroy: true
+      - id: invoices_freshness
+        type: freshness
+        expression: created_at
+`))
+	require.NoError(t, err)
+
+	uuidGenerator := uuid.NewUUIDGenerator(workspace)
+	deployed := func(id string) *pb.MonitorDefinition {
+		monitor := createMonitor(id, "billing", "pg::billing::invoices")
+		monitor.Id = uuidGenerator.GenerateMonitorUUID(monitor)
+		return monitor
+	}
+	volume, freshness := deployed("invoices_volume"), deployed("invoices_freshness")
+	service := newFakeMgmtService(volume, freshness)
+
+	changesOverview, err := service.ConfigChangesOverview(nil, nil, nil, "billing")
+	require.NoError(t, err)
+	require.Len(t, changesOverview.MonitorsToDelete, 2)
+
+	protected := protectedMonitors(map[string][]*yaml.VersionedParser{"biling": {parser}})
+	require.NoError(t, protectMonitors(staticPathConverter{}, workspace, changesOverview, protected))
+	assert.Equal(t, []string{volume.Id}, changesOverview.MonitorsProtected)
+	assert.Contains(t, changesOverview.GetBreakingChanges(), "Monitor ID: "+volume.Id)
+
+	// Protected monitors whose paths cannot be resolved fail the namespace instead of being deleted.
+	changesOverview, err = service.ConfigChangesOverview(nil, nil, nil, "billing")
+	require.NoError(t, err)
+	err = protectMonitors(failingPathConverter{}, workspace, changesOverview, protected)
+	assert.Equal(t, ExitCode_ApiFailure, exitCode(err))
+	assert.Empty(t, changesOverview.MonitorsProtected)
+}
+
+// failingPathConverter fails to resolve any path, like an unavailable API.
+type failingPathConverter struct {
+	staticPathConverter
+}
+
+func (c failingPathConverter) SimpleToPath(simple []string) (map[string]string, *paths.SimpleToPathError) {
+	return nil, &paths.SimpleToPathError{Err: errors.New("unavailable")}
+}
@@ -5,7 +5,11 @@ import (
 
 	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/getsynq/monitors_mgmt/uuid"
+	"github.com/getsynq/monitors_mgmt/yaml"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func createMonitor(id, configId, path string) *pb.MonitorDefinition {
@@ -105,8 +109,74 @@ func TestAssignAndValidateUUIDs(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			monitors := tt.monitors
-			duplicateSeen := assignAndValidateUUIDs(workspace, "default", monitors)
+			duplicateSeen := assignAndValidateUUIDs(standardOutput(), workspace, "default", monitors)
 			assert.Equal(t, tt.duplicateSeen, duplicateSeen)
 		})
 	}
 }
+
+func TestPrepareNamespaceWithMovedMonitors(t *testing.T) {
+	workspace := "test-workspace"
+	parser, err := yaml.NewVersionedParser([]byte(`version: v1beta2
+namespace: orders
+moved_from:
+  - legacy
+entities:
+  - id: pg::public::orders
+    time_partitioning_column: created_at
+    monitors:
+      - id: orders_volume
+        type: volume
+        moved_from:
+          - id: volume
+            entity: pg::public::orders_v1
+`))
+	require.NoError(t, err)
+
+	prepared, err := prepareNamespace(standardOutput(), staticPathConverter{}, workspace, "orders", []*yaml.VersionedParser{parser})
+	require.NoError(t, err)
+	monitors, moves := prepared.Monitors, prepared.Moves
+	require.Len(t, monitors, 1)
+
+	uuidGenerator := uuid.NewUUIDGenerator(workspace)
+	assert.Equal(t, mgmt.MonitorMoves{
+		monitors[0].Id: {
+			uuidGenerator.GenerateMonitorUUID(createMonitor("volume", "orders", "pg::public::orders_v1")),
+			uuidGenerator.GenerateMonitorUUID(createMonitor("orders_volume", "legacy", "pg::public::orders")),
+		},
+	}, moves)
+
+	previous := createMonitor("volume", "orders", "pg::public::orders_v1")
+	previous.Id = uuidGenerator.GenerateMonitorUUID(previous)
+	previous.Source = pb.MonitorDefinition_SOURCE_API
+	service := &fakeMgmtService{remote: map[string]*pb.MonitorDefinition{previous.Id: previous}}
+	changesOverview, err := service.ConfigChangesOverview(monitors, moves, nil, "orders")
+	require.NoError(t, err)
+	assert.Empty(t, changesOverview.MonitorsToCreate)
+	assert.Empty(t, changesOverview.MonitorsToDelete)
+	require.Len(t, changesOverview.MonitorsChangesOverview, 1)
+	assert.Equal(t, previous.Id, changesOverview.MonitorsChangesOverview[0].MonitorId)
+	assert.Equal(t, "pg::public::orders", changesOverview.MonitorsChangesOverview[0].NewDefinition.MonitoredId.GetSynqPath().GetPath())
+}
+
+func TestPrepareNamespaceWithInvalidTests(t *testing.T) {
+	// An invalid test fails the namespace, rather than planning the deletion of every deployed test.
+	parser, err := yaml.NewVersionedParser([]byte(`version: v1beta2
+namespace: orders
+entities:
+  - id: pg::public::orders
+    tests:
+      - id: orders_id
+        type: not_null
+        columns: [id]
+      - id: orders_id
+        type: unique
+        columns: [id]
+`))
+	require.NoError(t, err)
+
+	prepared, err := prepareNamespace(standardOutput(), staticPathConverter{}, "test-workspace", "orders", []*yaml.VersionedParser{parser})
+	assert.Nil(t, prepared)
+	assert.ErrorContains(t, err, "Could not convert to tests")
+	assert.Equal(t, ExitCode_Validation, exitCode(err))
+}
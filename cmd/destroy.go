@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	destroyCmd_namespaces   []string
+	destroyCmd_autoConfirm  bool
+	destroyCmd_allowDeletes bool
+	destroyCmd_dryRun       bool
+)
+
+func init() {
+	destroyCmd.Flags().StringSliceVar(&destroyCmd_namespaces, "namespace", []string{}, "Namespaces to destroy")
+	destroyCmd.Flags().BoolVar(&destroyCmd_autoConfirm, "auto-confirm", false, "Automatically confirm all prompts (skip interactive confirmations)")
+	destroyCmd.Flags().BoolVar(&destroyCmd_allowDeletes, "allow-deletes", false, "Allow deleting monitors and tests together with --auto-confirm")
+	destroyCmd.Flags().BoolVar(&destroyCmd_dryRun, "dry-run", false, "Only print what would be deleted")
+	destroyCmd.MarkFlagRequired("namespace")
+
+	rootCmd.AddCommand(destroyCmd)
+}
+
+var destroyCmd = &cobra.Command{
+	Use:   "destroy --namespace <namespace>",
+	Short: "Delete every monitor and test of a namespace",
+	Long: `Delete every monitor and test deployed from the YAML configuration of a namespace.
+
+The monitors and tests to delete are printed like a deployment would, and deleted after confirmation,
+unless --auto-confirm is set together with --allow-deletes. Monitors created in the app are never deleted.
+
+Destroy deletes monitors declared with prevent_destroy too, and is not limited by --max-deletes:
+it does not read the YAML files of the namespace.`,
+	Args: cobra.NoArgs,
+	RunE: destroyNamespaces,
+}
+
+func destroyNamespaces(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	conn, err := connectToApi(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprintf(logs, "Connected to API...\n\n")
+
+	workspace, err := fetchWorkspace(ctx, conn)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(logs, "🔍 Workspace: %s\n\n", workspace)
+	events.emit(outputEvent{Event: eventType_Workspace, Workspace: workspace})
+
+	mgmtService := mgmt.NewMgmtRemoteService(ctx, conn).WithOutput(logs)
+	results := []namespaceResult{}
+	for _, namespace := range destroyCmd_namespaces {
+		fmt.Fprintf(logs, "📋 Processing namespace '%s'\n", namespace)
+		events.emit(outputEvent{Event: eventType_Namespace, Namespace: namespace})
+
+		result := destroyNamespace(mgmtService, namespace)
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", result.Err)
+		}
+		results = append(results, result)
+	}
+
+	printNamespacesSummary(results)
+	return namespacesError(results)
+}
+
+// destroyNamespace deploys the namespace without any monitor or test, which deletes all of those it manages.
+func destroyNamespace(mgmtService mgmt.MgmtService, namespace string) namespaceResult {
+	changesOverview, err := mgmtService.ConfigChangesOverview(nil, nil, nil, namespace)
+	if err != nil {
+		return namespaceFailed(namespace, apiError(fmt.Errorf("❌ Error getting config changes overview: %v", err)))
+	}
+
+	changesOverview.Fprint(logs)
+	events.emit(outputEvent{Event: eventType_Changes, Namespace: namespace, Changes: changesOverview.Summary()})
+
+	if !changesOverview.HasChanges() {
+		return namespaceSucceeded(namespace, namespaceStatus_Unchanged)
+	}
+
+	if destroyCmd_dryRun {
+		fmt.Fprintln(logs, "🧪 Dry run, nothing deleted")
+		return namespaceSucceeded(namespace, namespaceStatus_Planned)
+	}
+
+	if err := checkDeletes(changesOverview, nil, destroyCmd_allowDeletes, destroyCmd_autoConfirm); err != nil {
+		return namespaceFailed(namespace, err)
+	}
+
+	if !destroyCmd_autoConfirm {
+		if !confirm(fmt.Sprintf("Are you sure you want to delete every monitor and test of namespace '%s'? (y/N)", namespace)) {
+			return namespaceFailed(namespace, cancelledError(errors.New("❌ Destroy cancelled")))
+		}
+	} else {
+		fmt.Fprintln(logs, "✅ Auto-confirmed destroy!")
+	}
+
+	if err := mgmtService.DeployMonitors(changesOverview); err != nil {
+		return namespaceFailed(namespace, apiError(fmt.Errorf("❌ Error deleting monitors: %v", err)))
+	}
+
+	fmt.Fprintln(logs, "✅ Destroy complete!")
+	return namespaceSucceeded(namespace, namespaceStatus_Destroyed)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"io"
+	"testing"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMgmtService computes changes against fixed remote monitors and records what gets deployed.
+type fakeMgmtService struct {
+	remote   map[string]*pb.MonitorDefinition
+	deployed []*mgmt.ChangesOverview
+}
+
+var _ mgmt.MgmtService = &fakeMgmtService{}
+
+func (s *fakeMgmtService) ConfigChangesOverview(
+	protoMonitors []*pb.MonitorDefinition,
+	moves mgmt.MonitorMoves,
+	sqlTests []*sqltestsv1.SqlTest,
+	configId string,
+) (*mgmt.ChangesOverview, error) {
+	changesOverview, err := mgmt.GenerateConfigChangesOverview(configId, protoMonitors, moves, s.remote)
+	if err != nil {
+		return nil, err
+	}
+	return changesOverview, changesOverview.AddSqlTestsChanges(sqlTests, nil)
+}
+
+func (s *fakeMgmtService) DeployMonitors(changesOverview *mgmt.ChangesOverview) error {
+	s.deployed = append(s.deployed, changesOverview)
+	return nil
+}
+
+func (s *fakeMgmtService) DetectDrift(changesOverview *mgmt.ChangesOverview) ([]string, error) {
+	return nil, nil
+}
+
+func (s *fakeMgmtService) ListMonitors(scope *mgmt.ListScope) ([]*pb.MonitorDefinition, error) {
+	return nil, nil
+}
+
+func (s *fakeMgmtService) WithOutput(out io.Writer) mgmt.MgmtService {
+	return s
+}
+
+func newFakeMgmtService(monitors ...*pb.MonitorDefinition) *fakeMgmtService {
+	remote := map[string]*pb.MonitorDefinition{}
+	for _, monitor := range monitors {
+		monitor.Source = pb.MonitorDefinition_SOURCE_API
+		remote[monitor.Id] = monitor
+	}
+	return &fakeMgmtService{remote: remote}
+}
+
+func TestDestroyNamespace(t *testing.T) {
+	newService := func() *fakeMgmtService {
+		return newFakeMgmtService(
+			createMonitor("monitor1", "orders", "table1"),
+			createMonitor("monitor2", "orders", "table2"),
+			createMonitor("monitor3", "payments", "table3"),
+		)
+	}
+	defer func() { destroyCmd_dryRun, destroyCmd_autoConfirm, destroyCmd_allowDeletes = false, false, false }()
+
+	t.Run("dry_run", func(t *testing.T) {
+		destroyCmd_dryRun, destroyCmd_autoConfirm = true, false
+		service := newService()
+
+		result := destroyNamespace(service, "orders")
+		require.NoError(t, result.Err)
+		assert.Equal(t, namespaceStatus_Planned, result.Status)
+		assert.Empty(t, service.deployed)
+	})
+
+	t.Run("auto_confirm_without_allow_deletes", func(t *testing.T) {
+		destroyCmd_dryRun, destroyCmd_autoConfirm, destroyCmd_allowDeletes = false, true, false
+		service := newService()
+
+		result := destroyNamespace(service, "orders")
+		assert.ErrorContains(t, result.Err, "set --allow-deletes")
+		assert.Equal(t, ExitCode_BreakingChanges, exitCode(result.Err))
+		assert.Empty(t, service.deployed)
+	})
+
+	t.Run("auto_confirm", func(t *testing.T) {
+		destroyCmd_dryRun, destroyCmd_autoConfirm, destroyCmd_allowDeletes = false, true, true
+		service := newService()
+
+		result := destroyNamespace(service, "orders")
+		require.NoError(t, result.Err)
+		assert.Equal(t, namespaceStatus_Destroyed, result.Status)
+		require.Len(t, service.deployed, 1)
+		assert.ElementsMatch(t, []string{"monitor1", "monitor2"}, monitorIdsOf(service.deployed[0].MonitorsToDelete))
+		assert.Empty(t, service.deployed[0].MonitorsToCreate)
+		assert.Empty(t, service.deployed[0].MonitorsChangesOverview)
+	})
+
+	t.Run("empty_namespace", func(t *testing.T) {
+		destroyCmd_dryRun, destroyCmd_autoConfirm = false, true
+		service := newService()
+
+		result := destroyNamespace(service, "unknown")
+		require.NoError(t, result.Err)
+		assert.Equal(t, namespaceStatus_Unchanged, result.Status)
+		assert.Empty(t, service.deployed)
+	})
+}
+
+func monitorIdsOf(monitors []*pb.MonitorDefinition) []string {
+	ids := []string{}
+	for _, monitor := range monitors {
+		ids = append(ids, monitor.Id)
+	}
+	return ids
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/samber/lo"
+)
+
+const skipChoice = "Skip, leave it ambiguous"
+
+// disambiguatePaths asks to choose the entity of every ambiguous path of the resolution, for --disambiguate.
+// Chosen paths are resolved to their entity, and the files may then be rewritten to monitor them by SYNQ path,
+// so that they are not ambiguous on the next deploy. Skipped paths stay ambiguous, and their namespaces report them.
+func disambiguatePaths(
+	resolution *paths.Resolution,
+	filePaths []string,
+	choose func(label string, items []string) (int, bool),
+	confirm func(label string) bool,
+) {
+	ambiguousPaths := lo.Keys(resolution.Ambiguous)
+	slices.Sort(ambiguousPaths)
+
+	fmt.Fprintf(logs, "🤔 %d monitored paths are ambiguous, choose the entity each of them monitors\n", len(ambiguousPaths))
+	chosen := map[string]string{}
+	for _, path := range ambiguousPaths {
+		candidates := resolution.Ambiguous[path]
+		index, ok := choose(fmt.Sprintf("Which entity does %s monitor?", path), append(slices.Clone(candidates), skipChoice))
+		if !ok || index >= len(candidates) {
+			continue
+		}
+		chosen[path] = candidates[index]
+		resolution.Resolved[path] = candidates[index]
+		resolution.Strategies[path] = paths.Strategy_Chosen
+		delete(resolution.Ambiguous, path)
+	}
+	if len(chosen) == 0 {
+		fmt.Fprintf(logs, "⚠️ No ambiguous path was disambiguated\n\n")
+		return
+	}
+	fmt.Fprintf(logs, "✅ Disambiguated %d of %d ambiguous paths\n", len(chosen), len(ambiguousPaths))
+
+	if !confirm("Rewrite the ids in the YAML files to the chosen SYNQ paths? (y/N)") {
+		fmt.Fprintf(logs, "📝 Not rewriting the YAML files, the paths will be ambiguous again on the next deploy\n\n")
+		return
+	}
+	for _, filePath := range filePaths {
+		rewritten, err := rewriteFilePaths(filePath, chosen)
+		if err != nil {
+			fmt.Fprintf(logs, "⚠️ %s was not rewritten: %v\n", filePath, err)
+		} else if rewritten > 0 {
+			fmt.Fprintf(logs, "✏️ Rewrote %d ids in %s\n", rewritten, filePath)
+		}
+	}
+	fmt.Fprintln(logs)
+}
+
+// rewriteFilePaths replaces the paths monitored in a file, keeping its formatting, and returns how many were replaced.
+func rewriteFilePaths(filePath string, replacements map[string]string) (int, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, err
+	}
+	rewritten, count, err := yaml.RewritePaths(content, replacements)
+	if err != nil || count == 0 {
+		return 0, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filePath, rewritten, info.Mode().Perm()); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
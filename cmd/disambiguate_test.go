@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisambiguatePaths(t *testing.T) {
+	config := `version: v1beta2
+namespace: orders
+
+entities:
+  # The orders of every region.
+  - id: public.orders
+    monitors:
+      - id: orders_volume
+        type: volume
+  - id: "analytics.events"
+    monitors:
+      - id: events_freshness
+        type: freshness
+        expression: created_at
+`
+
+	newResolution := func() *paths.Resolution {
+		resolution := paths.NewResolution()
+		resolution.Ambiguous = map[string][]string{
+			"public.orders":    {"pg::public::orders", "bq::public::orders"},
+			"analytics.events": {"bq::analytics::events", "pg::analytics::events"},
+		}
+		return resolution
+	}
+	writeConfig := func(t *testing.T) string {
+		file := filepath.Join(t.TempDir(), "orders.yaml")
+		require.NoError(t, os.WriteFile(file, []byte(config), 0o644))
+		return file
+	}
+	read := func(t *testing.T, file string) string {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("chosen_and_rewritten", func(t *testing.T) {
+		file, resolution := writeConfig(t), newResolution()
+		labels := [][]string{}
+		disambiguatePaths(resolution, []string{file}, func(label string, items []string) (int, bool) {
+			labels = append(labels, append([]string{label}, items...))
+			if label == "Which entity does public.orders monitor?" {
+				return 1, true
+			}
+			return len(items) - 1, true
+		}, func(string) bool { return true })
+
+		assert.Equal(t, [][]string{
+			{"Which entity does analytics.events monitor?", "bq::analytics::events", "pg::analytics::events", skipChoice},
+			{"Which entity does public.orders monitor?", "pg::public::orders", "bq::public::orders", skipChoice},
+		}, labels)
+		assert.Equal(t, map[string]string{"public.orders": "bq::public::orders"}, resolution.Resolved)
+		assert.Equal(t, map[string]paths.Strategy{"public.orders": paths.Strategy_Chosen}, resolution.Strategies)
+		assert.Equal(t, map[string][]string{"analytics.events": {"bq::analytics::events", "pg::analytics::events"}}, resolution.Ambiguous)
+
+		resolved, pathErr := resolution.SimpleToPath([]string{"public.orders"})
+		assert.Nil(t, pathErr)
+		assert.Equal(t, map[string]string{"public.orders": "bq::public::orders"}, resolved)
+
+		expected := `version: v1beta2
+namespace: orders
+
+entities:
+  # The orders of every region.
+  - id: bq::public::orders
+    monitors:
+      - id: orders_volume
+        type: volume
+  - id: "analytics.events"
+    monitors:
+      - id: events_freshness
+        type: freshness
+        expression: created_at
+`
+		assert.Equal(t, expected, read(t, file))
+	})
+
+	t.Run("not_rewritten", func(t *testing.T) {
+		file, resolution := writeConfig(t), newResolution()
+		disambiguatePaths(resolution, []string{file}, func(string, []string) (int, bool) {
+			return 0, true
+		}, func(string) bool { return false })
+
+		assert.Equal(t, map[string]string{
+			"public.orders":    "pg::public::orders",
+			"analytics.events": "bq::analytics::events",
+		}, resolution.Resolved)
+		assert.Empty(t, resolution.Ambiguous)
+		assert.Equal(t, config, read(t, file))
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		file, resolution := writeConfig(t), newResolution()
+		disambiguatePaths(resolution, []string{file}, func(string, []string) (int, bool) {
+			return 0, false
+		}, func(string) bool {
+			t.Fatal("nothing chosen, nothing to rewrite")
+			return false
+		})
+
+		assert.Empty(t, resolution.Resolved)
+		assert.Len(t, resolution.Ambiguous, 2)
+		assert.Equal(t, config, read(t, file))
+	})
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// Exit codes of the CLI. When several namespaces fail for different reasons, the lowest code wins.
+const (
+	ExitCode_Error           = 1
+	ExitCode_Validation      = 2
+	ExitCode_BreakingChanges = 3
+	ExitCode_ApiFailure      = 4
+	ExitCode_Cancelled       = 5
+)
+
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func validationError(err error) error {
+	return &exitError{code: ExitCode_Validation, err: err}
+}
+
+func breakingChangesError(err error) error {
+	return &exitError{code: ExitCode_BreakingChanges, err: err}
+}
+
+func apiError(err error) error {
+	return &exitError{code: ExitCode_ApiFailure, err: err}
+}
+
+func cancelledError(err error) error {
+	return &exitError{code: ExitCode_Cancelled, err: err}
+}
+
+// simpleToPathError fails on the API when resolution could not run, and on the configuration otherwise.
+func simpleToPathError(err *paths.SimpleToPathError) error {
+	if err.Err != nil {
+		return apiError(errors.New(err.Error()))
+	}
+	return validationError(errors.New(err.Error()))
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitCode_Error
+}
+
+// mostSevereError returns the error with the lowest exit code, keeping the first one on ties.
+func mostSevereError(errs []error) error {
+	errs = lo.Compact(errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return lo.MinBy(errs, func(a, b error) bool {
+		return exitCode(a) < exitCode(b)
+	})
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, exitCode(nil))
+	assert.Equal(t, ExitCode_Error, exitCode(errors.New("boom")))
+	assert.Equal(t, ExitCode_Validation, exitCode(validationError(errors.New("invalid"))))
+	assert.Equal(t, ExitCode_BreakingChanges, exitCode(breakingChangesError(errors.New("breaking"))))
+	assert.Equal(t, ExitCode_ApiFailure, exitCode(apiError(errors.New("unavailable"))))
+	assert.Equal(t, ExitCode_Cancelled, exitCode(cancelledError(errors.New("cancelled"))))
+
+	// The code survives wrapping
+	assert.Equal(t, ExitCode_ApiFailure, exitCode(fmt.Errorf("deploying: %w", apiError(errors.New("unavailable")))))
+}
+
+func TestNamespacesError(t *testing.T) {
+	assert.NoError(t, namespacesError([]namespaceResult{
+		namespaceSucceeded("a", namespaceStatus_Deployed),
+		namespaceSucceeded("b", namespaceStatus_Skipped),
+	}))
+
+	err := namespacesError([]namespaceResult{
+		namespaceSucceeded("a", namespaceStatus_Deployed),
+		namespaceFailed("b", cancelledError(errors.New("cancelled"))),
+		namespaceFailed("c", apiError(errors.New("unavailable"))),
+		namespaceFailed("d", breakingChangesError(errors.New("breaking"))),
+	})
+	require.Error(t, err)
+	assert.Equal(t, "❌ 3 of 4 namespaces failed", err.Error())
+	assert.Equal(t, ExitCode_BreakingChanges, exitCode(err))
+}
+
+func TestGetHostAndPort(t *testing.T) {
+	host, port, err := getHostAndPort("https://developer.synq.io")
+	require.NoError(t, err)
+	assert.Equal(t, "developer.synq.io", host)
+	assert.Equal(t, "443", port)
+
+	_, port, err = getHostAndPort("http://localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, "8080", port)
+
+	_, _, err = getHostAndPort("ftp://developer.synq.io")
+	assert.Error(t, err)
+}
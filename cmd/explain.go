@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pathsCmd.AddCommand(pathsResolveCmd)
+}
+
+var pathsResolveCmd = &cobra.Command{
+	Use:   "resolve <id>...",
+	Short: "Explain how monitored ids resolve to SYNQ paths",
+	Long: `Resolve monitored ids exactly like deploy, and print how each one was resolved: whether it matched
+an entity as a SYNQ path, the candidates of the DB coordinate it was read as otherwise, their entity types,
+and why each rejected candidate cannot be monitored.
+
+Unresolved ids come with the closest monitorable entities, which deploy also suggests in its errors.
+The command fails when any id does not resolve.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: explainPaths,
+}
+
+func explainPaths(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	conn, err := connectToApi(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprintf(logs, "Connected to API...\n\n")
+
+	workspace, err := fetchWorkspace(ctx, conn)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(logs, "🔍 Workspace: %s\n\n", workspace)
+	events.emit(outputEvent{Event: eventType_Workspace, Workspace: workspace})
+
+	lock, err := readLock()
+	if err != nil {
+		return err
+	}
+
+	explanations, err := paths.NewPathExplainer(ctx, conn).ExplainPaths(args)
+	if err != nil {
+		return apiError(fmt.Errorf("❌ Error resolving monitored paths: %v", err))
+	}
+
+	failed := 0
+	for _, explanation := range explanations {
+		for _, line := range describeExplanation(explanation) {
+			fmt.Fprintln(logs, line)
+		}
+		if lock != nil && lock.Workspace == workspace {
+			if locked, ok := lock.Paths[explanation.Path]; ok {
+				fmt.Fprintf(logs, "   🔒 locked to %s in %s, which deploy uses instead\n", locked, pathsCmd_lockfile)
+			}
+		}
+		fmt.Fprintln(logs)
+		events.emit(outputEvent{Event: eventType_Path, Path: explanation})
+
+		if explanation.Resolved == "" {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return validationError(fmt.Errorf("❌ %d of the %d ids do not resolve", failed, len(explanations)))
+	}
+	return nil
+}
+
+// describeExplanation renders the resolution chain of a path, one step per line.
+func describeExplanation(explanation *paths.Explanation) []string {
+	lines := []string{fmt.Sprintf("🔎 %s", explanation.Path)}
+	candidate := func(candidate paths.Candidate) string {
+		line := candidate.SynqPath
+		if candidate.EntityType != "" {
+			line += fmt.Sprintf(" (%s)", candidate.EntityType)
+		}
+		// A single candidate is resolved without checking its type, like deploy does.
+		if candidate.Rejected != "" && candidate.SynqPath != explanation.Resolved {
+			return fmt.Sprintf("✗ %s: %s", line, candidate.Rejected)
+		}
+		return "✓ " + line
+	}
+
+	if explanation.Entity != nil {
+		lines = append(lines, "   entity "+candidate(*explanation.Entity))
+	} else {
+		lines = append(lines, fmt.Sprintf("   ✗ no entity with SYNQ path %s", paths.PathWithColons(explanation.Path)))
+	}
+
+	if explanation.Strategy != paths.Strategy_SynqPath && explanation.Strategy != paths.Strategy_DotNotation {
+		if len(explanation.Candidates) == 0 {
+			lines = append(lines, "   ✗ no DB coordinate matches it")
+		} else {
+			lines = append(lines, "   DB coordinate candidates:")
+			for _, coordinateCandidate := range explanation.Candidates {
+				lines = append(lines, "     "+candidate(coordinateCandidate))
+			}
+		}
+	}
+
+	switch {
+	case explanation.Resolved != "":
+		lines = append(lines, fmt.Sprintf("   ✅ resolves to %s from %s", explanation.Resolved, strategyNames[explanation.Strategy]))
+	case len(explanation.Ambiguous) > 0:
+		lines = append(lines, fmt.Sprintf(
+			"   ❌ ambiguous between %s, use one of them as id", strings.Join(explanation.Ambiguous, ", "),
+		))
+	case len(explanation.Suggestions) > 0:
+		lines = append(lines, fmt.Sprintf("   ❌ unresolved, did you mean %s?", strings.Join(explanation.Suggestions, " or ")))
+	default:
+		lines = append(lines, "   ❌ unresolved, no similar entity found")
+	}
+	return lines
+}
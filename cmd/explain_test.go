@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeExplanation(t *testing.T) {
+	assert.Equal(t, []string{
+		"🔎 public.orders",
+		"   ✗ no entity with SYNQ path public::orders",
+		"   DB coordinate candidates:",
+		"     ✓ pg::public::orders (postgres_table)",
+		"     ✗ dbt::public::orders (dbt_model): entity type cannot be monitored",
+		"   ✅ resolves to pg::public::orders from DB coordinates",
+	}, describeExplanation(&paths.Explanation{
+		Path:     "public.orders",
+		Resolved: "pg::public::orders",
+		Strategy: paths.Strategy_Coordinate,
+		Candidates: []paths.Candidate{
+			{SynqPath: "pg::public::orders", EntityType: "postgres_table"},
+			{SynqPath: "dbt::public::orders", EntityType: "dbt_model", Rejected: "entity type cannot be monitored"},
+		},
+	}))
+
+	assert.Equal(t, []string{
+		"🔎 pg::public::orders",
+		"   entity ✓ pg::public::orders (postgres_table)",
+		"   ✅ resolves to pg::public::orders from SYNQ paths",
+	}, describeExplanation(&paths.Explanation{
+		Path:     "pg::public::orders",
+		Resolved: "pg::public::orders",
+		Strategy: paths.Strategy_SynqPath,
+		Entity:   &paths.Candidate{SynqPath: "pg::public::orders", EntityType: "postgres_table"},
+	}))
+
+	assert.Equal(t, []string{
+		"🔎 analytics.events",
+		"   entity ✗ analytics::events (dbt_model): entity type cannot be monitored",
+		"   DB coordinate candidates:",
+		"     ✓ bq::analytics::events (bq_table)",
+		"     ✓ pg::analytics::events (postgres_table)",
+		"   ❌ ambiguous between bq::analytics::events, pg::analytics::events, use one of them as id",
+	}, describeExplanation(&paths.Explanation{
+		Path:   "analytics.events",
+		Entity: &paths.Candidate{SynqPath: "analytics::events", EntityType: "dbt_model", Rejected: "entity type cannot be monitored"},
+		Candidates: []paths.Candidate{
+			{SynqPath: "bq::analytics::events", EntityType: "bq_table"},
+			{SynqPath: "pg::analytics::events", EntityType: "postgres_table"},
+		},
+		Ambiguous: []string{"bq::analytics::events", "pg::analytics::events"},
+	}))
+
+	assert.Equal(t, []string{
+		"🔎 public.ordrs",
+		"   ✗ no entity with SYNQ path public::ordrs",
+		"   ✗ no DB coordinate matches it",
+		"   ❌ unresolved, did you mean pg::public::orders or pg::public::order?",
+	}, describeExplanation(&paths.Explanation{Path: "public.ordrs", Suggestions: []string{"pg::public::orders", "pg::public::order"}}))
+
+	assert.Equal(t, "   ❌ unresolved, no similar entity found", describeExplanation(&paths.Explanation{Path: "shipments"})[3])
+}
@@ -5,11 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 
-	iamv1grpc "buf.build/gen/go/getsynq/api/grpc/go/synq/auth/iam/v1/iamv1grpc"
-	iamv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/auth/iam/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/mgmt"
 	"github.com/getsynq/monitors_mgmt/paths"
 	"github.com/getsynq/monitors_mgmt/yaml"
@@ -22,20 +23,38 @@ import (
 
 var (
 	exportCmd_namespace      string
+	exportCmd_formatVersion  string
 	exportCmd_integrationIds []string
 	exportCmd_monitoredPaths []string
 	exportCmd_monitorIds     []string
 	exportCmd_source         string
 	exportCmd_validSources   = []string{"app", "api", "all"}
+	exportCmd_splitBy        string
+	exportCmd_validSplitBy   = []string{exportSplitBy_Entity, exportSplitBy_Database, exportSplitBy_Schema, exportSplitBy_Integration}
+	exportCmd_merge          bool
+	exportCmd_onConflict     string
+)
+
+const (
+	exportSplitBy_Entity      = "entity"
+	exportSplitBy_Database    = "database"
+	exportSplitBy_Schema      = "schema"
+	exportSplitBy_Integration = "integration"
 )
 
 var exportCmd = &cobra.Command{
 	Use:   "export [output-file]",
 	Short: "Export custom monitors to YAML configuration",
 	Long: `Export custom monitors as YAML.
-Optionally provide scope to limit the monitors exported.`,
+Optionally provide scope to limit the monitors exported.
+
+With --split-by, the output is a directory receiving one file per entity, database, schema or integration.
+All files share the same namespace and are deployed together as that namespace.
+
+With --merge, the output file is an existing configuration: monitors missing from it are added, and those
+deployed differently are reported or, with --on-conflict=update, updated. Its comments, order and anchors are kept.`,
 	Args: cobra.ExactArgs(1),
-	Run:  exportMonitors,
+	RunE: exportMonitors,
 }
 
 func init() {
@@ -48,204 +67,423 @@ func init() {
 	exportCmd.Flags().
 		StringVar(&exportCmd_source, "source", exportCmd_validSources[0], fmt.Sprintf("Limit exported monitors by source. One of %+v. Defaults to \"%s\". AND'ed with other scopes.", exportCmd_validSources, exportCmd_validSources[0]))
 	exportCmd.Flags().StringVar(&exportCmd_namespace, "namespace", "", "Namespace for generated YAML config")
+	exportCmd.Flags().
+		StringVar(&exportCmd_formatVersion, "format-version", core.Version_DefaultGenerator, fmt.Sprintf("Version of the generated YAML config. One of %+v.", []string{core.Version_V1Beta1, core.Version_V1Beta2}))
+	exportCmd.Flags().
+		StringVar(&exportCmd_splitBy, "split-by", "", fmt.Sprintf("Write one file per group into the output directory. One of %+v.", exportCmd_validSplitBy))
+	exportCmd.Flags().BoolVar(&exportCmd_merge, "merge", false, "Merge the exported monitors into the existing output file")
+	exportCmd.Flags().
+		StringVar(&exportCmd_onConflict, "on-conflict", string(yaml.MergeStrategy_Report), fmt.Sprintf("What --merge does with monitors of the file deployed differently. One of %+v.", yaml.MergeStrategies))
 
 	rootCmd.AddCommand(exportCmd)
 }
 
-func exportMonitors(cmd *cobra.Command, args []string) {
+func exportMonitors(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	yamlFilePath := args[0]
+	outputPath := args[0]
 
-	// Check if file exists
-	if _, err := os.Stat(yamlFilePath); !os.IsNotExist(err) {
-		exitWithError(
-			fmt.Errorf("❌ Error: File '%s' exists. Please provide a fresh path or remove the existing file before exporting.\n", yamlFilePath),
-		)
+	if exportCmd_splitBy != "" && !slices.Contains(exportCmd_validSplitBy, exportCmd_splitBy) {
+		return validationError(fmt.Errorf("❌ Invalid split \"%s\". Must be one of %+v.", exportCmd_splitBy, exportCmd_validSplitBy))
 	}
 
-	// Create file directory if it does not exist
-	if err := os.MkdirAll(filepath.Dir(yamlFilePath), 0o770); err != nil {
-		exitWithError(fmt.Errorf("❌ Error: Unable to create directory for export file '%s'.\n", yamlFilePath))
+	if exportCmd_merge {
+		if exportCmd_splitBy != "" {
+			return validationError(errors.New("❌ --merge and --split-by cannot be used together."))
+		}
+		if !slices.Contains(yaml.MergeStrategies, yaml.MergeStrategy(exportCmd_onConflict)) {
+			return validationError(fmt.Errorf("❌ Invalid conflict strategy \"%s\". Must be one of %+v.", exportCmd_onConflict, yaml.MergeStrategies))
+		}
+		if _, err := os.Stat(outputPath); err != nil {
+			return validationError(fmt.Errorf("❌ Error: Unable to merge into '%s': %v", outputPath, err))
+		}
+	} else if exportCmd_splitBy == "" {
+		// Check if file exists
+		if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+			return validationError(
+				fmt.Errorf("❌ Error: File '%s' exists. Please provide a fresh path or remove the existing file before exporting.", outputPath),
+			)
+		}
+
+		// Create file directory if it does not exist
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0o770); err != nil {
+			return fmt.Errorf("❌ Error: Unable to create directory for export file '%s'.", outputPath)
+		}
+	} else if info, err := os.Stat(outputPath); err == nil && !info.IsDir() {
+		return validationError(fmt.Errorf("❌ Error: '%s' is a file. Please provide a directory to split the export into.", outputPath))
 	}
 
 	conn, err := connectToApi(ctx)
 	if err != nil {
-		exitWithError(err)
+		return err
 	}
 	defer conn.Close()
-	fmt.Printf("Connected to API...\n\n")
+	fmt.Fprintf(logs, "Connected to API...\n\n")
 
-	iamApi := iamv1grpc.NewIamServiceClient(conn)
-	iamResponse, err := iamApi.Iam(ctx, &iamv1.IamRequest{})
+	workspace, err := fetchWorkspace(ctx, conn)
 	if err != nil {
-		exitWithError(err)
+		return err
 	}
-	workspace := iamResponse.Workspace
-	fmt.Printf("🔍 Workspace: %s\nLooking for exportable monitors\n\n", workspace)
+	fmt.Fprintf(logs, "🔍 Workspace: %s\nLooking for exportable monitors\n\n", workspace)
+	events.emit(outputEvent{Event: eventType_Workspace, Workspace: workspace})
 
 	// Initialize Services
-	mgmtService := mgmt.NewMgmtRemoteService(ctx, conn)
-	pathsConverter := paths.NewPathConverter(ctx, conn)
+	mgmtService := mgmt.NewMgmtRemoteService(ctx, conn).WithOutput(logs)
+	pathsConverter := newPathConverter(ctx, conn, workspace)
 
 	// Fetch
-	monitors, err := mgmtService.ListMonitors(createListScope(pathsConverter))
+	scope := exportCmdScope()
+	listScope, err := createListScope(pathsConverter, scope)
+	if err != nil {
+		return err
+	}
+	monitors, err := mgmtService.ListMonitors(listScope)
 	if err != nil {
-		exitWithError(fmt.Errorf("❌ Error getting monitors: %v", err))
+		return apiError(fmt.Errorf("❌ Error getting monitors: %v", err))
 	}
 	if len(monitors) == 0 {
-		exitWithError(fmt.Errorf("❌ No monitors found for the given scope: %+v", exportScopeStr()))
+		return fmt.Errorf("❌ No monitors found for the given scope: %+v", scope)
 	}
 
-	fmt.Printf("\n✅ Found %d monitors. Exporting...\n", len(monitors))
+	fmt.Fprintf(logs, "\n✅ Found %d monitors. Exporting...\n", len(monitors))
+
+	if exportCmd_merge {
+		return mergeExport(pathsConverter, workspace, outputPath, exportCmd_namespace, yaml.MergeStrategy(exportCmd_onConflict), monitors)
+	}
+
+	// Group
+	monitorsByFile := map[string][]*pb.MonitorDefinition{outputPath: monitors}
+	if exportCmd_splitBy != "" {
+		monitorsByFile = splitExport(outputPath, exportCmd_splitBy, monitors)
+		for _, filePath := range sortedKeys(monitorsByFile) {
+			if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+				return validationError(
+					fmt.Errorf("❌ Error: File '%s' exists. Please provide a fresh directory or remove the existing files before exporting.", filePath),
+				)
+			}
+		}
+		if err := os.MkdirAll(outputPath, 0o770); err != nil {
+			return fmt.Errorf("❌ Error: Unable to create export directory '%s'.", outputPath)
+		}
+		fmt.Fprintf(logs, "Splitting by %s into %d files of namespace '%s'...\n", exportCmd_splitBy, len(monitorsByFile), exportCmd_namespace)
+	}
 
+	// Convert every file before writing any, so that a failed export leaves no partial output
+	contentByFile := map[string][]byte{}
+	for _, filePath := range sortedKeys(monitorsByFile) {
+		yamlBytes, err := exportYaml(pathsConverter, workspace, scope, exportCmd_formatVersion, exportCmd_namespace, monitorsByFile[filePath])
+		if err != nil {
+			return err
+		}
+		contentByFile[filePath] = yamlBytes
+	}
+	fmt.Fprintln(logs, "✅ Parse test completed for generated YAML...")
+
+	// Write to files
+	for _, filePath := range sortedKeys(contentByFile) {
+		if err := os.WriteFile(filePath, contentByFile[filePath], 0o644); err != nil {
+			return fmt.Errorf("❌ Error writing YAML: %v", err)
+		}
+		if exportCmd_splitBy != "" {
+			fmt.Fprintf(logs, "  📄 %s: %d monitors\n", filePath, len(monitorsByFile[filePath]))
+		}
+		events.emit(outputEvent{Event: eventType_Export, Namespace: exportCmd_namespace, File: filePath, Monitors: len(monitorsByFile[filePath])})
+	}
+
+	fmt.Fprintln(logs, "✅ Export complete!")
+	return nil
+}
+
+// exportYaml generates the YAML of the given monitors, simplifies their paths
+// and checks that it parses back to every one of them.
+func exportYaml(
+	pathsConverter paths.PathConverter,
+	workspace string,
+	scope exportScope,
+	version string,
+	namespace string,
+	monitors []*pb.MonitorDefinition,
+) ([]byte, error) {
 	// Convert
-	version := core.Version_DefaultGenerator
-	generator, err := yaml.NewVersionedGenerator(version, exportCmd_namespace, monitors)
+	generator, err := yaml.NewVersionedGenerator(version, namespace, monitors, nil)
 	if err != nil {
-		exitWithError(fmt.Errorf("❌ Error creating generator: %v", err))
+		return nil, fmt.Errorf("❌ Error creating generator: %v", err)
 	}
 
 	yamlBytes, err := generator.GenerateYAML()
 	if err != nil {
-		exitWithError(fmt.Errorf("❌ Conversion errors found: %s\n", err.Error()))
+		return nil, fmt.Errorf("❌ Conversion errors found: %s", err.Error())
 	}
 
 	// Simplify monitored paths
 	yamlBytes, err = simplifyPaths(pathsConverter, yamlBytes)
 	if err != nil {
-		exitWithError(fmt.Errorf("❌ Error simplifying monitored paths: %w", err))
+		return nil, apiError(fmt.Errorf("❌ Error simplifying monitored paths: %w", err))
 	}
 
 	// Parse to test validity
-	yamlParser, err := yaml.NewVersionedParser(yamlBytes)
+	yamlParser, err := yaml.NewVersionedParserForVersion(generator.GetVersion(), yamlBytes)
 	if err != nil {
-		exitWithError(fmt.Errorf("❌ Error parsing generated YAML: %v", err))
+		return nil, fmt.Errorf("❌ Error parsing generated YAML: %v", err)
 	}
-	_, err = yamlParser.ConvertToMonitorDefinitions()
+	parsedMonitors, err := yamlParser.ConvertToMonitorDefinitions()
 	if err != nil {
-		exitWithError(fmt.Errorf("❌ Conversion errors found while parsing generated YAML: %s\n", err.Error()))
+		return nil, fmt.Errorf("❌ Conversion errors found while parsing generated YAML: %s", err.Error())
+	}
+	if len(parsedMonitors) != len(monitors) {
+		return nil, fmt.Errorf("❌ Generated YAML contains %d monitors, %d were exported", len(parsedMonitors), len(monitors))
 	}
-	fmt.Println("✅ Parse test completed for generated YAML...")
 
-	// Write to file
-	f, err := os.OpenFile(yamlFilePath, os.O_RDWR|os.O_CREATE, 0o644)
-	if err != nil {
-		exitWithError(err)
+	return append([]byte(exportHeader(workspace, scope, time.Now())), yamlBytes...), nil
+}
+
+// splitExport groups monitors into one file per group of the output directory. Groups are read from
+// the SYNQ path of the monitored entity, laid out as integration::[database::]schema::table.
+//
+// Groups whose file names collide, like a::b and a.b, are numbered in the order of their names, like a.b_2.yaml,
+// so that no group overwrites another.
+func splitExport(outputDir string, splitBy string, monitors []*pb.MonitorDefinition) map[string][]*pb.MonitorDefinition {
+	groups := lo.GroupBy(monitors, func(monitor *pb.MonitorDefinition) string {
+		return exportGroup(splitBy, monitor.MonitoredId.GetSynqPath().GetPath())
+	})
+
+	monitorsByFile := map[string][]*pb.MonitorDefinition{}
+	for _, group := range sortedKeys(groups) {
+		name := exportFileName(group)
+		for i := 2; monitorsByFile[filepath.Join(outputDir, name)] != nil; i++ {
+			name = fmt.Sprintf("%s_%d.yaml", strings.TrimSuffix(exportFileName(group), ".yaml"), i)
+		}
+		monitorsByFile[filepath.Join(outputDir, name)] = groups[group]
 	}
-	defer f.Close()
+	return monitorsByFile
+}
 
-	if _, err := f.Write(yamlBytes); err != nil {
-		exitWithError(fmt.Errorf("❌ Error writing YAML: %v", err))
+func exportGroup(splitBy string, synqPath string) string {
+	segments := strings.Split(synqPath, "::")
+	switch splitBy {
+	case exportSplitBy_Integration:
+		return segments[0]
+	case exportSplitBy_Schema:
+		if len(segments) > 2 {
+			return strings.Join(segments[:len(segments)-1], "::")
+		}
+	case exportSplitBy_Database:
+		// Without a database level, as in ClickHouse, the schema is the database
+		if len(segments) > 3 {
+			return strings.Join(segments[:len(segments)-2], "::")
+		}
+		if len(segments) > 2 {
+			return strings.Join(segments[:len(segments)-1], "::")
+		}
 	}
+	return synqPath
+}
 
-	fmt.Println("✅ Export complete!")
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func exportFileName(group string) string {
+	name := unsafeFileNameChars.ReplaceAllString(strings.ReplaceAll(group, "::", "."), "_")
+	if name == "" {
+		name = "unknown"
+	}
+	return name + ".yaml"
 }
 
-func simplifyPaths(pathsConverter paths.PathConverter, yamlBytes []byte) ([]byte, error) {
-	var config map[string]interface{}
-	err := goyaml.Unmarshal(yamlBytes, &config)
+// mergeExport merges the exported monitors into an existing file, matching them on the UUIDs the file generates.
+// When set, the namespace must be the one of the file.
+func mergeExport(
+	pathsConverter paths.PathConverter,
+	workspace string,
+	filePath string,
+	namespace string,
+	strategy yaml.MergeStrategy,
+	monitors []*pb.MonitorDefinition,
+) error {
+	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+		return err
+	}
+	parser, err := yaml.NewVersionedParser(content)
+	if err != nil {
+		return validationError(fmt.Errorf("❌ Error parsing '%s': %v", filePath, err))
+	}
+	if namespace != "" && namespace != parser.GetConfigID() {
+		return validationError(fmt.Errorf("❌ '%s' belongs to namespace '%s', not '%s'.", filePath, parser.GetConfigID(), namespace))
+	}
+	localMonitors, err := parser.ConvertToMonitorDefinitions()
+	if err != nil {
+		return validationError(fmt.Errorf("❌ Could not convert '%s' to monitor definitions: %v", filePath, err))
 	}
 
-	monitors, ok := config["monitors"].([]interface{})
-	if !ok {
-		return yamlBytes, nil
+	fmt.Fprintf(logs, "Merging into '%s' of namespace '%s'...\n", filePath, parser.GetConfigID())
+	localIdentities := slices.Concat(localMonitors, identitiesToResolve(parser.ConvertToMonitorMoves()))
+	localPaths := lo.Uniq(lo.Map(localIdentities, func(monitor *pb.MonitorDefinition, _ int) string {
+		return monitor.MonitoredId.GetSynqPath().GetPath()
+	}))
+	resolvedPaths, resolveErr := pathsConverter.SimpleToPath(localPaths)
+	if resolveErr != nil && resolveErr.HasErrors() {
+		return simpleToPathError(resolveErr)
+	}
+
+	resolved := lo.Values(resolvedPaths)
+	remotePaths := lo.Filter(lo.Uniq(lo.Map(monitors, func(monitor *pb.MonitorDefinition, _ int) string {
+		return monitor.MonitoredId.GetSynqPath().GetPath()
+	})), func(path string, _ int) bool { return !slices.Contains(resolved, path) })
+	simplePaths, err := pathsConverter.PathToSimple(remotePaths)
+	if err != nil {
+		return apiError(fmt.Errorf("❌ Error simplifying monitored paths: %w", err))
 	}
 
-	pathsToSimplify := []string{}
-	for _, m := range monitors {
-		monitor, ok := m.(map[string]interface{})
-		if !ok {
-			continue
+	merge, err := yaml.MergeMonitors(content, monitors, yaml.MergeOptions{
+		Workspace:     workspace,
+		Strategy:      strategy,
+		ResolvedPaths: resolvedPaths,
+		SimplePaths:   simplePaths,
+	})
+	if err != nil {
+		return fmt.Errorf("❌ Error merging into '%s': %v", filePath, err)
+	}
+
+	for _, conflict := range merge.Conflicts {
+		fields := ""
+		if len(conflict.Fields) > 0 {
+			fields = fmt.Sprintf(" (%s)", strings.Join(conflict.Fields, ", "))
 		}
+		fmt.Fprintf(logs, "  ⚠️  %s on %s: %s%s\n", conflict.Id, conflict.Entity, conflict.Reason, fields)
+	}
 
-		if monitoredID, ok := monitor["monitored_id"].(string); ok && len(monitoredID) > 0 {
-			pathsToSimplify = append(pathsToSimplify, monitoredID)
+	if merge.HasChanges() {
+		if err := os.WriteFile(filePath, merge.Content, 0o644); err != nil {
+			return fmt.Errorf("❌ Error writing YAML: %v", err)
 		}
-		if monitoredIDs, ok := monitor["monitored_ids"].([]interface{}); ok {
-			for _, id := range monitoredIDs {
-				if idStr, ok := id.(string); ok {
-					pathsToSimplify = append(pathsToSimplify, idStr)
-				}
-			}
+	}
+
+	fmt.Fprintf(logs,
+		"✅ Merge complete! %d added, %d updated, %d unchanged, %d conflicts\n",
+		merge.Added, merge.Updated, merge.Unchanged, len(merge.Conflicts),
+	)
+	events.emit(outputEvent{Event: eventType_Export, Namespace: merge.Namespace, File: filePath, Monitors: merge.Added + merge.Updated})
+	return nil
+}
+
+// simplifyPaths replaces the SYNQ paths of the generated config with their simple form,
+// in the monitored_id(s) of v1beta1 monitors and in the id of v1beta2 entities.
+// The config is edited as YAML nodes, so that the order of the generator and any comment are kept.
+func simplifyPaths(pathsConverter paths.PathConverter, yamlBytes []byte) ([]byte, error) {
+	var document goyaml.Node
+	if err := goyaml.Unmarshal(yamlBytes, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+	if len(document.Content) == 0 {
+		return yamlBytes, nil
+	}
+	root := document.Content[0]
+
+	pathNodes := []*goyaml.Node{}
+	for _, monitor := range core.SequenceItems(core.MappingValue(root, "monitors")) {
+		if monitoredID := core.MappingValue(monitor, "monitored_id"); monitoredID != nil {
+			pathNodes = append(pathNodes, monitoredID)
+		}
+		pathNodes = append(pathNodes, core.SequenceItems(core.MappingValue(monitor, "monitored_ids"))...)
+	}
+	for _, entity := range core.SequenceItems(core.MappingValue(root, "entities")) {
+		if entityID := core.MappingValue(entity, "id"); entityID != nil {
+			pathNodes = append(pathNodes, entityID)
 		}
 	}
+	pathNodes = lo.Filter(pathNodes, func(node *goyaml.Node, _ int) bool {
+		return node.Kind == goyaml.ScalarNode && len(node.Value) > 0
+	})
+	if len(pathNodes) == 0 {
+		return yamlBytes, nil
+	}
 
-	simplifiedPaths, err := pathsConverter.PathToSimple(lo.Uniq(pathsToSimplify))
+	simplifiedPaths, err := pathsConverter.PathToSimple(lo.Uniq(lo.Map(pathNodes, func(node *goyaml.Node, _ int) string {
+		return node.Value
+	})))
 	if err != nil {
 		return nil, err
 	}
 
-	for _, m := range monitors {
-		monitor, ok := m.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		if monitoredID, ok := monitor["monitored_id"].(string); ok && len(monitoredID) > 0 {
-			if path, ok := simplifiedPaths[monitoredID]; ok && len(path) > 0 {
-				monitor["monitored_id"] = path
-			}
-		}
-		if monitoredIDs, ok := monitor["monitored_ids"].([]interface{}); ok {
-			for j, id := range monitoredIDs {
-				if idStr, ok := id.(string); ok {
-					if path, ok := simplifiedPaths[idStr]; ok && len(path) > 0 {
-						monitoredIDs[j] = path
-					}
-				}
-			}
+	for _, node := range pathNodes {
+		if path, ok := simplifiedPaths[node.Value]; ok && len(path) > 0 {
+			node.Value = path
 		}
 	}
 
-	return goyaml.Marshal(config)
+	return core.MarshalDocument(&document, core.DetectStyle(yamlBytes))
+}
+
+// exportHeader describes where an exported file comes from, and points editors to the schema.
+func exportHeader(workspace string, scope exportScope, exportedAt time.Time) string {
+	return strings.Join([]string{
+		"# yaml-language-server: $schema=" + schemaURL,
+		fmt.Sprintf("# Exported from workspace %s on %s", workspace, exportedAt.UTC().Format(time.RFC3339)),
+		"# Scope: " + scope.String(),
+		"",
+	}, "\n")
+}
+
+// exportScope selects the monitors listed by export and adopt, from their scope flags.
+type exportScope struct {
+	source         string
+	integrationIds []string
+	monitoredPaths []string
+	monitorIds     []string
 }
 
-func createListScope(pathsConverter paths.PathConverter) *mgmt.ListScope {
+func exportCmdScope() exportScope {
+	return exportScope{
+		source:         exportCmd_source,
+		integrationIds: exportCmd_integrationIds,
+		monitoredPaths: exportCmd_monitoredPaths,
+		monitorIds:     exportCmd_monitorIds,
+	}
+}
+
+func createListScope(pathsConverter paths.PathConverter, scope exportScope) (*mgmt.ListScope, error) {
 	integrationIds := []string{}
-	for _, integrationId := range exportCmd_integrationIds {
+	for _, integrationId := range scope.integrationIds {
 		integrationIds = lo.Uniq(strings.Split(integrationId, ","))
 	}
 
 	monitoredPaths := []string{}
-	for _, monitoredPath := range exportCmd_monitoredPaths {
+	for _, monitoredPath := range scope.monitoredPaths {
 		monitoredPaths = lo.Uniq(strings.Split(monitoredPath, ","))
 		converted, err := pathsConverter.SimpleToPath(monitoredPaths)
 		if err != nil && err.HasErrors() {
-			exitWithError(errors.New(err.Error()))
+			return nil, simpleToPathError(err)
 		}
 		monitoredPaths = lo.Values(converted)
 	}
 
 	monitorIds := []string{}
-	for _, monitorId := range exportCmd_monitorIds {
+	for _, monitorId := range scope.monitorIds {
 		monitorIds = lo.Uniq(strings.Split(monitorId, ","))
 	}
 
-	source := strings.ToLower(exportCmd_source)
+	source := strings.ToLower(scope.source)
 	if !slices.Contains(exportCmd_validSources, source) {
-		exitWithError(fmt.Errorf("❌ Invalid source \"%s\". Must be one of %+v.", source, exportCmd_validSources))
+		return nil, validationError(fmt.Errorf("❌ Invalid source \"%s\". Must be one of %+v.", source, exportCmd_validSources))
 	}
 
 	return &mgmt.ListScope{
 		IntegrationIds: integrationIds,
 		MonitoredPaths: monitoredPaths,
 		MonitorIds:     monitorIds,
-		Source:         exportCmd_source,
-	}
+		Source:         scope.source,
+	}, nil
 }
 
-func exportScopeStr() string {
-	scope := "source=" + exportCmd_source
-	if len(exportCmd_integrationIds) > 0 {
-		scope += fmt.Sprintf(", integration=%+v", exportCmd_integrationIds)
+func (s exportScope) String() string {
+	scope := "source=" + s.source
+	if len(s.integrationIds) > 0 {
+		scope += fmt.Sprintf(", integration=%+v", s.integrationIds)
 	}
-	if len(exportCmd_monitoredPaths) > 0 {
-		scope += fmt.Sprintf(", monitored=%+v", exportCmd_monitoredPaths)
+	if len(s.monitoredPaths) > 0 {
+		scope += fmt.Sprintf(", monitored=%+v", s.monitoredPaths)
 	}
-	if len(exportCmd_monitorIds) > 0 {
-		scope += fmt.Sprintf(", monitor=%+v", exportCmd_monitorIds)
+	if len(s.monitorIds) > 0 {
+		scope += fmt.Sprintf(", monitor=%+v", s.monitorIds)
 	}
 	return scope
 }
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticPathConverter map[string]string
+
+func (c staticPathConverter) SimpleToPath(simple []string) (map[string]string, *paths.SimpleToPathError) {
+	return nil, nil
+}
+
+func (c staticPathConverter) ResolvePaths(simple []string) (*paths.Resolution, error) {
+	return paths.NewResolution(), nil
+}
+
+func (c staticPathConverter) SuggestPaths(unresolved []string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+func (c staticPathConverter) PathToSimple(paths []string) (map[string]string, error) {
+	simplified := map[string]string{}
+	for _, path := range paths {
+		if simple, ok := c[path]; ok {
+			simplified[path] = simple
+		}
+	}
+	return simplified, nil
+}
+
+func TestSimplifyPaths(t *testing.T) {
+	converter := staticPathConverter{
+		"pg::public::orders":   "public.orders",
+		"pg::public::payments": "public.payments",
+	}
+
+	t.Run("v1beta1", func(t *testing.T) {
+		simplified, err := simplifyPaths(converter, []byte(`version: v1beta1
+monitors:
+  - id: volume
+    monitored_id: pg::public::orders
+  - id: freshness
+    monitored_ids:
+      - pg::public::payments
+      - pg::public::unknown
+`))
+		require.NoError(t, err)
+		assert.Equal(t, `version: v1beta1
+monitors:
+  - id: volume
+    monitored_id: public.orders
+  - id: freshness
+    monitored_ids:
+      - public.payments
+      - pg::public::unknown
+`, string(simplified))
+	})
+
+	t.Run("v1beta2", func(t *testing.T) {
+		simplified, err := simplifyPaths(converter, []byte(`# header
+version: v1beta2
+entities:
+    - id: pg::public::orders # orders
+      monitors:
+        - id: volume
+          type: volume
+          name: orders volume
+    - id: pg::public::unknown
+`))
+		require.NoError(t, err)
+		assert.Equal(t, `# header
+version: v1beta2
+entities:
+    - id: public.orders # orders
+      monitors:
+        - id: volume
+          type: volume
+          name: orders volume
+    - id: pg::public::unknown
+`, string(simplified))
+	})
+}
+
+func TestSplitExport(t *testing.T) {
+	monitorOn := func(id, path string) *pb.MonitorDefinition {
+		return &pb.MonitorDefinition{
+			Id:          id,
+			MonitoredId: &entitiesv1.Identifier{Id: &entitiesv1.Identifier_SynqPath{SynqPath: &entitiesv1.SynqPathIdentifier{Path: path}}},
+		}
+	}
+	monitors := []*pb.MonitorDefinition{
+		monitorOn("orders", "sf-prod::ANALYTICS::PUBLIC::ORDERS"),
+		monitorOn("payments", "sf-prod::ANALYTICS::PUBLIC::PAYMENTS"),
+		monitorOn("events", "sf-prod::RAW::EVENTS::CLICKS"),
+		monitorOn("runs", "ch-prod::default::runs"),
+		monitorOn("runs_daily", "ch-prod::default::runs"),
+	}
+
+	ids := func(byFile map[string][]*pb.MonitorDefinition) map[string][]string {
+		return lo.MapValues(byFile, func(monitors []*pb.MonitorDefinition, _ string) []string {
+			return lo.Map(monitors, func(monitor *pb.MonitorDefinition, _ int) string { return monitor.Id })
+		})
+	}
+
+	assert.Equal(t, map[string][]string{
+		"out/sf-prod.ANALYTICS.PUBLIC.ORDERS.yaml":   {"orders"},
+		"out/sf-prod.ANALYTICS.PUBLIC.PAYMENTS.yaml": {"payments"},
+		"out/sf-prod.RAW.EVENTS.CLICKS.yaml":         {"events"},
+		"out/ch-prod.default.runs.yaml":              {"runs", "runs_daily"},
+	}, ids(splitExport("out", exportSplitBy_Entity, monitors)))
+
+	assert.Equal(t, map[string][]string{
+		"out/sf-prod.ANALYTICS.PUBLIC.yaml": {"orders", "payments"},
+		"out/sf-prod.RAW.EVENTS.yaml":       {"events"},
+		"out/ch-prod.default.yaml":          {"runs", "runs_daily"},
+	}, ids(splitExport("out", exportSplitBy_Schema, monitors)))
+
+	assert.Equal(t, map[string][]string{
+		"out/sf-prod.ANALYTICS.yaml": {"orders", "payments"},
+		"out/sf-prod.RAW.yaml":       {"events"},
+		"out/ch-prod.default.yaml":   {"runs", "runs_daily"},
+	}, ids(splitExport("out", exportSplitBy_Database, monitors)))
+
+	assert.Equal(t, map[string][]string{
+		"out/sf-prod.yaml": {"orders", "payments", "events"},
+		"out/ch-prod.yaml": {"runs", "runs_daily"},
+	}, ids(splitExport("out", exportSplitBy_Integration, monitors)))
+
+	colliding := []*pb.MonitorDefinition{
+		monitorOn("dotted", "a.b"),
+		monitorOn("nested", "a::b"),
+		monitorOn("spaced", "x y"),
+		monitorOn("underscored", "x_y"),
+		monitorOn("numbered", "x_y_2"),
+	}
+	assert.Equal(t, map[string][]string{
+		"out/a.b.yaml":     {"dotted"},
+		"out/a.b_2.yaml":   {"nested"},
+		"out/x_y.yaml":     {"spaced"},
+		"out/x_y_2.yaml":   {"underscored"},
+		"out/x_y_2_2.yaml": {"numbered"},
+	}, ids(splitExport("out", exportSplitBy_Entity, colliding)))
+}
+
+func TestExportFileName(t *testing.T) {
+	assert.Equal(t, "bq-prod.my-project.dataset.table.yaml", exportFileName("bq-prod::my-project::dataset::table"))
+	assert.Equal(t, "pg.public.weird_name.yaml", exportFileName("pg::public::weird/name"))
+	assert.Equal(t, "unknown.yaml", exportFileName(""))
+}
+
+func TestExportHeader(t *testing.T) {
+	exportedAt := time.Date(2025, 3, 4, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	assert.Equal(t, `# yaml-language-server: $schema=https://raw.githubusercontent.com/getsynq/synq-monitors/main/schema.json
+# Exported from workspace acme on 2025-03-04T11:30:00Z
+# Scope: source=app, integration=[pg]
+`, exportHeader("acme", exportScope{source: "app", integrationIds: []string{"pg"}}, exportedAt))
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getsynq/monitors_mgmt/mgmt"
+)
+
+// reviewChanges asks to accept every change of the overview, dropping those which are refused, for --interactive.
+func reviewChanges(out *namespaceOutput, changesOverview *mgmt.ChangesOverview, accept func(label string) bool) []*mgmt.Change {
+	out.Println("\n🔎 Reviewing changes one by one")
+	return changesOverview.Select(func(change *mgmt.Change) bool {
+		return accept(fmt.Sprintf("%s? (y/N)", describeChange(change)))
+	})
+}
+
+// describeChange names a change and what it does, like "Update monitor orders_volume (<uuid>): schedule; resets on schedule".
+func describeChange(change *mgmt.Change) string {
+	description := fmt.Sprintf("%s%s %s", strings.ToUpper(string(change.Action[:1])), change.Action[1:], change.Kind)
+	if change.Name != "" {
+		description += " " + change.Name
+	}
+	description += fmt.Sprintf(" (%s)", change.Id)
+
+	details := []string{}
+	if len(change.Fields) > 0 {
+		details = append(details, strings.Join(change.Fields, ", "))
+	}
+	if change.Reset && len(change.ResetReasons) > 0 {
+		details = append(details, "resets on "+strings.Join(change.ResetReasons, ", "))
+	} else if change.Reset {
+		details = append(details, "resets")
+	}
+	if len(details) > 0 {
+		description += ": " + strings.Join(details, "; ")
+	}
+	return description
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReviewChanges(t *testing.T) {
+	kept, removed := createMonitor("kept", "orders", "table1"), createMonitor("removed", "orders", "table2")
+	service := newFakeMgmtService(kept, removed)
+
+	updated := proto.Clone(kept).(*pb.MonitorDefinition)
+	updated.Name = "kept"
+	updated.Timezone = "UTC"
+	changesOverview, err := service.ConfigChangesOverview(
+		[]*pb.MonitorDefinition{updated, createMonitor("created", "orders", "table3")}, nil, nil, "orders",
+	)
+	require.NoError(t, err)
+
+	labels := []string{}
+	skipped := reviewChanges(standardOutput(), changesOverview, func(label string) bool {
+		labels = append(labels, label)
+		return !strings.HasPrefix(label, "Delete")
+	})
+	assert.Equal(t, []string{
+		"Create monitor (created)? (y/N)",
+		"Update monitor kept (kept): name, timezone; resets on timezone? (y/N)",
+		"Delete monitor (removed)? (y/N)",
+	}, labels)
+
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "removed", skipped[0].Id)
+	assert.Empty(t, changesOverview.MonitorsToDelete)
+	assert.Len(t, changesOverview.MonitorsToCreate, 1)
+	assert.Len(t, changesOverview.MonitorsChangesOverview, 1)
+
+	require.NoError(t, service.DeployMonitors(changesOverview))
+	assert.Equal(t, []*mgmt.ChangesOverview{changesOverview}, service.deployed)
+}
+
+func TestDescribeChange(t *testing.T) {
+	assert.Equal(t, "Move monitor volume (id): config_id; resets", describeChange(&mgmt.Change{
+		Kind: mgmt.ChangeKind_Monitor, Action: mgmt.ChangeAction_Move, Id: "id", Name: "volume", Fields: []string{"config_id"}, Reset: true,
+	}))
+	assert.Equal(t, "Delete test not_null (id)", describeChange(&mgmt.Change{
+		Kind: mgmt.ChangeKind_Test, Action: mgmt.ChangeAction_Delete, Id: "id", Name: "not_null",
+	}))
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var (
+	pathsCmd_lockfile string
+	pathsCmd_locked   bool
+
+	pathsLockCmd_update bool
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{deployCmd, planCmd, validateCmd, pathsLockCmd, pathsResolveCmd} {
+		cmd.Flags().StringVar(&pathsCmd_lockfile, "lockfile", paths.LockFile, "Lockfile of the SYNQ paths the monitored paths resolve to, used when it exists")
+	}
+	for _, cmd := range []*cobra.Command{planCmd, validateCmd} {
+		cmd.Flags().BoolVar(&pathsCmd_locked, "locked", false, "Resolve the monitored paths from the lockfile only, without the API")
+	}
+	pathsLockCmd.Flags().BoolVar(&pathsLockCmd_update, "update", false, "Write the lockfile instead of checking that it is up to date")
+
+	pathsCmd.AddCommand(pathsLockCmd)
+}
+
+var pathsLockCmd = &cobra.Command{
+	Use:   "lock [FILES...]",
+	Short: "Check or update the lockfile of the resolved monitored paths",
+	Long: `Resolve every monitored path of the YAML configuration and compare them with the lockfile,
+which fails when the lockfile is out of date. With --update, the lockfile is written instead.
+
+Deploy and plan resolve the locked paths to their locked SYNQ paths, even when a new integration makes
+them ambiguous, and warn when they resolve differently. Paths which do not resolve anymore keep their locked
+SYNQ path on update, so an ambiguous path can be pinned by editing the lockfile.
+
+If no files are provided, it will recursively search for YAML files from the working directory.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: lockPaths,
+}
+
+func lockPaths(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	conn, err := connectToApi(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprintf(logs, "Connected to API...\n\n")
+
+	workspace, err := fetchWorkspace(ctx, conn)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(logs, "🔍 Workspace: %s\n\n", workspace)
+	events.emit(outputEvent{Event: eventType_Workspace, Workspace: workspace})
+
+	filePaths, err := collectFiles(args)
+	if err != nil {
+		return err
+	}
+	parsersByNamespace, _, err := loadParsers(filePaths)
+	if err != nil {
+		return err
+	}
+
+	// Only the update can create the lockfile, the check fails without it.
+	var current *paths.Lock
+	if pathsLockCmd_update {
+		current, err = readLock()
+	} else {
+		current, err = requireLock()
+	}
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		current = paths.NewLock(workspace)
+	}
+
+	// The lock is resolved without the cache, to record what the API resolves now.
+	pathsToLock := namespacesPaths(parsersByNamespace, sortedNamespaces(parsersByNamespace), nil)
+	fmt.Fprintf(logs, "🔍 Resolving %d monitored paths...\n", len(pathsToLock))
+	resolution, err := paths.NewPathConverter(ctx, conn).ResolvePaths(pathsToLock)
+	if err != nil {
+		return apiError(fmt.Errorf("❌ Error resolving monitored paths: %v", err))
+	}
+	updated := current.Update(workspace, pathsToLock, resolution)
+
+	if kept := lo.Filter(lo.Keys(updated.Paths), func(path string, _ int) bool {
+		_, ok := resolution.Resolved[path]
+		return !ok
+	}); len(kept) > 0 {
+		slices.Sort(kept)
+		fmt.Fprintf(logs, "📌 %d paths do not resolve anymore and keep their locked SYNQ path:\n", len(kept))
+		for _, path := range kept {
+			fmt.Fprintf(logs, "  - %s: %s\n", path, updated.Paths[path])
+		}
+	}
+
+	changes := lockChanges(current, updated)
+	if len(changes) > 0 {
+		fmt.Fprintf(logs, "\n📝 %d changes to %s:\n", len(changes), pathsCmd_lockfile)
+		for _, change := range changes {
+			fmt.Fprintf(logs, "  %s\n", change)
+		}
+		fmt.Fprintln(logs)
+	}
+
+	if !pathsLockCmd_update && len(changes) > 0 {
+		return validationError(fmt.Errorf("❌ %s is out of date, run paths lock --update to update it", pathsCmd_lockfile))
+	}
+	if pathsLockCmd_update {
+		if err := updated.Write(pathsCmd_lockfile); err != nil {
+			return fmt.Errorf("❌ Error writing %s: %v", pathsCmd_lockfile, err)
+		}
+		fmt.Fprintf(logs, "🔒 Locked %d paths in %s\n", len(updated.Paths), pathsCmd_lockfile)
+	} else {
+		fmt.Fprintf(logs, "✅ %s is up to date\n", pathsCmd_lockfile)
+	}
+
+	// The paths which can be neither resolved nor kept are reported, and fail like they would fail deploy.
+	_, resolveErr := resolution.SimpleToPath(lo.Filter(pathsToLock, func(path string, _ int) bool {
+		_, ok := updated.Paths[path]
+		return !ok
+	}))
+	if resolveErr != nil && resolveErr.HasErrors() {
+		return simpleToPathError(resolveErr)
+	}
+	return nil
+}
+
+// lockChanges describes the differences between two locks, one line per path.
+func lockChanges(current *paths.Lock, updated *paths.Lock) []string {
+	changes := []string{}
+	if current.Workspace != updated.Workspace {
+		changes = append(changes, fmt.Sprintf("~ workspace: %s -> %s", current.Workspace, updated.Workspace))
+	}
+
+	lockedPaths := lo.Uniq(append(lo.Keys(current.Paths), lo.Keys(updated.Paths)...))
+	slices.Sort(lockedPaths)
+	for _, path := range lockedPaths {
+		before, locked := current.Paths[path]
+		after, stillLocked := updated.Paths[path]
+		switch {
+		case !locked:
+			changes = append(changes, fmt.Sprintf("+ %s: %s", path, after))
+		case !stillLocked:
+			changes = append(changes, fmt.Sprintf("- %s: %s", path, before))
+		case before != after:
+			changes = append(changes, fmt.Sprintf("~ %s: %s -> %s", path, before, after))
+		}
+	}
+	return changes
+}
+
+// readLock returns the lock of --lockfile, nil when the file does not exist.
+func readLock() (*paths.Lock, error) {
+	lock, err := paths.ReadLock(pathsCmd_lockfile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, validationError(fmt.Errorf("❌ Error reading the lockfile: %v", err))
+	}
+	return lock, nil
+}
+
+// requireLock returns the lock of --lockfile, failing when it does not exist.
+func requireLock() (*paths.Lock, error) {
+	lock, err := readLock()
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return nil, validationError(fmt.Errorf("❌ %s does not exist, run paths lock --update to create it.", pathsCmd_lockfile))
+	}
+	return lock, nil
+}
+
+// newLockingPathConverter returns the converter of deploy and plan, which resolve the locked paths to their locked SYNQ path.
+// With --locked, the paths are only resolved from the lockfile.
+func newLockingPathConverter(ctx context.Context, conn *grpc.ClientConn, workspace string) (paths.PathConverter, error) {
+	var lock *paths.Lock
+	var err error
+	if pathsCmd_locked {
+		lock, err = requireLock()
+	} else {
+		lock, err = readLock()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return newPathConverter(ctx, conn, workspace), nil
+	}
+
+	if lock.Workspace != workspace {
+		return nil, validationError(fmt.Errorf(
+			"❌ %s locks the paths of workspace %s, not %s. Run paths lock --update to lock them again.",
+			pathsCmd_lockfile, lock.Workspace, workspace,
+		))
+	}
+	if pathsCmd_locked {
+		fmt.Fprintf(logs, "🔒 Resolving the monitored paths from %s only\n", pathsCmd_lockfile)
+		return paths.LockedPathConverter(lock), nil
+	}
+	fmt.Fprintf(logs, "🔒 Using the %d paths locked in %s\n", len(lock.Paths), pathsCmd_lockfile)
+	return paths.WithLock(newPathConverter(ctx, conn, workspace), lock), nil
+}
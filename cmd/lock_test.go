@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockChanges(t *testing.T) {
+	current := paths.NewLock("acme")
+	current.Paths = map[string]string{
+		"public.orders":   "pg::public::orders",
+		"public.payments": "pg::public::payments",
+		"analytics.users": "bq::analytics::users",
+	}
+	updated := paths.NewLock("acme")
+	updated.Paths = map[string]string{
+		"public.orders":   "pg::public::orders",
+		"public.payments": "pg::billing::payments",
+		"analytics.pages": "bq::analytics::pages",
+	}
+
+	assert.Empty(t, lockChanges(current, current))
+	assert.Equal(t, []string{
+		"+ analytics.pages: bq::analytics::pages",
+		"- analytics.users: bq::analytics::users",
+		"~ public.payments: pg::public::payments -> pg::billing::payments",
+	}, lockChanges(current, updated))
+	assert.Equal(t, []string{"~ workspace: acme -> other"}, lockChanges(paths.NewLock("acme"), paths.NewLock("other")))
+}
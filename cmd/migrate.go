@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v3"
+)
+
+var migrateCmd_outDir string
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrateCmd_outDir, "out-dir", "", "Write migrated files to this directory instead of rewriting them in place")
+
+	rootCmd.AddCommand(migrateCmd)
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [FILES...]",
+	Short: "Migrate v1beta1 YAML configuration to v1beta2",
+	Long: `Rewrite v1beta1 configuration files in the v1beta2 format.
+
+Monitors are grouped by monitored entity, their schedules use the v1beta2 schedule form
+and the values most of them have in common are moved to the defaults.
+
+Every migrated file is converted again and must produce exactly the same monitors, with the same IDs,
+so that deploying it never deletes and recreates a monitor. Files failing this check are left untouched.
+Files already in v1beta2 are skipped.
+
+If no files are provided, it will recursively search for YAML files from the working directory.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: migrateYaml,
+}
+
+func migrateYaml(cmd *cobra.Command, args []string) error {
+	filePaths, err := collectFiles(args)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, filePath := range filePaths {
+		if err := migrateFile(filePath); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", filePath, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return validationError(fmt.Errorf("❌ %d of %d files could not be migrated", failed, len(filePaths)))
+	}
+	return nil
+}
+
+func migrateFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var versionCheck core.Config
+	if err := goyaml.Unmarshal(content, &versionCheck); err != nil {
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if versionCheck.Version == core.Version_V1Beta2 {
+		fmt.Fprintf(logs, "⏭  %s: already %s\n", filePath, core.Version_V1Beta2)
+		return nil
+	}
+
+	migration, err := yaml.MigrateToV1Beta2(content)
+	if err != nil {
+		return err
+	}
+
+	target := migrationTarget(filePath)
+	if err := os.MkdirAll(filepath.Dir(target), 0o770); err != nil {
+		return err
+	}
+	if err := os.WriteFile(target, append(leadingComments(content), migration.Content...), 0o644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(logs,
+		"✅ %s: migrated %d monitors on %d entities of namespace '%s' to %s, monitor IDs unchanged\n",
+		filePath, migration.Monitors, migration.Entities, migration.Namespace, target,
+	)
+	return nil
+}
+
+// migrationTarget mirrors relative paths under --out-dir, other paths are flattened into it.
+func migrationTarget(filePath string) string {
+	if migrateCmd_outDir == "" {
+		return filePath
+	}
+	if filepath.IsLocal(filePath) {
+		return filepath.Join(migrateCmd_outDir, filePath)
+	}
+	return filepath.Join(migrateCmd_outDir, filepath.Base(filePath))
+}
+
+// leadingComments keeps the comment lines heading a file, such as the yaml-language-server schema line,
+// as comments are otherwise lost when the file is generated again.
+func leadingComments(content []byte) []byte {
+	header := bytes.Buffer{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			break
+		}
+		header.WriteString(line + "\n")
+	}
+	return header.Bytes()
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v3"
+)
+
+const (
+	outputFormat_Text = "text"
+	outputFormat_Json = "json"
+	outputFormat_Yaml = "yaml"
+)
+
+var (
+	rootCmd_output     string
+	validOutputFormats = []string{outputFormat_Text, outputFormat_Json, outputFormat_Yaml}
+
+	// stdout only carries events in structured output formats, logs carries everything printed for humans.
+	stdout io.Writer = os.Stdout
+	logs   io.Writer = os.Stdout
+	events           = &eventWriter{format: outputFormat_Text, out: stdout}
+)
+
+type eventType string
+
+const (
+	eventType_Workspace  eventType = "workspace"
+	eventType_Namespace  eventType = "namespace"
+	eventType_Resolution eventType = "resolution"
+	eventType_Changes    eventType = "changes"
+	eventType_Result     eventType = "result"
+	eventType_Export     eventType = "export"
+	eventType_Plan       eventType = "plan"
+	eventType_Path       eventType = "path"
+	eventType_Expansion  eventType = "expansion"
+)
+
+// outputEvent is a single structured event. Only the fields relevant to its type are set.
+type outputEvent struct {
+	Event      eventType            `json:"event"                yaml:"event"`
+	Workspace  string               `json:"workspace,omitempty"  yaml:"workspace,omitempty"`
+	Namespace  string               `json:"namespace,omitempty"  yaml:"namespace,omitempty"`
+	Files      []string             `json:"files,omitempty"      yaml:"files,omitempty"`
+	Resolution *resolutionResult    `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+	Changes    *mgmt.ChangesSummary `json:"changes,omitempty"    yaml:"changes,omitempty"`
+	Status     namespaceStatus      `json:"status,omitempty"     yaml:"status,omitempty"`
+	Error      string               `json:"error,omitempty"      yaml:"error,omitempty"`
+	File       string               `json:"file,omitempty"       yaml:"file,omitempty"`
+	Monitors   int                  `json:"monitors,omitempty"   yaml:"monitors,omitempty"`
+	Skipped    []*mgmt.Change       `json:"skipped,omitempty"    yaml:"skipped,omitempty"`
+	Path       *paths.Explanation   `json:"path,omitempty"       yaml:"path,omitempty"`
+	Expansion  *expansionResult     `json:"expansion,omitempty"  yaml:"expansion,omitempty"`
+}
+
+type resolutionResult struct {
+	Resolved   map[string]string      `json:"resolved"             yaml:"resolved"`
+	Unresolved []string               `json:"unresolved,omitempty" yaml:"unresolved,omitempty"`
+	Ambiguous  map[string][]string    `json:"ambiguous,omitempty"  yaml:"ambiguous,omitempty"`
+	Strategies map[paths.Strategy]int `json:"strategies,omitempty" yaml:"strategies,omitempty"`
+	Cached     int                    `json:"cached,omitempty"     yaml:"cached,omitempty"`
+	Drifted    map[string]paths.Drift `json:"drifted,omitempty"    yaml:"drifted,omitempty"`
+}
+
+// expansionResult is the SYNQ paths of the entities an entity selector was expanded to.
+type expansionResult struct {
+	Selector string   `json:"selector" yaml:"selector"`
+	Paths    []string `json:"paths"    yaml:"paths"`
+}
+
+// eventWriter writes events to stdout, as JSON lines or as YAML documents.
+// In text mode events are dropped, the human logs being the output.
+type eventWriter struct {
+	format string
+	out    io.Writer
+	mu     sync.Mutex
+}
+
+func (w *eventWriter) emit(event outputEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch w.format {
+	case outputFormat_Json:
+		content, err := json.Marshal(event)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error encoding %s event: %v\n", event.Event, err)
+			return
+		}
+		fmt.Fprintf(w.out, "%s\n", content)
+	case outputFormat_Yaml:
+		content, err := goyaml.Marshal(event)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error encoding %s event: %v\n", event.Event, err)
+			return
+		}
+		fmt.Fprintf(w.out, "---\n%s", content)
+	}
+}
+
+func (w *eventWriter) structured() bool {
+	return w.format != outputFormat_Text
+}
+
+// setupOutput validates --output and, for structured formats, prints every human log to stderr
+// so that stdout only carries events.
+func setupOutput(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(validOutputFormats, rootCmd_output) {
+		return validationError(fmt.Errorf("❌ Invalid output format \"%s\". Must be one of %+v.", rootCmd_output, validOutputFormats))
+	}
+
+	events = &eventWriter{format: rootCmd_output, out: stdout}
+	logs = stdout
+	if events.structured() {
+		logs = os.Stderr
+	}
+	return nil
+}
+
+// namespaceOutput is where the processing of a namespace prints its logs and emits its events.
+// Namespaces processed concurrently buffer both, to flush them in the order of the namespaces.
+type namespaceOutput struct {
+	logs   io.Writer
+	events *eventWriter
+	// logsBuffer and eventsBuffer are nil when the output is not buffered.
+	logsBuffer   *bytes.Buffer
+	eventsBuffer *bytes.Buffer
+}
+
+// standardOutput prints logs and emits events as they come.
+func standardOutput() *namespaceOutput {
+	return &namespaceOutput{logs: logs, events: events}
+}
+
+func bufferedOutput() *namespaceOutput {
+	logsBuffer, eventsBuffer := &bytes.Buffer{}, &bytes.Buffer{}
+	return &namespaceOutput{
+		logs:         logsBuffer,
+		events:       &eventWriter{format: events.format, out: eventsBuffer},
+		logsBuffer:   logsBuffer,
+		eventsBuffer: eventsBuffer,
+	}
+}
+
+func (o *namespaceOutput) Printf(format string, a ...any) {
+	fmt.Fprintf(o.logs, format, a...)
+}
+
+func (o *namespaceOutput) Println(a ...any) {
+	fmt.Fprintln(o.logs, a...)
+}
+
+func (o *namespaceOutput) emit(event outputEvent) {
+	o.events.emit(event)
+}
+
+// flush writes what a buffered output collected so far to the logs and the events.
+func (o *namespaceOutput) flush() {
+	if o.logsBuffer != nil {
+		o.logsBuffer.WriteTo(logs)
+	}
+	if o.eventsBuffer != nil {
+		events.mu.Lock()
+		defer events.mu.Unlock()
+		o.eventsBuffer.WriteTo(events.out)
+	}
+}
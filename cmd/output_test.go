@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventWriter(t *testing.T) {
+	event := outputEvent{
+		Event:     eventType_Changes,
+		Namespace: "ns",
+		Changes: &mgmt.ChangesSummary{
+			Namespace: "ns",
+			Counts:    mgmt.ChangesCounts{MonitorsToCreate: 1},
+			Changes:   []*mgmt.Change{{Kind: mgmt.ChangeKind_Monitor, Action: mgmt.ChangeAction_Create, Id: "id", Name: "volume"}},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	writer := &eventWriter{format: outputFormat_Text, out: out}
+	writer.emit(event)
+	assert.Empty(t, out.String())
+
+	writer.format = outputFormat_Json
+	writer.emit(event)
+	writer.emit(outputEvent{Event: eventType_Result, Namespace: "ns", Status: namespaceStatus_Deployed})
+	assert.Equal(t, `{"event":"changes","namespace":"ns","changes":{"namespace":"ns","has_changes":false,"breaking_changes":false,`+
+		`"counts":{"monitors_to_create":1,"monitors_to_update":0,"monitors_to_move":0,"monitors_to_delete":0,"monitors_unchanged":0,"monitors_managed_by_app":0,`+
+		`"monitors_managed_by_other_config":0,"tests_to_create":0,"tests_to_update":0,"tests_to_delete":0,"tests_unchanged":0,"tests_managed_by_other_config":0},`+
+		`"changes":[{"kind":"monitor","action":"create","id":"id","name":"volume"}]}}`+"\n"+
+		`{"event":"result","namespace":"ns","status":"deployed"}`+"\n", out.String())
+
+	out.Reset()
+	writer.format = outputFormat_Yaml
+	writer.emit(outputEvent{Event: eventType_Workspace, Workspace: "workspace"})
+	writer.emit(outputEvent{Event: eventType_Export, File: "out.yaml", Monitors: 2})
+	assert.Equal(t, "---\nevent: workspace\nworkspace: workspace\n---\nevent: export\nfile: out.yaml\nmonitors: 2\n", out.String())
+}
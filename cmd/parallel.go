@@ -0,0 +1,47 @@
+package cmd
+
+// processNamespaces runs process on the namespaces, at most parallelism of them at once, and then finish on each one
+// in the order of the namespaces, once it is processed and its output is flushed. finish runs on the calling goroutine,
+// which serializes the prompts. With a parallelism of 1 the output is printed as it comes.
+func processNamespaces[T any](
+	namespaces []string,
+	parallelism int,
+	process func(out *namespaceOutput, namespace string) T,
+	finish func(out *namespaceOutput, namespace string, processed T) namespaceResult,
+) []namespaceResult {
+	results := []namespaceResult{}
+	if parallelism <= 1 {
+		for _, namespace := range namespaces {
+			out := standardOutput()
+			results = append(results, finish(out, namespace, process(out, namespace)))
+		}
+		return results
+	}
+
+	outputs := make([]*namespaceOutput, len(namespaces))
+	processed := make([]T, len(namespaces))
+	done := make([]chan struct{}, len(namespaces))
+	for i := range namespaces {
+		outputs[i], done[i] = bufferedOutput(), make(chan struct{})
+	}
+
+	// Namespaces start in order, as they are finished in order.
+	slots := make(chan struct{}, parallelism)
+	go func() {
+		for i, namespace := range namespaces {
+			slots <- struct{}{}
+			go func() {
+				defer close(done[i])
+				defer func() { <-slots }()
+				processed[i] = process(outputs[i], namespace)
+			}()
+		}
+	}()
+
+	for i, namespace := range namespaces {
+		<-done[i]
+		outputs[i].flush()
+		results = append(results, finish(standardOutput(), namespace, processed[i]))
+	}
+	return results
+}
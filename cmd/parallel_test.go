@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessNamespaces(t *testing.T) {
+	stdoutFile, err := os.CreateTemp(t.TempDir(), "stdout")
+	require.NoError(t, err)
+	eventsOut := &bytes.Buffer{}
+	previousLogs, previousEvents := logs, events
+	logs, events = stdoutFile, &eventWriter{format: outputFormat_Json, out: eventsOut}
+	t.Cleanup(func() { logs, events = previousLogs, previousEvents })
+
+	namespaces := []string{"a", "b", "c", "d", "e"}
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	finishing := atomic.Bool{}
+	results := processNamespaces(namespaces, 3,
+		func(out *namespaceOutput, namespace string) string {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+
+			out.Printf("processing %s\n", namespace)
+			// The first namespaces take the longest, the output stays in order anyway.
+			time.Sleep(time.Duration(len(namespaces)-strings.Index("abcde", namespace)) * 5 * time.Millisecond)
+			out.emit(outputEvent{Event: eventType_Namespace, Namespace: namespace})
+			out.Printf("processed %s\n", namespace)
+			return "processed " + namespace
+		},
+		func(out *namespaceOutput, namespace string, processed string) namespaceResult {
+			assert.False(t, finishing.Swap(true), "finish runs one namespace at a time")
+			defer finishing.Store(false)
+
+			out.Printf("finished %s\n", namespace)
+			assert.Equal(t, "processed "+namespace, processed)
+			return namespaceSucceeded(namespace, namespaceStatus_Deployed)
+		},
+	)
+
+	require.Len(t, results, len(namespaces))
+	expectedLogs, expectedEvents := "", ""
+	for i, namespace := range namespaces {
+		assert.Equal(t, namespace, results[i].Namespace)
+		expectedLogs += fmt.Sprintf("processing %s\nprocessed %s\nfinished %s\n", namespace, namespace, namespace)
+		expectedEvents += fmt.Sprintf(`{"event":"namespace","namespace":"%s"}`+"\n", namespace)
+	}
+	printedLogs, err := os.ReadFile(stdoutFile.Name())
+	require.NoError(t, err)
+	assert.Equal(t, expectedLogs, string(printedLogs))
+	assert.Equal(t, expectedEvents, eventsOut.String())
+	assert.Equal(t, 3, maxRunning)
+}
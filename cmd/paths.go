@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var (
+	pathsCmd_cacheTTL     time.Duration
+	pathsCmd_refreshPaths bool
+)
+
+func init() {
+	// Every command converting paths shares the cache flags.
+	for _, cmd := range []*cobra.Command{deployCmd, planCmd, exportCmd, adoptCmd} {
+		cmd.Flags().DurationVar(&pathsCmd_cacheTTL, "paths-cache-ttl", 0, "Cache path resolutions on disk for this long, like 24h. Disabled by default")
+		cmd.Flags().BoolVar(&pathsCmd_refreshPaths, "refresh-paths", false, "Ignore the cached path resolutions and resolve every path again")
+	}
+
+	pathsCacheCmd.AddCommand(pathsCacheClearCmd)
+	pathsCmd.AddCommand(pathsCacheCmd)
+	rootCmd.AddCommand(pathsCmd)
+}
+
+var pathsCmd = &cobra.Command{
+	Use:   "paths",
+	Short: "Manage how monitored paths are resolved",
+}
+
+var pathsCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk cache of path resolutions",
+	Long: `With --paths-cache-ttl, path resolutions are cached on disk for each workspace for that long,
+so that deploy, plan, export and adopt do not resolve the same paths with the API on every run.
+The cache is disabled by default.
+
+Set --refresh-paths on those commands to resolve every path again, for example after a table was renamed.`,
+}
+
+var pathsCacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete the cached path resolutions of every workspace",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := paths.CacheDir()
+		if err != nil {
+			return fmt.Errorf("❌ Error locating the paths cache: %v", err)
+		}
+		if err := paths.ClearCache(dir); err != nil {
+			return fmt.Errorf("❌ Error clearing the paths cache: %v", err)
+		}
+		fmt.Fprintf(logs, "🧹 Cleared the paths cache in %s\n", dir)
+		return nil
+	},
+}
+
+// newPathConverter returns the converter of the workspace, behind the on-disk cache unless it is disabled.
+func newPathConverter(ctx context.Context, conn *grpc.ClientConn, workspace string) paths.PathConverter {
+	return withPathsCache(paths.NewPathConverter(ctx, conn), workspace)
+}
+
+func withPathsCache(pathsConverter paths.PathConverter, workspace string) paths.PathConverter {
+	if pathsCmd_cacheTTL <= 0 {
+		return pathsConverter
+	}
+	dir, err := paths.CacheDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ Paths cache disabled: %v\n", err)
+		return pathsConverter
+	}
+	return paths.WithCache(pathsConverter, paths.OpenCache(dir, workspace, pathsCmd_cacheTTL, pathsCmd_refreshPaths))
+}
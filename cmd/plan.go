@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/spf13/cobra"
+)
+
+var (
+	planCmd_out        string
+	planCmd_namespaces []string
+	planCmd_reset      []string
+	planCmd_noReset    bool
+	planCmd_maxDeletes string
+)
+
+func init() {
+	planCmd.Flags().StringVar(&planCmd_out, "out", "synq-monitors.plan.json", "File the plan is written to")
+	planCmd.Flags().StringSliceVar(&planCmd_namespaces, "namespace", []string{}, "If set, will only plan changes to the included namespaces")
+	planCmd.Flags().StringSliceVar(&planCmd_reset, "reset", []string{}, "Reset the monitors with these YAML ids or UUIDs, even when unchanged")
+	planCmd.Flags().BoolVar(&planCmd_noReset, "no-reset", false, "Fail to plan a namespace whose changes would reset monitors")
+	planCmd.MarkFlagsMutuallyExclusive("reset", "no-reset")
+	planCmd.Flags().
+		StringVar(&planCmd_maxDeletes, "max-deletes", "", "Fail to plan a namespace deleting more monitors and tests than this number, or percentage like 25%")
+
+	rootCmd.AddCommand(planCmd)
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan [FILES...]",
+	Short: "Write the changes deploy would make to a plan file",
+	Long: `Compute the configuration changes overview of every namespace, exactly like deploy,
+and write it to a versioned JSON plan file instead of applying it.
+
+The plan contains the monitors and tests to create, delete and update (with their delta, reset flags and reasons),
+ownership conflicts and a hash of the input YAML files, so that it can be reviewed before deploying.
+
+If no files are provided, it will recursively search for YAML files from the working directory.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: planFromYaml,
+}
+
+func planFromYaml(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	maxDeletes, err := parseMaxDeletes(planCmd_maxDeletes)
+	if err != nil {
+		return err
+	}
+
+	conn, err := connectToApi(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprintf(logs, "Connected to API...\n\n")
+
+	workspace, err := fetchWorkspace(ctx, conn)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(logs, "🔍 Workspace: %s\n\n", workspace)
+	events.emit(outputEvent{Event: eventType_Workspace, Workspace: workspace})
+
+	filePaths, err := collectFiles(args)
+	if err != nil {
+		return err
+	}
+	inputHash, err := hashFiles(filePaths)
+	if err != nil {
+		return fmt.Errorf("❌ Error hashing files: %v", err)
+	}
+	parsersByNamespace, namespacesToFiles, err := loadParsers(filePaths)
+	if err != nil {
+		return err
+	}
+
+	plan := &mgmt.Plan{
+		Version:    mgmt.PlanVersion,
+		Workspace:  workspace,
+		CreatedAt:  time.Now().UTC(),
+		InputHash:  inputHash,
+		Files:      filePaths,
+		Namespaces: []*mgmt.ChangesOverview{},
+	}
+
+	pathsConverter, err := newLockingPathConverter(ctx, conn, workspace)
+	if err != nil {
+		return err
+	}
+	if err := expandSelectors(paths.NewEntityExpander(ctx, conn), pathsConverter, parsersByNamespace); err != nil {
+		return err
+	}
+
+	protected := protectedMonitors(parsersByNamespace)
+	pathsConverter, err = resolveAllPaths(
+		pathsConverter, parsersByNamespace, selectedNamespaces(parsersByNamespace, planCmd_namespaces), protected, nil,
+	)
+	if err != nil {
+		return err
+	}
+	mgmtService := mgmt.NewMgmtRemoteService(ctx, conn).WithOutput(logs)
+	results := []namespaceResult{}
+	for _, namespace := range sortedNamespaces(parsersByNamespace) {
+		fmt.Fprintf(logs, "📋 Processing namespace '%s'\n", namespace)
+		for _, file := range namespacesToFiles[namespace] {
+			fmt.Fprintf(logs, " - %s\n", file)
+		}
+		events.emit(outputEvent{Event: eventType_Namespace, Namespace: namespace, Files: namespacesToFiles[namespace]})
+
+		if len(planCmd_namespaces) > 0 && !slices.Contains(planCmd_namespaces, namespace) {
+			fmt.Fprintf(logs, "🧹 Not processing %s as it is not in %v\n\n", namespace, planCmd_namespaces)
+			results = append(results, namespaceSucceeded(namespace, namespaceStatus_Skipped))
+			continue
+		}
+
+		// Failures are printed once, by the summary.
+		changesOverview, err := planNamespace(mgmtService, pathsConverter, workspace, namespace, parsersByNamespace[namespace], protected, maxDeletes)
+		if err != nil {
+			results = append(results, namespaceFailed(namespace, err))
+			continue
+		}
+		plan.Namespaces = append(plan.Namespaces, changesOverview)
+		if changesOverview.HasChanges() {
+			results = append(results, namespaceSucceeded(namespace, namespaceStatus_Planned))
+		} else {
+			results = append(results, namespaceSucceeded(namespace, namespaceStatus_Unchanged))
+		}
+	}
+
+	printNamespacesSummary(results)
+	if err := namespacesError(results); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ Plan not written, some namespaces could not be planned")
+		return err
+	}
+
+	err = plan.WriteFile(planCmd_out)
+	if err != nil {
+		return fmt.Errorf("❌ Error writing plan: %v", err)
+	}
+	fmt.Fprintf(logs, "\n✅ Plan written to %s\n", planCmd_out)
+	events.emit(outputEvent{Event: eventType_Plan, Workspace: workspace, File: planCmd_out})
+	return nil
+}
+
+// planNamespace computes the changes of a namespace, refusing those apply would refuse whatever its flags.
+func planNamespace(
+	mgmtService mgmt.MgmtService,
+	pathsConverter paths.PathConverter,
+	workspace string,
+	namespace string,
+	parsers []*yaml.VersionedParser,
+	protected []*pb.MonitorDefinition,
+	maxDeletes *deletesLimit,
+) (*mgmt.ChangesOverview, error) {
+	prepared, err := prepareNamespace(standardOutput(), pathsConverter, workspace, namespace, parsers)
+	if err != nil {
+		return nil, err
+	}
+
+	changesOverview, err := mgmtService.ConfigChangesOverview(prepared.Monitors, prepared.Moves, prepared.SqlTests, namespace)
+	if err != nil {
+		return nil, apiError(fmt.Errorf("❌ Error getting config changes overview: %v", err))
+	}
+	if err := protectMonitors(pathsConverter, workspace, changesOverview, protected); err != nil {
+		return nil, err
+	}
+	applyResets(changesOverview, prepared, planCmd_reset)
+
+	changesOverview.Fprint(logs)
+	events.emit(outputEvent{Event: eventType_Changes, Namespace: namespace, Changes: changesOverview.Summary()})
+	if planCmd_noReset {
+		if err := checkResets(changesOverview); err != nil {
+			return nil, err
+		}
+	}
+	// Whether deletions are allowed without a prompt is only known when the plan is applied.
+	if err := checkDeletes(changesOverview, maxDeletes, true, false); err != nil {
+		return nil, err
+	}
+	return changesOverview, nil
+}
+
+// hashFiles hashes the name and content of every file, in the order given.
+func hashFiles(filePaths []string) (string, error) {
+	hash := sha256.New()
+	for _, filePath := range filePaths {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", filePath, len(content))
+		hash.Write(content)
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanNamespaceChecksDeletes(t *testing.T) {
+	parser, err := yaml.NewVersionedParser([]byte(`version: v1beta2
+namespace: orders
+entities: []
+`))
+	require.NoError(t, err)
+	service := newFakeMgmtService(
+		createMonitor("monitor1", "orders", "table1"),
+		createMonitor("monitor2", "orders", "table2"),
+	)
+
+	limit, err := parseMaxDeletes("1")
+	require.NoError(t, err)
+	changesOverview, err := planNamespace(service, staticPathConverter{}, "test-workspace", "orders", []*yaml.VersionedParser{parser}, nil, limit)
+	assert.Nil(t, changesOverview)
+	assert.ErrorContains(t, err, "more than --max-deletes 1")
+	assert.Equal(t, ExitCode_BreakingChanges, exitCode(err))
+
+	// Deletions without a limit are left to apply, which knows whether they are confirmed.
+	changesOverview, err = planNamespace(service, staticPathConverter{}, "test-workspace", "orders", []*yaml.VersionedParser{parser}, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, changesOverview.MonitorsToDelete, 2)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// applyResets applies the reset policies of the YAML, then resets the monitors selected with --reset.
+// Selected ids which are not used in the namespace are ignored, as --reset applies to every namespace.
+//
+// Moved monitors keep their previous UUID in the overview, the policies and ids of their new UUID follow them.
+func applyResets(changesOverview *mgmt.ChangesOverview, prepared *preparedNamespace, resetIds []string) {
+	previousIds := lo.Invert(changesOverview.MonitorsMoved)
+	deployedId := func(monitorId string) string { return lo.ValueOr(previousIds, monitorId, monitorId) }
+
+	changesOverview.ApplyResetPolicies(lo.MapKeys(prepared.ResetPolicies, func(_ mgmt.ResetPolicy, monitorId string) string {
+		return deployedId(monitorId)
+	}))
+
+	monitorIds := []string{}
+	for _, resetId := range resetIds {
+		if monitorUUIDs, ok := prepared.MonitorUUIDs[resetId]; ok {
+			monitorIds = append(monitorIds, lo.Map(monitorUUIDs, func(monitorId string, _ int) string { return deployedId(monitorId) })...)
+		} else if parsed, err := uuid.Parse(resetId); err == nil {
+			monitorIds = append(monitorIds, deployedId(parsed.String()))
+		}
+	}
+	if len(monitorIds) > 0 {
+		changesOverview.ForceReset(monitorIds)
+	}
+}
+
+// checkResets refuses the changes which reset monitors, for --no-reset.
+func checkResets(changesOverview *mgmt.ChangesOverview) error {
+	resets := slices.DeleteFunc(slices.Clone(changesOverview.MonitorsChangesOverview), func(change *pb.ChangeOverview) bool {
+		return !change.ShouldReset
+	})
+	if len(resets) == 0 {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("❌ %d monitors of namespace %s would be reset, refused by --no-reset:", len(resets), changesOverview.ConfigID)}
+	for _, change := range resets {
+		lines = append(lines, fmt.Sprintf(
+			"  - %s (%s): %s", change.NewDefinition.GetName(), change.MonitorId, strings.Join(changesOverview.MonitorsResetReasons[change.MonitorId], ", "),
+		))
+	}
+	return breakingChangesError(errors.New(strings.Join(lines, "\n")))
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"testing"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestApplyAndCheckResets(t *testing.T) {
+	parser, err := yaml.NewVersionedParser([]byte(`version: v1beta2
+namespace: orders
+defaults:
+  timezone: Europe/Vilnius
+entities:
+  - id: pg::public::orders
+    time_partitioning_column: created_at
+    monitors:
+      - id: orders_volume
+        type: volume
+      - id: orders_freshness
+        type: freshness
+        expression: created_at
+        reset_policy: never
+      - id: orders_rows
+        type: volume
+        filter: amount > 0
+`))
+	require.NoError(t, err)
+
+	prepared, err := prepareNamespace(standardOutput(), staticPathConverter{}, "test-workspace", "orders", []*yaml.VersionedParser{parser})
+	require.NoError(t, err)
+	require.Len(t, prepared.Monitors, 3)
+	volume, freshness, rows := prepared.Monitors[0].Id, prepared.Monitors[1].Id, prepared.Monitors[2].Id
+	assert.Equal(t, map[string]mgmt.ResetPolicy{freshness: mgmt.ResetPolicy_Never}, prepared.ResetPolicies)
+
+	// The volume and freshness monitors were deployed with another timezone, the rows monitor is unchanged.
+	remote := map[string]*pb.MonitorDefinition{}
+	for _, monitor := range prepared.Monitors {
+		deployed := proto.Clone(monitor).(*pb.MonitorDefinition)
+		deployed.Source = pb.MonitorDefinition_SOURCE_API
+		if deployed.Id != rows {
+			deployed.Timezone = "UTC"
+		}
+		remote[deployed.Id] = deployed
+	}
+	overview := func() *mgmt.ChangesOverview {
+		service := &fakeMgmtService{remote: remote}
+		changesOverview, err := service.ConfigChangesOverview(prepared.Monitors, prepared.Moves, nil, "orders")
+		require.NoError(t, err)
+		return changesOverview
+	}
+	shouldReset := func(changesOverview *mgmt.ChangesOverview) map[string]bool {
+		resets := map[string]bool{}
+		for _, change := range changesOverview.MonitorsChangesOverview {
+			resets[change.MonitorId] = change.ShouldReset
+		}
+		return resets
+	}
+
+	changesOverview := overview()
+	applyResets(changesOverview, prepared, nil)
+	assert.Equal(t, map[string]bool{volume: true, freshness: false}, shouldReset(changesOverview))
+
+	err = checkResets(changesOverview)
+	assert.Equal(t, ExitCode_BreakingChanges, exitCode(err))
+	assert.EqualError(t, err, "❌ 1 monitors of namespace orders would be reset, refused by --no-reset:\n  - "+
+		prepared.Monitors[0].GetName()+" ("+volume+"): timezone")
+
+	// --reset selects monitors by their YAML id or UUID, unknown ids belong to other namespaces.
+	changesOverview = overview()
+	applyResets(changesOverview, prepared, []string{"orders_rows", freshness, "payments_volume"})
+	assert.Equal(t, map[string]bool{volume: true, freshness: true, rows: true}, shouldReset(changesOverview))
+	assert.Equal(t, []string{"timezone", mgmt.ResetReason_Forced}, changesOverview.MonitorsResetReasons[freshness])
+	assert.Equal(t, []string{mgmt.ResetReason_Forced}, changesOverview.MonitorsResetReasons[rows])
+	assert.Empty(t, changesOverview.MonitorsUnchanged)
+
+	unchanged := &fakeMgmtService{remote: map[string]*pb.MonitorDefinition{rows: remote[rows]}}
+	changesOverview, err = unchanged.ConfigChangesOverview(prepared.Monitors[2:], nil, nil, "orders")
+	require.NoError(t, err)
+	assert.NoError(t, checkResets(changesOverview))
+}
+
+func TestApplyResetsToMovedMonitors(t *testing.T) {
+	parser, err := yaml.NewVersionedParser([]byte(`version: v1beta2
+namespace: orders
+entities:
+  - id: pg::public::orders
+    time_partitioning_column: created_at
+    monitors:
+      - id: orders_volume_daily
+        type: volume
+        timezone: Europe/Vilnius
+        reset_policy: never
+        moved_from:
+          - id: orders_volume
+`))
+	require.NoError(t, err)
+
+	prepared, err := prepareNamespace(standardOutput(), staticPathConverter{}, "test-workspace", "orders", []*yaml.VersionedParser{parser})
+	require.NoError(t, err)
+	require.Len(t, prepared.Monitors, 1)
+	newId := prepared.Monitors[0].Id
+	require.Len(t, prepared.Moves[newId], 1)
+	previousId := prepared.Moves[newId][0]
+
+	// The monitor is deployed under its previous id, with another timezone which resets it.
+	deployed := proto.Clone(prepared.Monitors[0]).(*pb.MonitorDefinition)
+	deployed.Id = previousId
+	deployed.Timezone = "UTC"
+	deployed.Source = pb.MonitorDefinition_SOURCE_API
+	overview := func() *mgmt.ChangesOverview {
+		monitors := []*pb.MonitorDefinition{proto.Clone(prepared.Monitors[0]).(*pb.MonitorDefinition)}
+		service := &fakeMgmtService{remote: map[string]*pb.MonitorDefinition{previousId: deployed}}
+		changesOverview, err := service.ConfigChangesOverview(monitors, prepared.Moves, nil, "orders")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{previousId: newId}, changesOverview.MonitorsMoved)
+		require.Len(t, changesOverview.MonitorsChangesOverview, 1)
+		return changesOverview
+	}
+
+	changesOverview := overview()
+	applyResets(changesOverview, prepared, nil)
+	assert.False(t, changesOverview.MonitorsChangesOverview[0].ShouldReset)
+	assert.NoError(t, checkResets(changesOverview))
+
+	changesOverview = overview()
+	applyResets(changesOverview, prepared, []string{"orders_volume_daily"})
+	assert.True(t, changesOverview.MonitorsChangesOverview[0].ShouldReset)
+	assert.Equal(t, []string{"timezone", mgmt.ResetReason_Forced}, changesOverview.MonitorsResetReasons[previousId])
+}
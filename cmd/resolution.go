@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/samber/lo"
+)
+
+var strategyNames = map[paths.Strategy]string{
+	paths.Strategy_SynqPath:    "SYNQ paths",
+	paths.Strategy_Coordinate:  "DB coordinates",
+	paths.Strategy_DotNotation: "dot notation",
+	paths.Strategy_Lockfile:    "the lockfile",
+	paths.Strategy_Chosen:      "your choices",
+}
+
+// monitoredPaths returns the unique paths monitored or tested, which are to be resolved.
+func monitoredPaths(protoMonitors []*pb.MonitorDefinition, sqlTests []*sqltestsv1.SqlTest) []string {
+	pathsToConvert := []string{}
+	for _, monitor := range protoMonitors {
+		path := monitor.MonitoredId.GetSynqPath().GetPath()
+		if len(path) > 0 {
+			pathsToConvert = append(pathsToConvert, path)
+		}
+	}
+	for _, sqlTest := range sqlTests {
+		path := sqlTest.MonitoredId.GetSynqPath().GetPath()
+		if len(path) > 0 {
+			pathsToConvert = append(pathsToConvert, path)
+		}
+	}
+	return lo.Uniq(pathsToConvert)
+}
+
+// selectedNamespaces returns the namespaces to process, all of them when no --namespace is given.
+func selectedNamespaces[T any](byNamespace map[string]T, selected []string) []string {
+	return lo.Filter(sortedNamespaces(byNamespace), func(namespace string, _ int) bool {
+		return len(selected) == 0 || slices.Contains(selected, namespace)
+	})
+}
+
+// resolveAllPaths resolves the paths of the given namespaces and of the protected monitors in a single pass,
+// so that a path used by several namespaces is resolved once. The returned converter serves them to every namespace,
+// which still reports its own unresolved paths. Files which cannot be converted are left to their namespace to report.
+// When given, disambiguate may resolve the ambiguous paths before they are served.
+func resolveAllPaths(
+	pathsConverter paths.PathConverter,
+	parsersByNamespace map[string][]*yaml.VersionedParser,
+	namespaces []string,
+	protected []*pb.MonitorDefinition,
+	disambiguate func(resolution *paths.Resolution),
+) (paths.PathConverter, error) {
+	pathsToResolve := namespacesPaths(parsersByNamespace, namespaces, protected)
+	if len(pathsToResolve) == 0 {
+		return pathsConverter, nil
+	}
+
+	fmt.Fprintf(logs, "🔍 Resolving %d monitored paths of %d namespaces...\n", len(pathsToResolve), len(namespaces))
+	resolution, err := pathsConverter.ResolvePaths(pathsToResolve)
+	if err != nil {
+		return nil, apiError(fmt.Errorf("❌ Error resolving monitored paths: %v", err))
+	}
+	// Suggestions only help the namespaces to report their unresolved paths, they are not worth failing for.
+	if len(resolution.Unresolved) > 0 {
+		if suggestions, err := pathsConverter.SuggestPaths(resolution.Unresolved); err == nil {
+			resolution.Suggestions = suggestions
+		}
+	}
+	if disambiguate != nil && len(resolution.Ambiguous) > 0 {
+		disambiguate(resolution)
+	}
+	printResolution(resolution, len(pathsToResolve))
+	events.emit(outputEvent{Event: eventType_Resolution, Resolution: &resolutionResult{
+		Resolved:   resolution.Resolved,
+		Unresolved: resolution.Unresolved,
+		Ambiguous:  resolution.Ambiguous,
+		Strategies: resolution.CountByStrategy(),
+		Cached:     len(resolution.Cached),
+		Drifted:    resolution.Drifted,
+	}})
+
+	return paths.WithResolution(pathsConverter, resolution), nil
+}
+
+// namespacesPaths returns the paths monitored by the given namespaces, and by the protected monitors.
+// Files which cannot be converted are left to their namespace to report.
+func namespacesPaths(
+	parsersByNamespace map[string][]*yaml.VersionedParser,
+	namespaces []string,
+	protected []*pb.MonitorDefinition,
+) []string {
+	identities, sqlTests := slices.Clone(protected), []*sqltestsv1.SqlTest{}
+	for _, namespace := range namespaces {
+		for _, parser := range parsersByNamespace[namespace] {
+			if monitors, err := parser.ConvertToMonitorDefinitions(); err == nil {
+				identities = append(identities, monitors...)
+				identities = append(identities, identitiesToResolve(parser.ConvertToMonitorMoves())...)
+			}
+			if parserSqlTests, err := parser.ConvertToSqlTests(); err == nil {
+				sqlTests = append(sqlTests, parserSqlTests...)
+			}
+		}
+	}
+	return monitoredPaths(identities, sqlTests)
+}
+
+func printResolution(resolution *paths.Resolution, requested int) {
+	counts := resolution.CountByStrategy()
+	strategies := lo.FilterMap(paths.Strategies, func(strategy paths.Strategy, _ int) (string, bool) {
+		return fmt.Sprintf("%d from %s", counts[strategy], strategyNames[strategy]), counts[strategy] > 0
+	})
+	fmt.Fprintf(logs, "✅ Resolved %d of %d paths", len(resolution.Resolved), requested)
+	if len(strategies) > 0 {
+		fmt.Fprintf(logs, ": %s", strings.Join(strategies, ", "))
+	}
+	if len(resolution.Cached) > 0 {
+		fmt.Fprintf(logs, " (%d cached, set --refresh-paths to resolve them again)", len(resolution.Cached))
+	}
+	fmt.Fprintln(logs)
+	driftedPaths := lo.Keys(resolution.Drifted)
+	slices.Sort(driftedPaths)
+	for _, path := range driftedPaths {
+		drift := resolution.Drifted[path]
+		switch {
+		case drift.Live != "":
+			fmt.Fprintf(logs, "⚠️ %s is locked to %s but now resolves to %s", path, drift.Locked, drift.Live)
+		case len(drift.Candidates) > 0:
+			fmt.Fprintf(logs, "⚠️ %s is locked to %s but is now ambiguous between %s", path, drift.Locked, strings.Join(drift.Candidates, ", "))
+		default:
+			fmt.Fprintf(logs, "⚠️ %s is locked to %s but does not resolve anymore", path, drift.Locked)
+		}
+		fmt.Fprintln(logs, ", run paths lock --update to accept it")
+	}
+	if failed := len(resolution.Unresolved) + len(resolution.Ambiguous); failed > 0 {
+		fmt.Fprintf(logs, "⚠️ %d paths are unresolved or ambiguous, their namespaces will report them\n", failed)
+	}
+	fmt.Fprintln(logs)
+}
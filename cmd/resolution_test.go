@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPathConverter resolves every path under the pg integration, and counts the calls to the API.
+type countingPathConverter struct {
+	staticPathConverter
+	resolved     [][]string
+	simpleToPath int
+}
+
+func (c *countingPathConverter) SimpleToPath(simple []string) (map[string]string, *paths.SimpleToPathError) {
+	c.simpleToPath++
+	return nil, nil
+}
+
+func (c *countingPathConverter) ResolvePaths(simple []string) (*paths.Resolution, error) {
+	c.resolved = append(c.resolved, simple)
+	resolution := paths.NewResolution()
+	for _, path := range simple {
+		if path == "public.unknown" {
+			resolution.Unresolved = append(resolution.Unresolved, path)
+			continue
+		}
+		resolution.Resolved[path] = "pg::" + paths.PathWithColons(path)
+		resolution.Strategies[path] = paths.Strategy_Coordinate
+	}
+	return resolution, nil
+}
+
+func (c *countingPathConverter) SuggestPaths(unresolved []string) (map[string][]string, error) {
+	return map[string][]string{"public.unknown": {"pg::public::known"}}, nil
+}
+
+func TestResolveAllPaths(t *testing.T) {
+	parse := func(content string) *yaml.VersionedParser {
+		parser, err := yaml.NewVersionedParser([]byte(content))
+		require.NoError(t, err)
+		return parser
+	}
+	parsersByNamespace := map[string][]*yaml.VersionedParser{
+		"orders": {parse(`version: v1beta2
+namespace: orders
+entities:
+  - id: public.orders
+    monitors:
+      - id: orders_volume
+        type: volume
+        prevent_destroy: true
+`)},
+		"finance": {parse(`version: v1beta2
+namespace: finance
+entities:
+  - id: public.orders
+    monitors:
+      - id: revenue_volume
+        type: volume
+  - id: public.unknown
+    monitors:
+      - id: unknown_volume
+        type: volume
+`)},
+		"marketing": {parse(`version: v1beta2
+namespace: marketing
+entities:
+  - id: public.campaigns
+    monitors:
+      - id: campaigns_volume
+        type: volume
+`)},
+	}
+
+	converter := &countingPathConverter{}
+	resolvingConverter, err := resolveAllPaths(
+		converter, parsersByNamespace, selectedNamespaces(parsersByNamespace, []string{"orders", "finance"}), protectedMonitors(parsersByNamespace), nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, converter.resolved, 1)
+	assert.ElementsMatch(t, []string{"public.orders", "public.unknown"}, converter.resolved[0])
+
+	prepared, err := prepareNamespace(standardOutput(), resolvingConverter, "workspace", "orders", parsersByNamespace["orders"])
+	require.NoError(t, err)
+	assert.Equal(t, "pg::public::orders", prepared.Monitors[0].MonitoredId.GetSynqPath().GetPath())
+
+	_, err = prepareNamespace(standardOutput(), resolvingConverter, "workspace", "finance", parsersByNamespace["finance"])
+	assert.Equal(t, ExitCode_Validation, exitCode(err))
+	assert.Contains(t, err.Error(), "  - public.unknown\n      did you mean pg::public::known?")
+	assert.Zero(t, converter.simpleToPath)
+}
@@ -14,10 +14,11 @@ var (
 )
 
 var rootCmd = &cobra.Command{
-	Use:           "synq-monitors",
-	Short:         "Manage custom monitors on SYNQ",
-	SilenceUsage:  true,
-	SilenceErrors: true,
+	Use:               "synq-monitors",
+	Short:             "Manage custom monitors on SYNQ",
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+	PersistentPreRunE: setupOutput,
 	Run: func(cmd *cobra.Command, args []string) {
 	},
 }
@@ -27,11 +28,15 @@ func init() {
 	rootCmd.Flags().StringVar(&clientID, "client-id", "", "Synq client ID (overrides .env and environment variables)")
 	rootCmd.Flags().StringVar(&clientSecret, "client-secret", "", "Synq client secret (overrides .env and environment variables)")
 	rootCmd.Flags().StringVar(&apiUrl, "api-url", "", "Synq API URL (overrides .env and environment variables)")
+
+	rootCmd.PersistentFlags().
+		StringVarP(&rootCmd_output, "output", "o", outputFormat_Text, fmt.Sprintf("Output format, one of %+v. Structured formats write events to stdout and logs to stderr.", validOutputFormats))
 }
 
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v", err)
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(exitCode(err))
 	}
 }
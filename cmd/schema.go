@@ -2,32 +2,35 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/getsynq/monitors_mgmt/schema"
 	"github.com/spf13/cobra"
 )
 
+// schemaURL is the published schema, referenced by the files the CLI writes.
+const schemaURL = "https://raw.githubusercontent.com/getsynq/synq-monitors/main/schema.json"
+
 var schemaCmd = &cobra.Command{
 	Use:   "schema",
 	Short: "Generate JSON schema",
 	Long: `Generate JSON schema.
 The schema can be used in IDEs for autocomplete and validation.`,
 	Args: cobra.NoArgs,
-	Run:  generateSchema,
+	RunE: generateSchema,
 }
 
 func init() {
 	rootCmd.AddCommand(schemaCmd)
 }
 
-func generateSchema(cmd *cobra.Command, args []string) {
+func generateSchema(cmd *cobra.Command, args []string) error {
 	schemaBytes, err := schema.GenerateJSONSchema()
 	if err != nil {
-		exitWithError(fmt.Errorf("❌ Error generating schema: %v", err))
+		return fmt.Errorf("❌ Error generating schema: %v", err)
 	}
 
-	if _, err := os.Stdout.Write(schemaBytes); err != nil {
-		exitWithError(fmt.Errorf("❌ Error writing schema: %v", err))
+	if _, err := stdout.Write(schemaBytes); err != nil {
+		return fmt.Errorf("❌ Error writing schema: %v", err)
 	}
+	return nil
 }
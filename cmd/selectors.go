@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/samber/lo"
+)
+
+// expandSelectors expands the entity selectors of every file into the entities of the catalog they match,
+// before anything is converted, so that those entities are then resolved and deployed like declared ones.
+// The ids declared next to selectors are resolved first, so that the tables they declare keep their own monitors.
+// Invalid selectors are left to their namespace to report.
+func expandSelectors(
+	expander paths.EntityExpander,
+	pathsConverter paths.PathConverter,
+	parsersByNamespace map[string][]*yaml.VersionedParser,
+) error {
+	selectors, declaredIds := []*paths.Selector{}, []string{}
+	for _, namespace := range sortedNamespaces(parsersByNamespace) {
+		for _, parser := range parsersByNamespace[namespace] {
+			entitySelectors := parser.EntitySelectors()
+			for _, entitySelector := range entitySelectors {
+				if selector, err := paths.NewSelector(*entitySelector); err == nil {
+					selectors = append(selectors, selector)
+				}
+			}
+			if len(entitySelectors) > 0 {
+				declaredIds = append(declaredIds, parser.EntityIds()...)
+			}
+		}
+	}
+	selectors = lo.UniqBy(selectors, func(selector *paths.Selector) string { return selector.String() })
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(logs, "🔭 Expanding %d entity selectors...\n", len(selectors))
+	matched, err := expander.ExpandSelectors(selectors)
+	if err != nil {
+		return apiError(fmt.Errorf("❌ Error expanding entity selectors: %v", err))
+	}
+	for _, selector := range selectors {
+		synqPaths := matched[selector.String()]
+		printExpansion(selector.String(), synqPaths)
+		events.emit(outputEvent{Event: eventType_Expansion, Expansion: &expansionResult{Selector: selector.String(), Paths: synqPaths}})
+	}
+	fmt.Fprintln(logs)
+
+	// Ids which do not resolve are compared as SYNQ paths, and reported by their namespace.
+	resolved := map[string]string{}
+	if declaredIds = lo.Uniq(declaredIds); len(declaredIds) > 0 {
+		resolution, err := pathsConverter.ResolvePaths(declaredIds)
+		if err != nil {
+			return apiError(fmt.Errorf("❌ Error resolving the ids declared next to entity selectors: %v", err))
+		}
+		resolved = resolution.Resolved
+	}
+	for _, parsers := range parsersByNamespace {
+		for _, parser := range parsers {
+			parser.ExpandEntitySelectors(matched, resolved)
+		}
+	}
+	return nil
+}
+
+func printExpansion(selector string, synqPaths []string) {
+	if len(synqPaths) == 0 {
+		fmt.Fprintf(logs, "⚠️ %s matches no entity, its monitors and tests are not deployed\n", selector)
+		return
+	}
+	fmt.Fprintf(logs, "✅ %s matches %d entities:\n", selector, len(synqPaths))
+	for _, synqPath := range synqPaths {
+		fmt.Fprintf(logs, "   - %s\n", synqPath)
+	}
+}
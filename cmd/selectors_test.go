@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"testing"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/yaml"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticEntityExpander matches every selector with the same SYNQ paths, and records the selectors it expanded.
+type staticEntityExpander struct {
+	synqPaths []string
+	expanded  []string
+}
+
+func (e *staticEntityExpander) ExpandSelectors(selectors []*paths.Selector) (map[string][]string, error) {
+	matched := map[string][]string{}
+	for _, selector := range selectors {
+		e.expanded = append(e.expanded, selector.String())
+		matched[selector.String()] = e.synqPaths
+	}
+	return matched, nil
+}
+
+func TestExpandSelectors(t *testing.T) {
+	parser := func(content string) *yaml.VersionedParser {
+		parser, err := yaml.NewVersionedParser([]byte(content))
+		require.NoError(t, err)
+		return parser
+	}
+	parsersByNamespace := map[string][]*yaml.VersionedParser{
+		"sales": {parser(`version: v1beta2
+namespace: sales
+entities:
+  - selector:
+      match: pg.sales.*
+    monitors:
+      - id: volume
+        type: volume
+  # Resolves to pg::sales::orders, which keeps its own monitors.
+  - id: sales.orders
+    time_partitioning_column: created_at
+    monitors:
+      - id: volume
+        type: volume
+`)},
+		"finance": {parser(`version: v1beta2
+namespace: finance
+entities:
+  - selector:
+      match: pg.sales.*
+    monitors:
+      - id: finance_volume
+        type: volume
+  - selector:
+      regex: "("
+    monitors:
+      - id: volume
+        type: volume
+`)},
+		"legacy": {parser(`namespace: legacy
+monitors:
+  - id: volume
+    type: volume
+    monitored_id: bq-prod.legacy.orders
+    time_partitioning: created_at
+`)},
+	}
+
+	expander := &staticEntityExpander{synqPaths: []string{"pg::sales::orders", "pg::sales::payments"}}
+	converter := &countingPathConverter{}
+	require.NoError(t, expandSelectors(expander, converter, parsersByNamespace))
+	assert.Equal(t, []string{"pg.sales.*"}, expander.expanded)
+	assert.Equal(t, [][]string{{"sales.orders"}}, converter.resolved)
+
+	identities := func(namespace string) []string {
+		monitors, err := parsersByNamespace[namespace][0].ConvertToMonitorDefinitions()
+		if err != nil {
+			return []string{err.Error()}
+		}
+		return lo.Map(monitors, func(monitor *pb.MonitorDefinition, _ int) string {
+			return monitor.Id + "/" + monitor.MonitoredId.GetSynqPath().GetPath()
+		})
+	}
+	assert.Equal(t, []string{"volume/pg::sales::payments", "volume/sales.orders"}, identities("sales"))
+	// The invalid selector is left to its namespace to report.
+	assert.Equal(t, []string{"Entity '/(/': selector - invalid regex: error parsing regexp: missing closing ): `(`"}, identities("finance"))
+	assert.Equal(t, []string{"volume/bq-prod.legacy.orders"}, identities("legacy"))
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/getsynq/monitors_mgmt/mgmt"
+	"github.com/samber/lo"
+)
+
+type namespaceStatus string
+
+const (
+	namespaceStatus_Deployed  namespaceStatus = "deployed"
+	namespaceStatus_Destroyed namespaceStatus = "destroyed"
+	namespaceStatus_Planned   namespaceStatus = "planned"
+	namespaceStatus_Unchanged namespaceStatus = "unchanged"
+	namespaceStatus_Skipped   namespaceStatus = "skipped"
+	namespaceStatus_Failed    namespaceStatus = "failed"
+)
+
+type namespaceResult struct {
+	Namespace string
+	Status    namespaceStatus
+	Err       error
+	Skipped   []*mgmt.Change
+}
+
+func namespaceSucceeded(namespace string, status namespaceStatus) namespaceResult {
+	return namespaceResult{Namespace: namespace, Status: status}
+}
+
+// namespaceSkippedChanges is a namespace which succeeded without the changes skipped with --interactive.
+func namespaceSkippedChanges(namespace string, status namespaceStatus, skipped []*mgmt.Change) namespaceResult {
+	return namespaceResult{Namespace: namespace, Status: status, Skipped: skipped}
+}
+
+func namespaceFailed(namespace string, err error) namespaceResult {
+	return namespaceResult{Namespace: namespace, Status: namespaceStatus_Failed, Err: err}
+}
+
+// printNamespacesSummary prints the outcome of every namespace and emits it as a result event.
+func printNamespacesSummary(results []namespaceResult) {
+	for _, result := range results {
+		event := outputEvent{Event: eventType_Result, Namespace: result.Namespace, Status: result.Status, Skipped: result.Skipped}
+		if result.Err != nil {
+			event.Error = result.Err.Error()
+		}
+		events.emit(event)
+	}
+
+	if len(results) == 0 {
+		return
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	red := color.New(color.FgRed, color.Bold)
+	gray := color.New(color.FgHiBlack)
+
+	fmt.Fprintln(logs)
+	color.New(color.Bold).Fprintln(logs, "📋 Namespaces Summary")
+	fmt.Fprintln(logs, strings.Repeat("=", 50))
+	for _, result := range results {
+		switch result.Status {
+		case namespaceStatus_Failed:
+			red.Fprintf(logs, "  ❌ %s: %s\n", result.Namespace, result.Status)
+			for _, line := range strings.Split(result.Err.Error(), "\n") {
+				gray.Fprintf(logs, "       %s\n", line)
+			}
+		case namespaceStatus_Skipped:
+			gray.Fprintf(logs, "  ⏭  %s: %s\n", result.Namespace, result.Status)
+		default:
+			green.Fprintf(logs, "  ✅ %s: %s\n", result.Namespace, result.Status)
+		}
+		if len(result.Skipped) > 0 {
+			gray.Fprintf(logs, "       ⏭  %d changes skipped:\n", len(result.Skipped))
+			for _, change := range result.Skipped {
+				gray.Fprintf(logs, "         - %s\n", describeChange(change))
+			}
+		}
+	}
+	fmt.Fprintln(logs, strings.Repeat("=", 50))
+}
+
+// namespacesError summarises failed namespaces into a single error carrying the most severe exit code.
+func namespacesError(results []namespaceResult) error {
+	failed := lo.Filter(results, func(result namespaceResult, _ int) bool {
+		return result.Err != nil
+	})
+	if len(failed) == 0 {
+		return nil
+	}
+
+	worst := mostSevereError(lo.Map(failed, func(result namespaceResult, _ int) error {
+		return result.Err
+	}))
+	return &exitError{
+		code: exitCode(worst),
+		err:  fmt.Errorf("❌ %d of %d namespaces failed", len(failed), len(results)),
+	}
+}
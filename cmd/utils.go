@@ -5,11 +5,17 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
+	"slices"
 	"strings"
 
+	iamv1grpc "buf.build/gen/go/getsynq/api/grpc/go/synq/auth/iam/v1/iamv1grpc"
+	iamv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/auth/iam/v1"
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/config"
+	"github.com/manifoldco/promptui"
+	"github.com/samber/lo"
 	"golang.org/x/oauth2/clientcredentials"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -28,10 +34,13 @@ func connectToApi(ctx context.Context) (*grpc.ClientConn, error) {
 
 	creds, err := configLoader.LoadCredentials()
 	if err != nil {
-		exitWithError(fmt.Errorf("❌ Failed to load credentials: %v", err))
+		return nil, fmt.Errorf("❌ Failed to load credentials: %v", err)
 	}
 
-	host, port := getHostAndPort(creds.ApiUrl)
+	host, port, err := getHostAndPort(creds.ApiUrl)
+	if err != nil {
+		return nil, err
+	}
 	apiUrl := fmt.Sprintf("%s:%s", host, port)
 
 	tokenURL := fmt.Sprintf("https://%s/oauth2/token", host)
@@ -49,15 +58,28 @@ func connectToApi(ctx context.Context) (*grpc.ClientConn, error) {
 		grpc.WithAuthority(host),
 	}
 
-	return grpc.DialContext(ctx, apiUrl, opts...)
+	conn, err := grpc.DialContext(ctx, apiUrl, opts...)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return conn, nil
+}
+
+func fetchWorkspace(ctx context.Context, conn *grpc.ClientConn) (string, error) {
+	iamApi := iamv1grpc.NewIamServiceClient(conn)
+	iamResponse, err := iamApi.Iam(ctx, &iamv1.IamRequest{})
+	if err != nil {
+		return "", apiError(fmt.Errorf("❌ Failed to fetch workspace: %v", err))
+	}
+	return iamResponse.Workspace, nil
 }
 
-func PrintMonitorDefs(monitorDefs []*pb.MonitorDefinition) {
-	fmt.Println("\n📋 MonitorDefinitions (JSON format):")
-	fmt.Println(strings.Repeat("=", 60))
+func PrintMonitorDefs(w io.Writer, monitorDefs []*pb.MonitorDefinition) {
+	fmt.Fprintln(w, "\n📋 MonitorDefinitions (JSON format):")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
 
 	for i, def := range monitorDefs {
-		fmt.Printf("\n--- Monitor %d: %s ---\n", i+1, def.Name)
+		fmt.Fprintf(w, "\n--- Monitor %d: %s ---\n", i+1, def.Name)
 
 		// Convert to JSON for readable display
 		jsonBytes, err := protojson.MarshalOptions{
@@ -66,7 +88,7 @@ func PrintMonitorDefs(monitorDefs []*pb.MonitorDefinition) {
 		}.Marshal(def)
 
 		if err != nil {
-			fmt.Printf("❌ Error converting to JSON: %v\n", err)
+			fmt.Fprintf(w, "❌ Error converting to JSON: %v\n", err)
 			continue
 		}
 
@@ -74,16 +96,74 @@ func PrintMonitorDefs(monitorDefs []*pb.MonitorDefinition) {
 		var prettyJSON map[string]interface{}
 		if err := json.Unmarshal(jsonBytes, &prettyJSON); err == nil {
 			prettyBytes, _ := json.MarshalIndent(prettyJSON, "", "  ")
-			fmt.Println(string(prettyBytes))
+			fmt.Fprintln(w, string(prettyBytes))
 		} else {
-			fmt.Println(string(jsonBytes))
+			fmt.Fprintln(w, string(jsonBytes))
+		}
+	}
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+}
+
+func PrintSqlTests(w io.Writer, sqlTests []*sqltestsv1.SqlTest) {
+	if len(sqlTests) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\n📋 SqlTests (JSON format):")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for i, sqlTest := range sqlTests {
+		fmt.Fprintf(w, "\n--- Test %d: %s ---\n", i+1, sqlTest.Name)
+
+		jsonBytes, err := protojson.MarshalOptions{
+			Multiline: true,
+			Indent:    "  ",
+		}.Marshal(sqlTest)
+		if err != nil {
+			fmt.Fprintf(w, "❌ Error converting to JSON: %v\n", err)
+			continue
 		}
+		fmt.Fprintln(w, string(jsonBytes))
+	}
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+}
+
+// confirm prompts for a y/N answer. The prompt goes to the logs, which are stderr in structured output mode.
+func confirm(label string) bool {
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+		Stdout:    promptOutput{logs},
+	}
+	result, err := prompt.Run()
+	return err == nil && strings.ToLower(result) == "y"
+}
+
+// choose asks to select one of the items, and returns its index unless the prompt was cancelled.
+func choose(label string, items []string) (int, bool) {
+	prompt := promptui.Select{
+		Label:  label,
+		Items:  items,
+		Stdout: promptOutput{logs},
 	}
+	index, _, err := prompt.Run()
+	return index, err == nil
+}
+
+// promptOutput lets prompts write to the logs without closing them.
+type promptOutput struct {
+	io.Writer
+}
 
-	fmt.Println(strings.Repeat("=", 60))
+func (promptOutput) Close() error {
+	return nil
 }
 
-func exitWithError(err error) {
-	fmt.Fprintf(os.Stderr, "%v\n", err)
-	os.Exit(1)
+// sortedKeys returns the keys of a map in order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := lo.Keys(m)
+	slices.Sort(keys)
+	return keys
 }
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/uuid"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// The workspace only seeds the generated UUIDs, any value detects the same duplicates.
+const validateCmd_workspace = "validate"
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [FILES...]",
+	Short: "Validate YAML configuration without connecting to SYNQ",
+	Long: `Validate custom monitors YAML configuration files offline.
+
+Every file is parsed and converted exactly like deploy does, but no credentials are needed
+and nothing is resolved or deployed. All problems are reported with their file, line and column,
+including duplicate monitors and tests within a namespace.
+
+With --locked, the monitored paths are resolved from the lockfile, which reports the paths missing from it,
+and the monitors and tests of paths locked to the same SYNQ path as duplicates.
+
+If no files are provided, it will recursively search for YAML files from the working directory.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: validateYaml,
+}
+
+type validationProblem struct {
+	File     string
+	Position core.Position
+	Message  string
+}
+
+func (p validationProblem) String() string {
+	if p.Position.IsSet() {
+		return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Position.Line, p.Position.Column, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.File, p.Message)
+}
+
+func validateYaml(cmd *cobra.Command, args []string) error {
+	filePaths := args
+	if len(filePaths) == 0 {
+		var err error
+		filePaths, err = findFiles(".", []string{".yaml", ".yml"})
+		if err != nil {
+			return fmt.Errorf("❌ Error finding files: %v", err)
+		}
+	}
+
+	var lock *paths.Lock
+	if pathsCmd_locked {
+		var err error
+		if lock, err = requireLock(); err != nil {
+			return err
+		}
+	}
+
+	problems := validateFiles(filePaths, lock)
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, problem.String())
+	}
+
+	if len(problems) > 0 {
+		return validationError(fmt.Errorf("❌ Found %d problems in %d files", len(problems), len(filePaths)))
+	}
+	fmt.Fprintf(logs, "✅ %d files are valid\n", len(filePaths))
+	return nil
+}
+
+// validateFiles resolves the monitored paths from the lock, unless it is nil.
+func validateFiles(filePaths []string, lock *paths.Lock) []validationProblem {
+	problems := []validationProblem{}
+	uuidGenerator := uuid.NewUUIDGenerator(validateCmd_workspace)
+
+	// namespace -> generated UUID -> file where it was first seen
+	seenMonitors := map[string]map[string]string{}
+	seenSqlTests := map[string]map[string]string{}
+
+	for _, filePath := range filePaths {
+		parser, err := getParser(filePath)
+		if err != nil {
+			problems = append(problems, validationProblem{File: filePath, Message: err.Error()})
+			continue
+		}
+
+		namespace := parser.GetConfigID()
+		if seenMonitors[namespace] == nil {
+			seenMonitors[namespace] = map[string]string{}
+			seenSqlTests[namespace] = map[string]string{}
+		}
+
+		monitors, err := parser.ConvertToMonitorDefinitions()
+		problems = append(problems, conversionProblems(filePath, err)...)
+		sqlTests, sqlTestsErr := parser.ConvertToSqlTests()
+		problems = append(problems, conversionProblems(filePath, sqlTestsErr)...)
+
+		if lock != nil {
+			resolution := lock.Resolve(monitoredPaths(monitors, sqlTests))
+			for _, path := range resolution.Unresolved {
+				problems = append(problems, validationProblem{
+					File:    filePath,
+					Message: fmt.Sprintf("monitored path '%s' is not in the lockfile, run paths lock --update to lock it", path),
+				})
+			}
+			resolveIdentifiers(resolution.Resolved, monitors, sqlTests)
+		}
+
+		for _, monitor := range monitors {
+			id := uuidGenerator.GenerateMonitorUUID(monitor)
+			if firstFile, ok := seenMonitors[namespace][id]; ok {
+				problems = append(problems, validationProblem{
+					File: filePath,
+					Message: fmt.Sprintf(
+						"duplicate monitor '%s' on '%s' in namespace '%s', first defined in %s",
+						monitor.Name, monitor.MonitoredId.GetSynqPath().GetPath(), namespace, firstFile,
+					),
+				})
+				continue
+			}
+			seenMonitors[namespace][id] = filePath
+		}
+
+		for _, sqlTest := range sqlTests {
+			id := uuidGenerator.GenerateSqlTestUUID(sqlTest)
+			if firstFile, ok := seenSqlTests[namespace][id]; ok {
+				problems = append(problems, validationProblem{
+					File: filePath,
+					Message: fmt.Sprintf(
+						"duplicate test '%s' on '%s' in namespace '%s', first defined in %s",
+						sqlTest.Name, sqlTest.MonitoredId.GetSynqPath().GetPath(), namespace, firstFile,
+					),
+				})
+				continue
+			}
+			seenSqlTests[namespace][id] = filePath
+		}
+	}
+
+	return problems
+}
+
+// conversionProblems splits conversion errors into one problem per error, keeping their position.
+func conversionProblems(filePath string, err error) []validationProblem {
+	if err == nil {
+		return nil
+	}
+
+	errs := []error{err}
+	if multiErr, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = multiErr.Unwrap()
+	}
+
+	problems := []validationProblem{}
+	for _, err := range errs {
+		problem := validationProblem{File: filePath, Message: err.Error()}
+		var located core.LocatedError
+		if errors.As(err, &located) {
+			problem.Position = located.GetPosition()
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
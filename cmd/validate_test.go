@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getsynq/monitors_mgmt/paths"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeValidateFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestValidateFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := writeValidateFile(t, dir, "valid.yaml", `version: v1beta2
+namespace: ns
+entities:
+  - id: orders
+    monitors:
+      - id: volume
+        type: volume
+`)
+	invalid := writeValidateFile(t, dir, "invalid.yaml", `version: v1beta2
+namespace: other
+entities:
+  - id: orders
+    monitors:
+      - id: freshness
+        type: freshness
+    tests:
+      - type: min_max
+        column: amount
+        min_value: 10
+        max_value: 1
+`)
+	duplicate := writeValidateFile(t, dir, "duplicate.yaml", `version: v1beta2
+namespace: ns
+entities:
+  - id: orders
+    monitors:
+      - id: volume
+        type: volume
+`)
+	malformed := writeValidateFile(t, dir, "malformed.yaml", "version: [v1beta2\n")
+
+	t.Run("valid_file", func(t *testing.T) {
+		assert.Empty(t, validateFiles([]string{valid}, nil))
+	})
+
+	t.Run("conversion_errors_are_located", func(t *testing.T) {
+		problems := validateFiles([]string{invalid}, nil)
+		require.Len(t, problems, 2)
+
+		assert.Equal(t, invalid, problems[0].File)
+		assert.Equal(t, core.Position{Line: 6, Column: 9}, problems[0].Position)
+		assert.Contains(t, problems[0].Message, "expression is required")
+
+		assert.Equal(t, core.Position{Line: 11, Column: 9}, problems[1].Position)
+		assert.Contains(t, problems[1].Message, "must not be greater than max_value")
+	})
+
+	t.Run("duplicates_within_namespace", func(t *testing.T) {
+		problems := validateFiles([]string{valid, invalid, duplicate}, nil)
+		require.Len(t, problems, 3)
+		assert.Equal(t, duplicate, problems[2].File)
+		assert.Contains(t, problems[2].Message, "duplicate monitor 'volume' on 'orders' in namespace 'ns'")
+	})
+
+	t.Run("locked_paths", func(t *testing.T) {
+		aliased := writeValidateFile(t, dir, "aliased.yaml", `version: v1beta2
+namespace: ns
+entities:
+  - id: public.orders
+    monitors:
+      - id: volume
+        type: volume
+  - id: public.payments
+    monitors:
+      - id: volume
+        type: volume
+`)
+		lock := paths.NewLock("workspace")
+		lock.Paths = map[string]string{"orders": "pg::public::orders", "public.orders": "pg::public::orders"}
+
+		problems := validateFiles([]string{valid, aliased}, lock)
+		require.Len(t, problems, 2)
+		assert.Equal(t, aliased, problems[0].File)
+		assert.Equal(t, "monitored path 'public.payments' is not in the lockfile, run paths lock --update to lock it", problems[0].Message)
+		assert.Contains(t, problems[1].Message, "duplicate monitor 'volume' on 'pg::public::orders' in namespace 'ns'")
+	})
+
+	t.Run("malformed_yaml", func(t *testing.T) {
+		problems := validateFiles([]string{malformed}, nil)
+		require.Len(t, problems, 1)
+		assert.Contains(t, problems[0].Message, "failed to parse YAML")
+	})
+}
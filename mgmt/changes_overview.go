@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"reflect"
+	"io"
+	"os"
 	"slices"
 	"strings"
 
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/fatih/color"
@@ -25,10 +27,22 @@ type ChangesOverview struct {
 	MonitorsManagedByApp         []string
 	MonitorsManagedByOtherConfig map[string]string
 	MonitorsChangesOverview      []*pb.ChangeOverview
+	// MonitorsMoved maps the previous UUIDs kept by moved monitors to the UUIDs they would otherwise get.
+	MonitorsMoved                map[string]string
+	MonitorsProtected            []string
+	MonitorsResetReasons         map[string][]string
+	SqlTestsUnchanged            []*sqltestsv1.SqlTest
+	SqlTestsToCreate             []*sqltestsv1.SqlTest
+	SqlTestsToDelete             []*sqltestsv1.SqlTest
+	SqlTestsManagedByOtherConfig map[string]string
+	SqlTestsChangesOverview      []*SqlTestChangeOverview
+	// fetchedMonitors are the deployed definitions the overview compares with, by UUID.
+	fetchedMonitors map[string]*pb.MonitorDefinition
 }
 
 func (s *ChangesOverview) HasChanges() bool {
-	return len(s.MonitorsToCreate)+len(s.MonitorsToDelete)+len(s.MonitorsChangesOverview)+len(s.MonitorsManagedByApp)+len(s.MonitorsManagedByOtherConfig) > 0
+	return len(s.MonitorsToCreate)+len(s.MonitorsToDelete)+len(s.MonitorsChangesOverview)+len(s.MonitorsManagedByApp)+len(s.MonitorsManagedByOtherConfig) > 0 ||
+		len(s.SqlTestsToCreate)+len(s.SqlTestsToDelete)+len(s.SqlTestsChangesOverview)+len(s.SqlTestsManagedByOtherConfig) > 0
 }
 
 func (s *ChangesOverview) GetBreakingChanges() string {
@@ -43,10 +57,35 @@ func (s *ChangesOverview) GetBreakingChanges() string {
 		}
 		breakingChanges = append(breakingChanges, fmt.Sprintf("     - Monitor ID: %s, Managed by namespace: %s", monitorId, namespaceStr))
 	}
+	if len(s.MonitorsProtected) > 0 {
+		breakingChanges = append(breakingChanges, fmt.Sprintf("  🛡️ %d monitors to delete are protected by prevent_destroy.", len(s.MonitorsProtected)))
+	}
+	for _, monitor := range s.MonitorsToDelete {
+		if slices.Contains(s.MonitorsProtected, monitor.Id) {
+			breakingChanges = append(breakingChanges, fmt.Sprintf("     - Monitor ID: %s, Name: %s", monitor.Id, monitor.Name))
+		}
+	}
+	if len(s.SqlTestsManagedByOtherConfig) > 0 {
+		breakingChanges = append(breakingChanges, fmt.Sprintf("  🚫 %d tests managed by other configs.", len(s.SqlTestsManagedByOtherConfig)))
+	}
+	for sqlTestId, configId := range s.SqlTestsManagedByOtherConfig {
+		namespaceStr := "default"
+		if len(configId) > 0 {
+			namespaceStr = configId
+		}
+		breakingChanges = append(breakingChanges, fmt.Sprintf("     - Test ID: %s, Managed by namespace: %s", sqlTestId, namespaceStr))
+	}
 	return strings.Join(breakingChanges, "\n")
 }
 
-func GenerateConfigChangesOverview(configId string, protoMonitors []*pb.MonitorDefinition, fetchedMonitors map[string]*pb.MonitorDefinition) (*ChangesOverview, error) {
+func GenerateConfigChangesOverview(
+	configId string,
+	protoMonitors []*pb.MonitorDefinition,
+	moves MonitorMoves,
+	fetchedMonitors map[string]*pb.MonitorDefinition,
+) (*ChangesOverview, error) {
+	monitorsMoved := moveMonitors(protoMonitors, moves, fetchedMonitors)
+
 	// Map incoming data
 	monitorIdsInConfig := []string{}
 	for id, monitor := range fetchedMonitors {
@@ -68,7 +107,7 @@ func GenerateConfigChangesOverview(configId string, protoMonitors []*pb.MonitorD
 	}
 
 	// For all requested monitors check if they are:
-	// * change of ownership (source or config)
+	// * change of ownership (source or config), unless moved from another config
 	// * to create
 	// * to update
 	// * unchanged
@@ -77,6 +116,7 @@ func GenerateConfigChangesOverview(configId string, protoMonitors []*pb.MonitorD
 	monitorsToCreate, monitorsUnchanged := []*pb.MonitorDefinition{}, []*pb.MonitorDefinition{}
 	managedByApp, managedByOtherConfigs := []string{}, map[string]string{}
 	changesOverview := []*pb.ChangeOverview{}
+	monitorsResetReasons := map[string][]string{}
 	for monitorId, monitor := range requestedMonitors {
 		fetchedMonitor := fetchedMonitors[monitorId]
 		monitor.Source = pb.MonitorDefinition_SOURCE_API
@@ -89,7 +129,8 @@ func GenerateConfigChangesOverview(configId string, protoMonitors []*pb.MonitorD
 			managedByApp = append(managedByApp, monitor.Id)
 		}
 
-		if fetchedMonitor.Source == pb.MonitorDefinition_SOURCE_API && monitor.ConfigId != fetchedMonitor.ConfigId {
+		_, moved := monitorsMoved[monitorId]
+		if fetchedMonitor.Source == pb.MonitorDefinition_SOURCE_API && monitor.ConfigId != fetchedMonitor.ConfigId && !moved {
 			managedByOtherConfigs[monitor.Id] = fetchedMonitor.ConfigId
 			continue
 		}
@@ -102,6 +143,9 @@ func GenerateConfigChangesOverview(configId string, protoMonitors []*pb.MonitorD
 			monitorsUnchanged = append(monitorsUnchanged, monitor)
 		} else {
 			changesOverview = append(changesOverview, changes)
+			if reasons := resetReasons(fetchedMonitor, monitor); len(reasons) > 0 {
+				monitorsResetReasons[monitorId] = reasons
+			}
 		}
 	}
 
@@ -113,10 +157,33 @@ func GenerateConfigChangesOverview(configId string, protoMonitors []*pb.MonitorD
 		MonitorsManagedByApp:         managedByApp,
 		MonitorsManagedByOtherConfig: managedByOtherConfigs,
 		MonitorsChangesOverview:      changesOverview,
+		MonitorsMoved:                monitorsMoved,
+		MonitorsResetReasons:         monitorsResetReasons,
+		fetchedMonitors:              fetchedMonitors,
 	}, nil
 }
 
+// PreventDestroy protects the given monitors, any of them planned for deletion becomes a breaking change.
+func (s *ChangesOverview) PreventDestroy(monitorIds []string) {
+	for _, monitor := range s.MonitorsToDelete {
+		if slices.Contains(monitorIds, monitor.Id) && !slices.Contains(s.MonitorsProtected, monitor.Id) {
+			s.MonitorsProtected = append(s.MonitorsProtected, monitor.Id)
+		}
+	}
+}
+
+func monitorIds(monitors []*pb.MonitorDefinition) []string {
+	return lo.Map(monitors, func(monitor *pb.MonitorDefinition, _ int) string {
+		return monitor.Id
+	})
+}
+
 func (s *ChangesOverview) PrettyPrint() {
+	s.Fprint(os.Stdout)
+}
+
+// Fprint writes the overview printed by PrettyPrint to w.
+func (s *ChangesOverview) Fprint(w io.Writer) {
 	// Color definitions
 	green := color.New(color.FgGreen, color.Bold)
 	red := color.New(color.FgRed, color.Bold)
@@ -125,122 +192,184 @@ func (s *ChangesOverview) PrettyPrint() {
 	gray := color.New(color.FgHiBlack)
 	bold := color.New(color.Bold)
 
-	fmt.Println()
-	bold.Printf("📊 Configuration Changes Overview: %s\n", s.ConfigID)
-	fmt.Println(strings.Repeat("=", 50))
+	fmt.Fprintln(w)
+	bold.Fprintf(w, "📊 Configuration Changes Overview: %s\n", s.ConfigID)
+	fmt.Fprintln(w, strings.Repeat("=", 50))
 
-	totalChanges := len(s.MonitorsToCreate) + len(s.MonitorsToDelete) + len(s.MonitorsChangesOverview)
-	fmt.Printf("\n📈 Summary: %d total changes\n", totalChanges)
+	totalChanges := len(s.MonitorsToCreate) + len(s.MonitorsToDelete) + len(s.MonitorsChangesOverview) +
+		len(s.SqlTestsToCreate) + len(s.SqlTestsToDelete) + len(s.SqlTestsChangesOverview)
+	fmt.Fprintf(w, "\n📈 Summary: %d total changes\n", totalChanges)
 	if len(s.MonitorsToCreate) > 0 {
-		green.Printf("  + %d monitors to create\n", len(s.MonitorsToCreate))
+		green.Fprintf(w, "  + %d monitors to create\n", len(s.MonitorsToCreate))
 	}
 	if len(s.MonitorsToDelete) > 0 {
-		red.Printf("  - %d monitors to delete\n", len(s.MonitorsToDelete))
+		red.Fprintf(w, "  - %d monitors to delete\n", len(s.MonitorsToDelete))
 	}
-	if len(s.MonitorsChangesOverview) > 0 {
-		yellow.Printf("  ~ %d monitors to update\n", len(s.MonitorsChangesOverview))
+	monitorsToUpdate, monitorsToMove := s.splitMoves()
+	if len(monitorsToUpdate) > 0 {
+		yellow.Fprintf(w, "  ~ %d monitors to update\n", len(monitorsToUpdate))
+	}
+	if len(monitorsToMove) > 0 {
+		yellow.Fprintf(w, "  → %d monitors to move\n", len(monitorsToMove))
 	}
 	if len(s.MonitorsUnchanged) > 0 {
-		blue.Printf("  = %d monitors unchanged\n", len(s.MonitorsUnchanged))
+		blue.Fprintf(w, "  = %d monitors unchanged\n", len(s.MonitorsUnchanged))
 	}
 	if len(s.MonitorsManagedByApp) > 0 {
-		gray.Printf("  ⚠ %d monitors managed by app that will now be managed by given config\n", len(s.MonitorsManagedByApp))
+		gray.Fprintf(w, "  ⚠ %d monitors managed by app that will now be managed by given config\n", len(s.MonitorsManagedByApp))
 	}
 	if len(s.MonitorsManagedByOtherConfig) > 0 {
-		red.Printf("  🚫 %d monitors managed by other configs\n", len(s.MonitorsManagedByOtherConfig))
+		red.Fprintf(w, "  🚫 %d monitors managed by other configs\n", len(s.MonitorsManagedByOtherConfig))
+	}
+	if len(s.SqlTestsToCreate) > 0 {
+		green.Fprintf(w, "  + %d tests to create\n", len(s.SqlTestsToCreate))
+	}
+	if len(s.SqlTestsToDelete) > 0 {
+		red.Fprintf(w, "  - %d tests to delete\n", len(s.SqlTestsToDelete))
+	}
+	if len(s.SqlTestsChangesOverview) > 0 {
+		yellow.Fprintf(w, "  ~ %d tests to update\n", len(s.SqlTestsChangesOverview))
+	}
+	if len(s.SqlTestsUnchanged) > 0 {
+		blue.Fprintf(w, "  = %d tests unchanged\n", len(s.SqlTestsUnchanged))
+	}
+	if len(s.SqlTestsManagedByOtherConfig) > 0 {
+		red.Fprintf(w, "  🚫 %d tests managed by other configs\n", len(s.SqlTestsManagedByOtherConfig))
 	}
 
 	if totalChanges == 0 {
-		gray.Println("\n✨ No changes detected - configuration is up to date")
+		gray.Fprintln(w, "\n✨ No changes detected - configuration is up to date")
 		return
 	}
 
 	// New monitors
 	if len(s.MonitorsToCreate) > 0 {
-		fmt.Println()
-		green.Println("🆕 Monitors to Create:")
+		fmt.Fprintln(w)
+		green.Fprintln(w, "🆕 Monitors to Create:")
 		for i, monitor := range s.MonitorsToCreate {
-			fmt.Printf("  %d. ", i+1)
-			green.Printf("%s", monitor.Name)
-			fmt.Printf(" (%s)\n", s.getMonitorType(monitor))
+			fmt.Fprintf(w, "  %d. ", i+1)
+			green.Fprintf(w, "%s", monitor.Name)
+			fmt.Fprintf(w, " (%s)\n", s.getMonitorType(monitor))
 			if monitor.MonitoredId != nil {
-				gray.Printf("     → Monitored: %s\n", s.formatMonitoredId(monitor.MonitoredId))
+				gray.Fprintf(w, "     → Monitored: %s\n", s.formatMonitoredId(monitor.MonitoredId))
 			}
 		}
 	}
 
 	// Deleted monitors
 	if len(s.MonitorsToDelete) > 0 {
-		fmt.Println()
-		red.Println("🗑️  Monitors to Delete:")
+		fmt.Fprintln(w)
+		red.Fprintln(w, "🗑️  Monitors to Delete:")
 		for i, monitor := range s.MonitorsToDelete {
-			fmt.Printf("  %d. ", i+1)
-			red.Printf("%s", monitor.Name)
-			fmt.Printf(" (%s)\n", s.getMonitorType(monitor))
+			fmt.Fprintf(w, "  %d. ", i+1)
+			red.Fprintf(w, "%s", monitor.Name)
+			fmt.Fprintf(w, " (%s)\n", s.getMonitorType(monitor))
 			if monitor.MonitoredId != nil {
-				gray.Printf("     → Monitored: %s\n", s.formatMonitoredId(monitor.MonitoredId))
+				gray.Fprintf(w, "     → Monitored: %s\n", s.formatMonitoredId(monitor.MonitoredId))
+			}
+			if slices.Contains(s.MonitorsProtected, monitor.Id) {
+				red.Fprintf(w, "       🛡️ PROTECTED BY prevent_destroy\n")
 			}
 		}
 	}
 
 	// Updated monitors
-	if len(s.MonitorsChangesOverview) > 0 {
-		fmt.Println()
-		yellow.Println("📝 Monitors to Update:")
-		for i, change := range s.MonitorsChangesOverview {
-			fmt.Printf("  %d. ", i+1)
+	if len(monitorsToUpdate) > 0 {
+		fmt.Fprintln(w)
+		yellow.Fprintln(w, "📝 Monitors to Update:")
+		for i, change := range monitorsToUpdate {
+			fmt.Fprintf(w, "  %d. ", i+1)
 			if change.NewDefinition != nil {
-				yellow.Printf("%s", change.NewDefinition.Name)
-				fmt.Printf(" (%s)\n", s.getMonitorType(change.NewDefinition))
+				yellow.Fprintf(w, "%s", change.NewDefinition.Name)
+				fmt.Fprintf(w, " (%s)\n", s.getMonitorType(change.NewDefinition))
 			} else if change.OriginDefinition != nil {
-				yellow.Printf("%s", change.OriginDefinition.Name)
-				fmt.Printf(" (%s)\n", s.getMonitorType(change.OriginDefinition))
+				yellow.Fprintf(w, "%s", change.OriginDefinition.Name)
+				fmt.Fprintf(w, " (%s)\n", s.getMonitorType(change.OriginDefinition))
 			}
 
-			// Show ShouldReset flag if true
-			if change.ShouldReset {
-				red.Printf("       🔄 RESET REQUIRED\n")
-			}
+			s.printReset(w, change, red, gray)
 
 			// Show change of ownership
 			if slices.Contains(s.MonitorsManagedByApp, change.MonitorId) {
-				red.Printf("       🔄 Management transfer from App\n")
+				red.Fprintf(w, "       🔄 Management transfer from App\n")
 			}
 
-			if change.Changes != "" {
-				// Indent the diff output
-				lines := strings.Split(change.Changes, "\n")
-				for _, line := range lines {
-					if line != "" {
-						if strings.HasPrefix(line, "+") {
-							green.Printf("       %s\n", line)
-						} else if strings.HasPrefix(line, "-") {
-							red.Printf("       %s\n", line)
-						} else {
-							gray.Printf("       %s\n", line)
-						}
-					}
-				}
+			printChanges(w, change.Changes, green, red, gray)
+		}
+	}
+
+	// Moved monitors, updated in place to keep their history
+	if len(monitorsToMove) > 0 {
+		fmt.Fprintln(w)
+		yellow.Fprintln(w, "🚚 Monitors to Move:")
+		for i, change := range monitorsToMove {
+			fmt.Fprintf(w, "  %d. ", i+1)
+			yellow.Fprintf(w, "%s", change.NewDefinition.GetName())
+			fmt.Fprintf(w, " (%s)\n", s.getMonitorType(change.NewDefinition))
+			gray.Fprintf(w, "     → From: %s", change.OriginDefinition.GetName())
+			if change.OriginDefinition.GetMonitoredId() != nil {
+				gray.Fprintf(w, " on %s", s.formatMonitoredId(change.OriginDefinition.MonitoredId))
 			}
+			gray.Fprintf(w, " in namespace %s\n", lo.CoalesceOrEmpty(change.OriginDefinition.GetConfigId(), "default"))
+
+			s.printReset(w, change, red, gray)
+
+			printChanges(w, change.Changes, green, red, gray)
 		}
 	}
 
 	// Unchanged monitors
 	if len(s.MonitorsUnchanged) > 0 {
-		fmt.Println()
-		blue.Println("✅ Monitors Unchanged:")
+		fmt.Fprintln(w)
+		blue.Fprintln(w, "✅ Monitors Unchanged:")
 		for i, monitor := range s.MonitorsUnchanged {
-			fmt.Printf("  %d. ", i+1)
-			blue.Printf("%s", monitor.Name)
-			fmt.Printf(" (%s)\n", s.getMonitorType(monitor))
+			fmt.Fprintf(w, "  %d. ", i+1)
+			blue.Fprintf(w, "%s", monitor.Name)
+			fmt.Fprintf(w, " (%s)\n", s.getMonitorType(monitor))
 			if monitor.MonitoredId != nil {
-				gray.Printf("     → Monitored: %s\n", s.formatMonitoredId(monitor.MonitoredId))
+				gray.Fprintf(w, "     → Monitored: %s\n", s.formatMonitoredId(monitor.MonitoredId))
 			}
 		}
 	}
 
-	fmt.Println()
-	fmt.Println(strings.Repeat("=", 50))
+	s.prettyPrintSqlTests(w, green, red, yellow, blue, gray)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+}
+
+// splitMoves separates the updates of moved monitors from the others.
+func (s *ChangesOverview) splitMoves() ([]*pb.ChangeOverview, []*pb.ChangeOverview) {
+	return lo.FilterReject(s.MonitorsChangesOverview, func(change *pb.ChangeOverview, _ int) bool {
+		_, moved := s.MonitorsMoved[change.MonitorId]
+		return !moved
+	})
+}
+
+// printReset shows why an update resets the monitor, or would without its reset policy.
+func (s *ChangesOverview) printReset(w io.Writer, change *pb.ChangeOverview, red, gray *color.Color) {
+	reasons := strings.Join(s.MonitorsResetReasons[change.MonitorId], ", ")
+	if change.ShouldReset {
+		red.Fprintf(w, "       🔄 RESET REQUIRED: %s\n", lo.CoalesceOrEmpty(reasons, "unknown"))
+	} else if reasons != "" {
+		gray.Fprintf(w, "       ⏸️ Reset skipped by reset_policy never: %s\n", reasons)
+	}
+}
+
+// Indent the diff output
+func printChanges(w io.Writer, changes string, green, red, gray *color.Color) {
+	lines := strings.Split(changes, "\n")
+	for _, line := range lines {
+		if line != "" {
+			if strings.HasPrefix(line, "+") {
+				green.Fprintf(w, "       %s\n", line)
+			} else if strings.HasPrefix(line, "-") {
+				red.Fprintf(w, "       %s\n", line)
+			} else {
+				gray.Fprintf(w, "       %s\n", line)
+			}
+		}
+	}
 }
 
 // Helper function to extract monitor type from MonitorDefinition
@@ -345,50 +474,6 @@ func generateChangeOverview(
 		NewDefinition:    newOverview,
 		Changes:          changes,
 		ChangesDeltaJson: changesDelta,
-		ShouldReset:      shouldReset(origin, newOverview),
+		ShouldReset:      len(resetReasons(origin, newOverview)) > 0,
 	}, nil
 }
-
-func shouldReset(
-	originDef *pb.MonitorDefinition,
-	newDef *pb.MonitorDefinition,
-) bool {
-	if originDef.GetTimezone() != newDef.GetTimezone() {
-		return true
-	}
-
-	if originDef.GetCustomNumeric().GetMetricAggregation() != newDef.GetCustomNumeric().GetMetricAggregation() {
-		return true
-	}
-
-	if reflect.TypeOf(originDef.Schedule) != reflect.TypeOf(newDef.Schedule) {
-		return true
-	} else {
-		switch originDef.Schedule.(type) {
-		case *pb.MonitorDefinition_Daily:
-			if originDef.GetDaily().GetMinutesSinceMidnight() != newDef.GetDaily().GetMinutesSinceMidnight() {
-				return true
-			}
-			if originDef.GetDaily().GetDelayNumDays() != newDef.GetDaily().GetDelayNumDays() {
-				return true
-			}
-		case *pb.MonitorDefinition_Hourly:
-			if originDef.GetHourly().GetMinuteOfHour() != newDef.GetHourly().GetMinuteOfHour() {
-				return true
-			}
-			if originDef.GetHourly().GetDelayNumHours() != newDef.GetHourly().GetDelayNumHours() {
-				return true
-			}
-		}
-	}
-
-	if originDef.GetTimePartitioning().GetExpression() != newDef.GetTimePartitioning().GetExpression() {
-		return true
-	}
-
-	if originDef.GetSegmentation().GetExpression() != newDef.GetSegmentation().GetExpression() {
-		return true
-	}
-
-	return false
-}
@@ -150,7 +150,7 @@ func (s *MgmtServiceTestSuite) TestConfigChangesOverview() {
 			},
 		}
 
-		changes, err := GenerateConfigChangesOverview(configId, requestedMonitors, map[string]*pb.MonitorDefinition{
+		changes, err := GenerateConfigChangesOverview(configId, requestedMonitors, nil, map[string]*pb.MonitorDefinition{
 			existingMonitor.Id:      existingMonitor,
 			appMonitor.Id:           appMonitor,
 			toDeleteMonitor.Id:      toDeleteMonitor,
@@ -199,7 +199,7 @@ func (s *MgmtServiceTestSuite) TestConfigChangesOverview() {
 	s.Run("empty_request_no_existing_monitors", func() {
 		requestedMonitors := []*pb.MonitorDefinition{}
 
-		changes, err := GenerateConfigChangesOverview("config-id-no-existing", requestedMonitors, map[string]*pb.MonitorDefinition{})
+		changes, err := GenerateConfigChangesOverview("config-id-no-existing", requestedMonitors, nil, map[string]*pb.MonitorDefinition{})
 		s.Require().NoError(err)
 		s.Require().NotNil(changes)
 		s.Require().False(changes.HasChanges())
@@ -232,7 +232,7 @@ func (s *MgmtServiceTestSuite) TestConfigChangesOverview() {
 			Source:   pb.MonitorDefinition_SOURCE_API,
 		}
 
-		changes, err := GenerateConfigChangesOverview("", []*pb.MonitorDefinition{}, map[string]*pb.MonitorDefinition{
+		changes, err := GenerateConfigChangesOverview("", []*pb.MonitorDefinition{}, nil, map[string]*pb.MonitorDefinition{
 			monitor.Id: monitor,
 		})
 		s.Require().NoError(err)
@@ -249,7 +249,7 @@ func (s *MgmtServiceTestSuite) TestConfigChangesOverview() {
 	})
 
 	s.Run("empty_request_with_monitors", func() {
-		changes, err := GenerateConfigChangesOverview(configId, []*pb.MonitorDefinition{}, map[string]*pb.MonitorDefinition{
+		changes, err := GenerateConfigChangesOverview(configId, []*pb.MonitorDefinition{}, nil, map[string]*pb.MonitorDefinition{
 			existingMonitor.Id: existingMonitor,
 		})
 		s.Require().NoError(err)
@@ -266,7 +266,7 @@ func (s *MgmtServiceTestSuite) TestConfigChangesOverview() {
 	})
 
 	s.Run("no_changes", func() {
-		changes, err := GenerateConfigChangesOverview(configId, []*pb.MonitorDefinition{existingMonitor}, map[string]*pb.MonitorDefinition{
+		changes, err := GenerateConfigChangesOverview(configId, []*pb.MonitorDefinition{existingMonitor}, nil, map[string]*pb.MonitorDefinition{
 			existingMonitor.Id: existingMonitor,
 		})
 		s.Require().NoError(err)
@@ -320,7 +320,7 @@ func (s *MgmtServiceTestSuite) TestConfigChangesOverview() {
 			},
 			Severity: pb.Severity_SEVERITY_WARNING,
 			Source:   pb.MonitorDefinition_SOURCE_API,
-		}}, map[string]*pb.MonitorDefinition{
+		}}, nil, map[string]*pb.MonitorDefinition{
 			monitor.Id: monitor,
 		})
 		s.Require().NoError(err)
@@ -371,7 +371,7 @@ func (s *MgmtServiceTestSuite) TestConfigChangesOverview() {
 			},
 			Severity: pb.Severity_SEVERITY_WARNING,
 			Source:   pb.MonitorDefinition_SOURCE_API,
-		}}, map[string]*pb.MonitorDefinition{
+		}}, nil, map[string]*pb.MonitorDefinition{
 			monitor.Id: monitor,
 		})
 		s.Require().NoError(err)
@@ -386,3 +386,44 @@ func (s *MgmtServiceTestSuite) TestConfigChangesOverview() {
 		s.Len(changes.MonitorsManagedByOtherConfig, 0)
 	})
 }
+
+func (s *MgmtServiceTestSuite) TestPreventDestroy() {
+	configId := "config-id"
+	newMonitor := func(name string) *pb.MonitorDefinition {
+		return &pb.MonitorDefinition{
+			Id:       uuid.NewString(),
+			Name:     name,
+			ConfigId: configId,
+			Source:   pb.MonitorDefinition_SOURCE_API,
+			MonitoredId: &entitiesv1.Identifier{
+				Id: &entitiesv1.Identifier_SynqPath{
+					SynqPath: &entitiesv1.SynqPathIdentifier{Path: "mysql-host::schema::table"},
+				},
+			},
+			Monitor: &pb.MonitorDefinition_Volume{Volume: &pb.MonitorVolume{}},
+		}
+	}
+
+	kept := newMonitor("kept")
+	deleted := newMonitor("deleted")
+	protected := newMonitor("protected")
+	changes, err := GenerateConfigChangesOverview(
+		configId,
+		[]*pb.MonitorDefinition{kept},
+		nil,
+		map[string]*pb.MonitorDefinition{kept.Id: kept, deleted.Id: deleted, protected.Id: protected},
+	)
+	s.Require().NoError(err)
+	s.Empty(changes.GetBreakingChanges())
+
+	changes.PreventDestroy([]string{kept.Id, protected.Id})
+	changes.PreventDestroy([]string{protected.Id})
+	s.Equal([]string{protected.Id}, changes.MonitorsProtected)
+	s.Equal("  🛡️ 1 monitors to delete are protected by prevent_destroy.\n     - Monitor ID: "+protected.Id+", Name: protected", changes.GetBreakingChanges())
+
+	summary := changes.Summary()
+	s.True(summary.BreakingChanges)
+	protectedChanges := lo.Filter(summary.Changes, func(change *Change, _ int) bool { return change.Protected })
+	s.Require().Len(protectedChanges, 1)
+	s.Equal(protected.Id, protectedChanges[0].Id)
+}
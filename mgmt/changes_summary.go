@@ -0,0 +1,177 @@
+package mgmt
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+type ChangeAction string
+
+const (
+	ChangeAction_Create   ChangeAction = "create"
+	ChangeAction_Update   ChangeAction = "update"
+	ChangeAction_Move     ChangeAction = "move"
+	ChangeAction_Delete   ChangeAction = "delete"
+	ChangeAction_Conflict ChangeAction = "conflict"
+)
+
+type ChangeKind string
+
+const (
+	ChangeKind_Monitor ChangeKind = "monitor"
+	ChangeKind_Test    ChangeKind = "test"
+)
+
+type ChangesCounts struct {
+	MonitorsToCreate             int `json:"monitors_to_create"               yaml:"monitors_to_create"`
+	MonitorsToUpdate             int `json:"monitors_to_update"               yaml:"monitors_to_update"`
+	MonitorsToMove               int `json:"monitors_to_move"                 yaml:"monitors_to_move"`
+	MonitorsToDelete             int `json:"monitors_to_delete"               yaml:"monitors_to_delete"`
+	MonitorsUnchanged            int `json:"monitors_unchanged"               yaml:"monitors_unchanged"`
+	MonitorsManagedByApp         int `json:"monitors_managed_by_app"          yaml:"monitors_managed_by_app"`
+	MonitorsManagedByOtherConfig int `json:"monitors_managed_by_other_config" yaml:"monitors_managed_by_other_config"`
+	TestsToCreate                int `json:"tests_to_create"                  yaml:"tests_to_create"`
+	TestsToUpdate                int `json:"tests_to_update"                  yaml:"tests_to_update"`
+	TestsToDelete                int `json:"tests_to_delete"                  yaml:"tests_to_delete"`
+	TestsUnchanged               int `json:"tests_unchanged"                  yaml:"tests_unchanged"`
+	TestsManagedByOtherConfig    int `json:"tests_managed_by_other_config"    yaml:"tests_managed_by_other_config"`
+}
+
+// Change is a single monitor or test which the overview would create, update, move or delete,
+// or which cannot be taken over because another namespace manages it.
+type Change struct {
+	Kind   ChangeKind   `json:"kind"                         yaml:"kind"`
+	Action ChangeAction `json:"action"                       yaml:"action"`
+	Id     string       `json:"id"                           yaml:"id"`
+	Name   string       `json:"name,omitempty"               yaml:"name,omitempty"`
+	Type   string       `json:"type,omitempty"               yaml:"type,omitempty"`
+	// Fields lists the top level fields changed by an update, as named in the JSON delta.
+	Fields        []string `json:"fields,omitempty"          yaml:"fields,omitempty"`
+	Reset         bool     `json:"reset,omitempty"           yaml:"reset,omitempty"`
+	ResetReasons  []string `json:"reset_reasons,omitempty"   yaml:"reset_reasons,omitempty"`
+	ManagedByApp  bool     `json:"managed_by_app,omitempty"  yaml:"managed_by_app,omitempty"`
+	Protected     bool     `json:"protected,omitempty"       yaml:"protected,omitempty"`
+	OtherConfigId string   `json:"other_config_id,omitempty" yaml:"other_config_id,omitempty"`
+}
+
+// ChangesSummary is the machine readable counterpart of PrettyPrint.
+type ChangesSummary struct {
+	Namespace       string        `json:"namespace"        yaml:"namespace"`
+	HasChanges      bool          `json:"has_changes"      yaml:"has_changes"`
+	BreakingChanges bool          `json:"breaking_changes" yaml:"breaking_changes"`
+	Counts          ChangesCounts `json:"counts"           yaml:"counts"`
+	Changes         []*Change     `json:"changes"          yaml:"changes"`
+}
+
+func (s *ChangesOverview) Summary() *ChangesSummary {
+	monitorsToUpdate, monitorsToMove := s.splitMoves()
+	summary := &ChangesSummary{
+		Namespace:       s.ConfigID,
+		HasChanges:      s.HasChanges(),
+		BreakingChanges: len(s.GetBreakingChanges()) > 0,
+		Counts: ChangesCounts{
+			MonitorsToCreate:             len(s.MonitorsToCreate),
+			MonitorsToUpdate:             len(monitorsToUpdate),
+			MonitorsToMove:               len(monitorsToMove),
+			MonitorsToDelete:             len(s.MonitorsToDelete),
+			MonitorsUnchanged:            len(s.MonitorsUnchanged),
+			MonitorsManagedByApp:         len(s.MonitorsManagedByApp),
+			MonitorsManagedByOtherConfig: len(s.MonitorsManagedByOtherConfig),
+			TestsToCreate:                len(s.SqlTestsToCreate),
+			TestsToUpdate:                len(s.SqlTestsChangesOverview),
+			TestsToDelete:                len(s.SqlTestsToDelete),
+			TestsUnchanged:               len(s.SqlTestsUnchanged),
+			TestsManagedByOtherConfig:    len(s.SqlTestsManagedByOtherConfig),
+		},
+		Changes: []*Change{},
+	}
+
+	for _, monitor := range s.MonitorsToCreate {
+		summary.Changes = append(summary.Changes, &Change{
+			Kind: ChangeKind_Monitor, Action: ChangeAction_Create, Id: monitor.Id, Name: monitor.Name, Type: s.getMonitorType(monitor),
+		})
+	}
+	for _, change := range s.MonitorsChangesOverview {
+		definition := change.NewDefinition
+		if definition == nil {
+			definition = change.OriginDefinition
+		}
+		action := ChangeAction_Update
+		if slices.Contains(monitorsToMove, change) {
+			action = ChangeAction_Move
+		}
+		summary.Changes = append(summary.Changes, &Change{
+			Kind:         ChangeKind_Monitor,
+			Action:       action,
+			Id:           change.MonitorId,
+			Name:         definition.GetName(),
+			Type:         s.getMonitorType(definition),
+			Fields:       changedFields(change.ChangesDeltaJson),
+			Reset:        change.ShouldReset,
+			ResetReasons: s.MonitorsResetReasons[change.MonitorId],
+			ManagedByApp: slices.Contains(s.MonitorsManagedByApp, change.MonitorId),
+		})
+	}
+	for _, monitor := range s.MonitorsToDelete {
+		summary.Changes = append(summary.Changes, &Change{
+			Kind:      ChangeKind_Monitor,
+			Action:    ChangeAction_Delete,
+			Id:        monitor.Id,
+			Name:      monitor.Name,
+			Type:      s.getMonitorType(monitor),
+			Protected: slices.Contains(s.MonitorsProtected, monitor.Id),
+		})
+	}
+	for _, monitorId := range sortedKeys(s.MonitorsManagedByOtherConfig) {
+		summary.Changes = append(summary.Changes, &Change{
+			Kind: ChangeKind_Monitor, Action: ChangeAction_Conflict, Id: monitorId, OtherConfigId: s.MonitorsManagedByOtherConfig[monitorId],
+		})
+	}
+
+	for _, sqlTest := range s.SqlTestsToCreate {
+		summary.Changes = append(summary.Changes, &Change{
+			Kind: ChangeKind_Test, Action: ChangeAction_Create, Id: sqlTest.Id, Name: sqlTest.Name, Type: getSqlTestType(sqlTest),
+		})
+	}
+	for _, change := range s.SqlTestsChangesOverview {
+		summary.Changes = append(summary.Changes, &Change{
+			Kind:   ChangeKind_Test,
+			Action: ChangeAction_Update,
+			Id:     change.SqlTestId,
+			Name:   change.NewDefinition.GetName(),
+			Type:   getSqlTestType(change.NewDefinition),
+			Fields: changedFields(change.ChangesDeltaJson),
+		})
+	}
+	for _, sqlTest := range s.SqlTestsToDelete {
+		summary.Changes = append(summary.Changes, &Change{
+			Kind: ChangeKind_Test, Action: ChangeAction_Delete, Id: sqlTest.Id, Name: sqlTest.Name, Type: getSqlTestType(sqlTest),
+		})
+	}
+	for _, sqlTestId := range sortedKeys(s.SqlTestsManagedByOtherConfig) {
+		summary.Changes = append(summary.Changes, &Change{
+			Kind: ChangeKind_Test, Action: ChangeAction_Conflict, Id: sqlTestId, OtherConfigId: s.SqlTestsManagedByOtherConfig[sqlTestId],
+		})
+	}
+
+	return summary
+}
+
+// changedFields returns the top level fields of a JSON delta, sorted.
+func changedFields(deltaJson string) []string {
+	delta := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(deltaJson), &delta); err != nil {
+		return nil
+	}
+	fields := lo.Keys(delta)
+	slices.Sort(fields)
+	return fields
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := lo.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
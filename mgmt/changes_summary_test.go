@@ -0,0 +1,65 @@
+package mgmt
+
+import (
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *MgmtServiceTestSuite) TestSummary() {
+	configId := "config-id"
+	newMonitor := func(name string) *pb.MonitorDefinition {
+		return &pb.MonitorDefinition{
+			Id:       uuid.NewString(),
+			Name:     name,
+			ConfigId: configId,
+			Source:   pb.MonitorDefinition_SOURCE_API,
+			MonitoredId: &entitiesv1.Identifier{
+				Id: &entitiesv1.Identifier_SynqPath{
+					SynqPath: &entitiesv1.SynqPathIdentifier{Path: "mysql-host::schema::table"},
+				},
+			},
+			Monitor: &pb.MonitorDefinition_Volume{Volume: &pb.MonitorVolume{}},
+		}
+	}
+
+	toUpdate := newMonitor("to_update")
+	updated := proto.Clone(toUpdate).(*pb.MonitorDefinition)
+	updated.Timezone = "UTC"
+	toDelete := newMonitor("to_delete")
+	toCreate := newMonitor("to_create")
+	otherConfig := newMonitor("other_config")
+	claimed := proto.Clone(otherConfig).(*pb.MonitorDefinition)
+	otherConfig.ConfigId = "other"
+
+	changes, err := GenerateConfigChangesOverview(
+		configId,
+		[]*pb.MonitorDefinition{updated, toCreate, claimed},
+		nil,
+		map[string]*pb.MonitorDefinition{toUpdate.Id: toUpdate, toDelete.Id: toDelete, otherConfig.Id: otherConfig},
+	)
+	s.Require().NoError(err)
+	sqlTest := &sqltestsv1.SqlTest{Id: uuid.NewString(), Name: "not_null", ConfigId: configId, Template: &sqltestsv1.SqlTest_NotNull{}}
+	s.Require().NoError(changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{sqlTest}, map[string]*sqltestsv1.SqlTest{}))
+
+	summary := changes.Summary()
+	s.Equal(configId, summary.Namespace)
+	s.True(summary.HasChanges)
+	s.True(summary.BreakingChanges)
+	s.Equal(ChangesCounts{
+		MonitorsToCreate:             1,
+		MonitorsToUpdate:             1,
+		MonitorsToDelete:             1,
+		MonitorsManagedByOtherConfig: 1,
+		TestsToCreate:                1,
+	}, summary.Counts)
+	s.Equal([]*Change{
+		{Kind: ChangeKind_Monitor, Action: ChangeAction_Create, Id: toCreate.Id, Name: "to_create", Type: "volume"},
+		{Kind: ChangeKind_Monitor, Action: ChangeAction_Update, Id: toUpdate.Id, Name: "to_update", Type: "volume", Fields: []string{"timezone"}, Reset: true, ResetReasons: []string{"timezone"}},
+		{Kind: ChangeKind_Monitor, Action: ChangeAction_Delete, Id: toDelete.Id, Name: "to_delete", Type: "volume"},
+		{Kind: ChangeKind_Monitor, Action: ChangeAction_Conflict, Id: otherConfig.Id, OtherConfigId: "other"},
+		{Kind: ChangeKind_Test, Action: ChangeAction_Create, Id: sqlTest.Id, Name: "not_null", Type: "not_null"},
+	}, summary.Changes)
+}
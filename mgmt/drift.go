@@ -0,0 +1,113 @@
+package mgmt
+
+import (
+	"fmt"
+	"slices"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/proto"
+)
+
+// Drift compares the remote state the overview was computed against with freshly fetched monitors and tests.
+// Every difference is described in the result, an empty result means the overview can still be applied as is.
+func (s *ChangesOverview) Drift(
+	fetchedMonitors map[string]*pb.MonitorDefinition,
+	fetchedSqlTests map[string]*sqltestsv1.SqlTest,
+) []string {
+	drift := []string{}
+
+	expectMissing := func(kind, id, name string, fetched proto.Message) {
+		if !isNil(fetched) {
+			drift = append(drift, fmt.Sprintf("%s '%s' (%s) was created since the plan", kind, name, id))
+		}
+	}
+	expectEqual := func(kind, id, name string, recorded, fetched proto.Message) {
+		if isNil(fetched) {
+			drift = append(drift, fmt.Sprintf("%s '%s' (%s) was deleted since the plan", kind, name, id))
+		} else if !proto.Equal(recorded, fetched) {
+			drift = append(drift, fmt.Sprintf("%s '%s' (%s) was changed since the plan", kind, name, id))
+		}
+	}
+
+	for _, monitor := range s.MonitorsToCreate {
+		expectMissing("monitor", monitor.Id, monitor.Name, fetchedMonitors[monitor.Id])
+	}
+	for _, monitor := range s.MonitorsToDelete {
+		expectEqual("monitor", monitor.Id, monitor.Name, monitor, fetchedMonitors[monitor.Id])
+	}
+	for _, change := range s.MonitorsChangesOverview {
+		expectEqual("monitor", change.MonitorId, change.OriginDefinition.GetName(), change.OriginDefinition, fetchedMonitors[change.MonitorId])
+	}
+	for _, monitor := range s.MonitorsUnchanged {
+		expectEqual("monitor", monitor.Id, monitor.Name, monitor, fetchedMonitors[monitor.Id])
+	}
+	for monitorId, configId := range s.MonitorsManagedByOtherConfig {
+		if fetched := fetchedMonitors[monitorId]; fetched == nil || fetched.ConfigId != configId {
+			drift = append(drift, fmt.Sprintf("monitor %s is no longer managed by namespace '%s'", monitorId, configId))
+		}
+	}
+
+	for _, sqlTest := range s.SqlTestsToCreate {
+		expectMissing("test", sqlTest.Id, sqlTest.Name, fetchedSqlTests[sqlTest.Id])
+	}
+	for _, sqlTest := range s.SqlTestsToDelete {
+		expectEqual("test", sqlTest.Id, sqlTest.Name, sqlTest, fetchedSqlTests[sqlTest.Id])
+	}
+	for _, change := range s.SqlTestsChangesOverview {
+		expectEqual("test", change.SqlTestId, change.OriginDefinition.GetName(), change.OriginDefinition, fetchedSqlTests[change.SqlTestId])
+	}
+	for _, sqlTest := range s.SqlTestsUnchanged {
+		expectEqual("test", sqlTest.Id, sqlTest.Name, sqlTest, fetchedSqlTests[sqlTest.Id])
+	}
+	for sqlTestId, configId := range s.SqlTestsManagedByOtherConfig {
+		if fetched := fetchedSqlTests[sqlTestId]; fetched == nil || fetched.ConfigId != configId {
+			drift = append(drift, fmt.Sprintf("test %s is no longer managed by namespace '%s'", sqlTestId, configId))
+		}
+	}
+
+	// Anything added to the namespace since the plan would be left untouched by it
+	referencedMonitorIds := lo.SliceToMap(s.referencedMonitorIds(), func(id string) (string, bool) { return id, true })
+	for id, monitor := range fetchedMonitors {
+		if monitor.ConfigId == s.ConfigID && monitor.Source == pb.MonitorDefinition_SOURCE_API && !referencedMonitorIds[id] {
+			drift = append(drift, fmt.Sprintf("monitor '%s' (%s) was added to the namespace since the plan", monitor.Name, id))
+		}
+	}
+	referencedSqlTestIds := lo.SliceToMap(s.referencedSqlTestIds(), func(id string) (string, bool) { return id, true })
+	for id, sqlTest := range fetchedSqlTests {
+		if sqlTest.ConfigId == s.ConfigID && !referencedSqlTestIds[id] {
+			drift = append(drift, fmt.Sprintf("test '%s' (%s) was added to the namespace since the plan", sqlTest.Name, id))
+		}
+	}
+
+	slices.Sort(drift)
+	return drift
+}
+
+func (s *ChangesOverview) referencedMonitorIds() []string {
+	ids := []string{}
+	ids = append(ids, monitorIds(s.MonitorsToCreate)...)
+	ids = append(ids, monitorIds(s.MonitorsToDelete)...)
+	ids = append(ids, monitorIds(s.MonitorsUnchanged)...)
+	for _, change := range s.MonitorsChangesOverview {
+		ids = append(ids, change.MonitorId)
+	}
+	return append(ids, lo.Keys(s.MonitorsManagedByOtherConfig)...)
+}
+
+func (s *ChangesOverview) referencedSqlTestIds() []string {
+	ids := []string{}
+	ids = append(ids, sqlTestIds(s.SqlTestsToCreate)...)
+	ids = append(ids, sqlTestIds(s.SqlTestsToDelete)...)
+	ids = append(ids, sqlTestIds(s.SqlTestsUnchanged)...)
+	for _, change := range s.SqlTestsChangesOverview {
+		ids = append(ids, change.SqlTestId)
+	}
+	return append(ids, lo.Keys(s.SqlTestsManagedByOtherConfig)...)
+}
+
+// isNil reports whether a message taken from a map of typed pointers is missing.
+func isNil(message proto.Message) bool {
+	return message == nil || !message.ProtoReflect().IsValid()
+}
@@ -0,0 +1,80 @@
+package mgmt
+
+import (
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *MgmtServiceTestSuite) TestDrift() {
+	configId := "config-id"
+	newMonitor := func(name string) *pb.MonitorDefinition {
+		return &pb.MonitorDefinition{
+			Id:       uuid.NewString(),
+			Name:     name,
+			ConfigId: configId,
+			Source:   pb.MonitorDefinition_SOURCE_API,
+			MonitoredId: &entitiesv1.Identifier{
+				Id: &entitiesv1.Identifier_SynqPath{
+					SynqPath: &entitiesv1.SynqPathIdentifier{Path: "mysql-host::schema::table"},
+				},
+			},
+			Monitor: &pb.MonitorDefinition_Volume{Volume: &pb.MonitorVolume{}},
+		}
+	}
+
+	toUpdate := newMonitor("to_update")
+	updated := proto.Clone(toUpdate).(*pb.MonitorDefinition)
+	updated.Timezone = "UTC"
+	toDelete := newMonitor("to_delete")
+	toCreate := newMonitor("to_create")
+
+	remote := func() map[string]*pb.MonitorDefinition {
+		return map[string]*pb.MonitorDefinition{
+			toUpdate.Id: proto.Clone(toUpdate).(*pb.MonitorDefinition),
+			toDelete.Id: proto.Clone(toDelete).(*pb.MonitorDefinition),
+		}
+	}
+
+	changes, err := GenerateConfigChangesOverview(configId, []*pb.MonitorDefinition{updated, toCreate}, nil, remote())
+	s.Require().NoError(err)
+	s.Require().NoError(changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{}, map[string]*sqltestsv1.SqlTest{}))
+
+	s.Run("no_drift", func() {
+		s.Empty(changes.Drift(remote(), map[string]*sqltestsv1.SqlTest{}))
+	})
+
+	s.Run("origin_changed", func() {
+		fetched := remote()
+		fetched[toUpdate.Id].Name = "renamed"
+		s.Equal(
+			[]string{"monitor 'to_update' (" + toUpdate.Id + ") was changed since the plan"},
+			changes.Drift(fetched, map[string]*sqltestsv1.SqlTest{}),
+		)
+	})
+
+	s.Run("deleted_and_created", func() {
+		fetched := remote()
+		delete(fetched, toDelete.Id)
+		fetched[toCreate.Id] = toCreate
+		s.ElementsMatch(
+			[]string{
+				"monitor 'to_delete' (" + toDelete.Id + ") was deleted since the plan",
+				"monitor 'to_create' (" + toCreate.Id + ") was created since the plan",
+			},
+			changes.Drift(fetched, map[string]*sqltestsv1.SqlTest{}),
+		)
+	})
+
+	s.Run("added_to_namespace", func() {
+		fetched := remote()
+		added := newMonitor("added")
+		fetched[added.Id] = added
+		s.Equal(
+			[]string{"monitor 'added' (" + added.Id + ") was added to the namespace since the plan"},
+			changes.Drift(fetched, map[string]*sqltestsv1.SqlTest{}),
+		)
+	})
+}
@@ -0,0 +1,44 @@
+package mgmt
+
+import (
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/samber/lo"
+)
+
+// MonitorMoves maps the UUID of a monitor to the UUIDs it was previously deployed with,
+// as declared with moved_from in the YAML configuration.
+type MonitorMoves map[string][]string
+
+func (m MonitorMoves) previousIds() []string {
+	return lo.Uniq(lo.Flatten(lo.Values(m)))
+}
+
+// moveMonitors gives its previous UUID to every requested monitor which is not deployed under its own UUID,
+// but still is under a previous one, so that it is updated in place and keeps its history.
+// It returns the previous UUIDs kept, mapped to the UUIDs they replace.
+func moveMonitors(
+	protoMonitors []*pb.MonitorDefinition,
+	moves MonitorMoves,
+	fetchedMonitors map[string]*pb.MonitorDefinition,
+) map[string]string {
+	claimed := lo.SliceToMap(protoMonitors, func(monitor *pb.MonitorDefinition) (string, bool) {
+		return monitor.Id, true
+	})
+
+	moved := map[string]string{}
+	for _, monitor := range protoMonitors {
+		if fetchedMonitors[monitor.Id] != nil {
+			continue
+		}
+		for _, previousId := range moves[monitor.Id] {
+			if fetchedMonitors[previousId] == nil || claimed[previousId] {
+				continue
+			}
+			moved[previousId] = monitor.Id
+			claimed[previousId] = true
+			monitor.Id = previousId
+			break
+		}
+	}
+	return moved
+}
@@ -0,0 +1,109 @@
+package mgmt
+
+import (
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *MgmtServiceTestSuite) TestMonitorMoves() {
+	configId := "config-id"
+	newMonitor := func(name, configId string) *pb.MonitorDefinition {
+		return &pb.MonitorDefinition{
+			Id:       uuid.NewString(),
+			Name:     name,
+			ConfigId: configId,
+			Source:   pb.MonitorDefinition_SOURCE_API,
+			MonitoredId: &entitiesv1.Identifier{
+				Id: &entitiesv1.Identifier_SynqPath{
+					SynqPath: &entitiesv1.SynqPathIdentifier{Path: "mysql-host::schema::table"},
+				},
+			},
+			Monitor: &pb.MonitorDefinition_Volume{Volume: &pb.MonitorVolume{}},
+		}
+	}
+	renamed := func(previous *pb.MonitorDefinition) *pb.MonitorDefinition {
+		monitor := proto.Clone(previous).(*pb.MonitorDefinition)
+		monitor.Id = uuid.NewString()
+		monitor.Name = "renamed"
+		monitor.ConfigId = configId
+		return monitor
+	}
+
+	s.Run("renamed", func() {
+		previous := newMonitor("volume", configId)
+		monitor := renamed(previous)
+		newId := monitor.Id
+
+		changes, err := GenerateConfigChangesOverview(
+			configId,
+			[]*pb.MonitorDefinition{monitor},
+			MonitorMoves{newId: {previous.Id}},
+			map[string]*pb.MonitorDefinition{previous.Id: previous},
+		)
+		s.Require().NoError(err)
+		s.Empty(changes.MonitorsToCreate)
+		s.Empty(changes.MonitorsToDelete)
+		s.Equal(map[string]string{previous.Id: newId}, changes.MonitorsMoved)
+		s.Require().Len(changes.MonitorsChangesOverview, 1)
+		s.Equal(previous.Id, changes.MonitorsChangesOverview[0].MonitorId)
+		s.Equal(previous.Id, changes.MonitorsChangesOverview[0].NewDefinition.Id)
+
+		summary := changes.Summary()
+		s.Equal(1, summary.Counts.MonitorsToMove)
+		s.Equal(0, summary.Counts.MonitorsToUpdate)
+		s.Equal([]*Change{
+			{Kind: ChangeKind_Monitor, Action: ChangeAction_Move, Id: previous.Id, Name: "renamed", Type: "volume", Fields: []string{"name"}},
+		}, summary.Changes)
+	})
+
+	s.Run("from_another_namespace", func() {
+		previous := newMonitor("volume", "previous-config-id")
+		monitor := renamed(previous)
+
+		changes, err := GenerateConfigChangesOverview(
+			configId,
+			[]*pb.MonitorDefinition{monitor},
+			MonitorMoves{monitor.Id: {previous.Id}},
+			map[string]*pb.MonitorDefinition{previous.Id: previous},
+		)
+		s.Require().NoError(err)
+		s.Empty(changes.MonitorsManagedByOtherConfig)
+		s.Empty(changes.GetBreakingChanges())
+		s.Require().Len(changes.MonitorsChangesOverview, 1)
+		s.Equal(configId, changes.MonitorsChangesOverview[0].NewDefinition.ConfigId)
+	})
+
+	s.Run("already_deployed", func() {
+		previous := newMonitor("volume", configId)
+		monitor := renamed(previous)
+		deployed := proto.Clone(monitor).(*pb.MonitorDefinition)
+
+		changes, err := GenerateConfigChangesOverview(
+			configId,
+			[]*pb.MonitorDefinition{monitor},
+			MonitorMoves{monitor.Id: {previous.Id}},
+			map[string]*pb.MonitorDefinition{previous.Id: previous, deployed.Id: deployed},
+		)
+		s.Require().NoError(err)
+		s.Empty(changes.MonitorsMoved)
+		s.Equal([]string{previous.Id}, monitorIds(changes.MonitorsToDelete))
+		s.Equal([]string{deployed.Id}, monitorIds(changes.MonitorsUnchanged))
+	})
+
+	s.Run("previous_still_requested", func() {
+		previous := newMonitor("volume", configId)
+		monitor := renamed(previous)
+
+		changes, err := GenerateConfigChangesOverview(
+			configId,
+			[]*pb.MonitorDefinition{previous, monitor},
+			MonitorMoves{monitor.Id: {previous.Id}},
+			map[string]*pb.MonitorDefinition{previous.Id: previous},
+		)
+		s.Require().NoError(err)
+		s.Empty(changes.MonitorsMoved)
+		s.Equal([]string{monitor.Id}, monitorIds(changes.MonitorsToCreate))
+	})
+}
@@ -0,0 +1,223 @@
+package mgmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const PlanVersion = "v1"
+
+// Plan is the reviewable result of computing the changes overview of every namespace,
+// written to disk by `plan` so that it can be attached to a merge request.
+type Plan struct {
+	Version   string    `json:"version"`
+	Workspace string    `json:"workspace"`
+	CreatedAt time.Time `json:"created_at"`
+	// InputHash is the hash of all YAML files the plan was computed from.
+	InputHash  string             `json:"input_hash"`
+	Files      []string           `json:"files"`
+	Namespaces []*ChangesOverview `json:"namespaces"`
+}
+
+func (p *Plan) WriteFile(path string) error {
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+func ReadPlanFile(path string) (*Plan, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan Plan
+	err = json.Unmarshal(content, &plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if plan.Version != PlanVersion {
+		return nil, fmt.Errorf("plan %s has version %q, only %q is supported", path, plan.Version, PlanVersion)
+	}
+
+	return &plan, nil
+}
+
+type changesOverviewJson struct {
+	Namespace                    string                       `json:"namespace"`
+	HasChanges                   bool                         `json:"has_changes"`
+	BreakingChanges              string                       `json:"breaking_changes,omitempty"`
+	MonitorsToCreate             []json.RawMessage            `json:"monitors_to_create"`
+	MonitorsToDelete             []json.RawMessage            `json:"monitors_to_delete"`
+	MonitorsToUpdate             []json.RawMessage            `json:"monitors_to_update"`
+	MonitorsUnchanged            []json.RawMessage            `json:"monitors_unchanged"`
+	MonitorsManagedByApp         []string                     `json:"monitors_managed_by_app"`
+	MonitorsManagedByOtherConfig map[string]string            `json:"monitors_managed_by_other_config"`
+	MonitorsMoved                map[string]string            `json:"monitors_moved,omitempty"`
+	MonitorsProtected            []string                     `json:"monitors_protected,omitempty"`
+	MonitorsResetReasons         map[string][]string          `json:"monitors_reset_reasons,omitempty"`
+	SqlTestsToCreate             []json.RawMessage            `json:"sql_tests_to_create"`
+	SqlTestsToDelete             []json.RawMessage            `json:"sql_tests_to_delete"`
+	SqlTestsToUpdate             []*sqlTestChangeOverviewJson `json:"sql_tests_to_update"`
+	SqlTestsUnchanged            []json.RawMessage            `json:"sql_tests_unchanged"`
+	SqlTestsManagedByOtherConfig map[string]string            `json:"sql_tests_managed_by_other_config"`
+}
+
+type sqlTestChangeOverviewJson struct {
+	SqlTestId        string          `json:"sql_test_id"`
+	OriginDefinition json.RawMessage `json:"origin_definition"`
+	NewDefinition    json.RawMessage `json:"new_definition"`
+	Changes          string          `json:"changes"`
+	ChangesDeltaJson string          `json:"changes_delta_json"`
+}
+
+func (s *ChangesOverview) MarshalJSON() ([]byte, error) {
+	var err error
+	out := &changesOverviewJson{
+		Namespace:                    s.ConfigID,
+		HasChanges:                   s.HasChanges(),
+		BreakingChanges:              s.GetBreakingChanges(),
+		MonitorsManagedByApp:         s.MonitorsManagedByApp,
+		MonitorsManagedByOtherConfig: s.MonitorsManagedByOtherConfig,
+		MonitorsMoved:                s.MonitorsMoved,
+		MonitorsProtected:            s.MonitorsProtected,
+		MonitorsResetReasons:         s.MonitorsResetReasons,
+		SqlTestsManagedByOtherConfig: s.SqlTestsManagedByOtherConfig,
+		SqlTestsToUpdate:             []*sqlTestChangeOverviewJson{},
+	}
+
+	if out.MonitorsToCreate, err = marshalProtos(s.MonitorsToCreate); err != nil {
+		return nil, err
+	}
+	if out.MonitorsToDelete, err = marshalProtos(s.MonitorsToDelete); err != nil {
+		return nil, err
+	}
+	if out.MonitorsToUpdate, err = marshalProtos(s.MonitorsChangesOverview); err != nil {
+		return nil, err
+	}
+	if out.MonitorsUnchanged, err = marshalProtos(s.MonitorsUnchanged); err != nil {
+		return nil, err
+	}
+	if out.SqlTestsToCreate, err = marshalProtos(s.SqlTestsToCreate); err != nil {
+		return nil, err
+	}
+	if out.SqlTestsToDelete, err = marshalProtos(s.SqlTestsToDelete); err != nil {
+		return nil, err
+	}
+	if out.SqlTestsUnchanged, err = marshalProtos(s.SqlTestsUnchanged); err != nil {
+		return nil, err
+	}
+	for _, change := range s.SqlTestsChangesOverview {
+		origin, err := protojson.Marshal(change.OriginDefinition)
+		if err != nil {
+			return nil, err
+		}
+		newDefinition, err := protojson.Marshal(change.NewDefinition)
+		if err != nil {
+			return nil, err
+		}
+		out.SqlTestsToUpdate = append(out.SqlTestsToUpdate, &sqlTestChangeOverviewJson{
+			SqlTestId:        change.SqlTestId,
+			OriginDefinition: origin,
+			NewDefinition:    newDefinition,
+			Changes:          change.Changes,
+			ChangesDeltaJson: change.ChangesDeltaJson,
+		})
+	}
+
+	return json.Marshal(out)
+}
+
+func (s *ChangesOverview) UnmarshalJSON(data []byte) error {
+	var in changesOverviewJson
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+
+	*s = ChangesOverview{
+		ConfigID:                     in.Namespace,
+		MonitorsManagedByApp:         in.MonitorsManagedByApp,
+		MonitorsManagedByOtherConfig: in.MonitorsManagedByOtherConfig,
+		MonitorsMoved:                in.MonitorsMoved,
+		MonitorsProtected:            in.MonitorsProtected,
+		MonitorsResetReasons:         in.MonitorsResetReasons,
+		SqlTestsManagedByOtherConfig: in.SqlTestsManagedByOtherConfig,
+		SqlTestsChangesOverview:      []*SqlTestChangeOverview{},
+	}
+
+	if s.MonitorsToCreate, err = unmarshalProtos[*pb.MonitorDefinition](in.MonitorsToCreate); err != nil {
+		return err
+	}
+	if s.MonitorsToDelete, err = unmarshalProtos[*pb.MonitorDefinition](in.MonitorsToDelete); err != nil {
+		return err
+	}
+	if s.MonitorsChangesOverview, err = unmarshalProtos[*pb.ChangeOverview](in.MonitorsToUpdate); err != nil {
+		return err
+	}
+	if s.MonitorsUnchanged, err = unmarshalProtos[*pb.MonitorDefinition](in.MonitorsUnchanged); err != nil {
+		return err
+	}
+	if s.SqlTestsToCreate, err = unmarshalProtos[*sqltestsv1.SqlTest](in.SqlTestsToCreate); err != nil {
+		return err
+	}
+	if s.SqlTestsToDelete, err = unmarshalProtos[*sqltestsv1.SqlTest](in.SqlTestsToDelete); err != nil {
+		return err
+	}
+	if s.SqlTestsUnchanged, err = unmarshalProtos[*sqltestsv1.SqlTest](in.SqlTestsUnchanged); err != nil {
+		return err
+	}
+	for _, change := range in.SqlTestsToUpdate {
+		origin, newDefinition := &sqltestsv1.SqlTest{}, &sqltestsv1.SqlTest{}
+		if err := protojson.Unmarshal(change.OriginDefinition, origin); err != nil {
+			return err
+		}
+		if err := protojson.Unmarshal(change.NewDefinition, newDefinition); err != nil {
+			return err
+		}
+		s.SqlTestsChangesOverview = append(s.SqlTestsChangesOverview, &SqlTestChangeOverview{
+			SqlTestId:        change.SqlTestId,
+			OriginDefinition: origin,
+			NewDefinition:    newDefinition,
+			Changes:          change.Changes,
+			ChangesDeltaJson: change.ChangesDeltaJson,
+		})
+	}
+
+	return nil
+}
+
+func marshalProtos[T proto.Message](messages []T) ([]json.RawMessage, error) {
+	out := make([]json.RawMessage, 0, len(messages))
+	for _, message := range messages {
+		raw, err := protojson.Marshal(message)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, raw)
+	}
+	return out, nil
+}
+
+func unmarshalProtos[T proto.Message](raws []json.RawMessage) ([]T, error) {
+	out := make([]T, 0, len(raws))
+	for _, raw := range raws {
+		var message T
+		message = message.ProtoReflect().Type().New().Interface().(T)
+		err := protojson.Unmarshal(raw, message)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, message)
+	}
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package mgmt
+
+import (
+	"path/filepath"
+	"time"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *MgmtServiceTestSuite) TestPlanRoundTrip() {
+	configId := "config-id"
+	monitoredId := &entitiesv1.Identifier{
+		Id: &entitiesv1.Identifier_SynqPath{
+			SynqPath: &entitiesv1.SynqPathIdentifier{
+				Path: "mysql-host::schema::table",
+			},
+		},
+	}
+
+	existingMonitor := &pb.MonitorDefinition{
+		Id:          uuid.NewString(),
+		Name:        "volume",
+		ConfigId:    configId,
+		MonitoredId: monitoredId,
+		Source:      pb.MonitorDefinition_SOURCE_API,
+		Timezone:    "UTC",
+		Monitor:     &pb.MonitorDefinition_Volume{Volume: &pb.MonitorVolume{}},
+	}
+	updatedMonitor := proto.Clone(existingMonitor).(*pb.MonitorDefinition)
+	updatedMonitor.Timezone = "Europe/Berlin"
+	deletedMonitor := &pb.MonitorDefinition{
+		Id:          uuid.NewString(),
+		Name:        "freshness",
+		ConfigId:    configId,
+		MonitoredId: monitoredId,
+		Source:      pb.MonitorDefinition_SOURCE_API,
+		Monitor:     &pb.MonitorDefinition_Freshness{Freshness: &pb.MonitorFreshness{Expression: "updated_at"}},
+	}
+	createdSqlTest := &sqltestsv1.SqlTest{
+		Id:          uuid.NewString(),
+		Name:        "not_null_id",
+		ConfigId:    configId,
+		MonitoredId: monitoredId,
+		Template:    &sqltestsv1.SqlTest_NotNull{NotNull: &sqltestsv1.NotNullTest{ColumnNames: []string{"id"}}},
+	}
+
+	changes, err := GenerateConfigChangesOverview(configId, []*pb.MonitorDefinition{updatedMonitor}, nil, map[string]*pb.MonitorDefinition{
+		existingMonitor.Id: existingMonitor,
+		deletedMonitor.Id:  deletedMonitor,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{createdSqlTest}, map[string]*sqltestsv1.SqlTest{}))
+
+	plan := &Plan{
+		Version:    PlanVersion,
+		Workspace:  "workspace",
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+		InputHash:  "sha256:abc",
+		Files:      []string{"monitors.yaml"},
+		Namespaces: []*ChangesOverview{changes},
+	}
+
+	path := filepath.Join(s.T().TempDir(), "plan.json")
+	s.Require().NoError(plan.WriteFile(path))
+
+	readPlan, err := ReadPlanFile(path)
+	s.Require().NoError(err)
+	s.Equal(plan.Workspace, readPlan.Workspace)
+	s.Equal(plan.InputHash, readPlan.InputHash)
+	s.True(plan.CreatedAt.Equal(readPlan.CreatedAt))
+	s.Require().Len(readPlan.Namespaces, 1)
+
+	readChanges := readPlan.Namespaces[0]
+	s.Equal(configId, readChanges.ConfigID)
+	s.True(readChanges.HasChanges())
+	s.Require().Len(readChanges.MonitorsToDelete, 1)
+	s.True(proto.Equal(deletedMonitor, readChanges.MonitorsToDelete[0]))
+	s.Require().Len(readChanges.MonitorsChangesOverview, 1)
+	s.True(proto.Equal(changes.MonitorsChangesOverview[0], readChanges.MonitorsChangesOverview[0]))
+	s.True(readChanges.MonitorsChangesOverview[0].ShouldReset)
+	s.Equal(map[string][]string{existingMonitor.Id: {"timezone"}}, readChanges.MonitorsResetReasons)
+	s.Require().Len(readChanges.SqlTestsToCreate, 1)
+	s.True(proto.Equal(createdSqlTest, readChanges.SqlTestsToCreate[0]))
+}
+
+func (s *MgmtServiceTestSuite) TestReadPlanFileRejectsUnknownVersion() {
+	path := filepath.Join(s.T().TempDir(), "plan.json")
+	plan := &Plan{Version: "v0"}
+	s.Require().NoError(plan.WriteFile(path))
+
+	_, err := ReadPlanFile(path)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "only \"v1\" is supported")
+}
@@ -0,0 +1,115 @@
+package mgmt
+
+import (
+	"reflect"
+	"slices"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/proto"
+)
+
+// ResetPolicy decides whether updating a monitor resets what it learned, see the reset policies of the YAML.
+type ResetPolicy string
+
+const (
+	ResetPolicy_Auto   ResetPolicy = "auto"
+	ResetPolicy_Never  ResetPolicy = "never"
+	ResetPolicy_Always ResetPolicy = "always"
+)
+
+// Reasons of a reset which are not a changed field.
+const (
+	ResetReason_Policy = "reset_policy: always"
+	ResetReason_Forced = "--reset"
+)
+
+// resetReasons lists the changed fields which invalidate what the monitor learned.
+func resetReasons(originDef *pb.MonitorDefinition, newDef *pb.MonitorDefinition) []string {
+	reasons := []string{}
+	if originDef.GetTimezone() != newDef.GetTimezone() {
+		reasons = append(reasons, "timezone")
+	}
+
+	if originDef.GetCustomNumeric().GetMetricAggregation() != newDef.GetCustomNumeric().GetMetricAggregation() {
+		reasons = append(reasons, "metric_aggregation")
+	}
+
+	if reflect.TypeOf(originDef.Schedule) != reflect.TypeOf(newDef.Schedule) {
+		reasons = append(reasons, "schedule")
+	} else {
+		switch originDef.Schedule.(type) {
+		case *pb.MonitorDefinition_Daily:
+			if originDef.GetDaily().GetMinutesSinceMidnight() != newDef.GetDaily().GetMinutesSinceMidnight() ||
+				originDef.GetDaily().GetDelayNumDays() != newDef.GetDaily().GetDelayNumDays() {
+				reasons = append(reasons, "schedule")
+			}
+		case *pb.MonitorDefinition_Hourly:
+			if originDef.GetHourly().GetMinuteOfHour() != newDef.GetHourly().GetMinuteOfHour() ||
+				originDef.GetHourly().GetDelayNumHours() != newDef.GetHourly().GetDelayNumHours() {
+				reasons = append(reasons, "schedule")
+			}
+		}
+	}
+
+	if originDef.GetTimePartitioning().GetExpression() != newDef.GetTimePartitioning().GetExpression() {
+		reasons = append(reasons, "time_partitioning")
+	}
+
+	if originDef.GetSegmentation().GetExpression() != newDef.GetSegmentation().GetExpression() {
+		reasons = append(reasons, "segmentation")
+	}
+
+	return reasons
+}
+
+// ApplyResetPolicies overrides whether the updates of the given monitors reset them.
+// Monitors without a policy keep resetting on the changes which require it.
+func (s *ChangesOverview) ApplyResetPolicies(policies map[string]ResetPolicy) {
+	for _, change := range s.MonitorsChangesOverview {
+		switch policies[change.MonitorId] {
+		case ResetPolicy_Never:
+			change.ShouldReset = false
+		case ResetPolicy_Always:
+			change.ShouldReset = true
+			s.addResetReason(change.MonitorId, ResetReason_Policy)
+		}
+	}
+}
+
+// ForceReset resets the given monitors, unchanged ones are updated to their current definition to be reset.
+// Their origin is the deployed definition, which the drift check of apply compares with.
+func (s *ChangesOverview) ForceReset(monitorIds []string) {
+	for _, change := range s.MonitorsChangesOverview {
+		if slices.Contains(monitorIds, change.MonitorId) {
+			change.ShouldReset = true
+			s.addResetReason(change.MonitorId, ResetReason_Forced)
+		}
+	}
+
+	unchanged := []*pb.MonitorDefinition{}
+	for _, monitor := range s.MonitorsUnchanged {
+		if !slices.Contains(monitorIds, monitor.Id) {
+			unchanged = append(unchanged, monitor)
+			continue
+		}
+		s.MonitorsChangesOverview = append(s.MonitorsChangesOverview, &pb.ChangeOverview{
+			MonitorId:        monitor.Id,
+			OriginDefinition: lo.CoalesceOrEmpty(s.fetchedMonitors[monitor.Id], proto.Clone(monitor).(*pb.MonitorDefinition)),
+			NewDefinition:    monitor,
+			ChangesDeltaJson: "{}",
+			ShouldReset:      true,
+		})
+		s.addResetReason(monitor.Id, ResetReason_Forced)
+	}
+	s.MonitorsUnchanged = unchanged
+}
+
+func (s *ChangesOverview) addResetReason(monitorId string, reason string) {
+	if s.MonitorsResetReasons == nil {
+		s.MonitorsResetReasons = map[string][]string{}
+	}
+	if !slices.Contains(s.MonitorsResetReasons[monitorId], reason) {
+		s.MonitorsResetReasons[monitorId] = append(s.MonitorsResetReasons[monitorId], reason)
+	}
+}
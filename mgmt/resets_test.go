@@ -0,0 +1,89 @@
+package mgmt
+
+import (
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *MgmtServiceTestSuite) TestResets() {
+	configId := "config-id"
+	newMonitor := func(name string) *pb.MonitorDefinition {
+		return &pb.MonitorDefinition{
+			Id:       uuid.NewString(),
+			Name:     name,
+			ConfigId: configId,
+			Source:   pb.MonitorDefinition_SOURCE_API,
+			MonitoredId: &entitiesv1.Identifier{
+				Id: &entitiesv1.Identifier_SynqPath{
+					SynqPath: &entitiesv1.SynqPathIdentifier{Path: "mysql-host::schema::table"},
+				},
+			},
+			Schedule: &pb.MonitorDefinition_Daily{Daily: &pb.ScheduleDaily{MinutesSinceMidnight: 60}},
+			Monitor:  &pb.MonitorDefinition_Volume{Volume: &pb.MonitorVolume{}},
+		}
+	}
+	changesOf := func(monitors ...*pb.MonitorDefinition) *ChangesOverview {
+		fetched := map[string]*pb.MonitorDefinition{}
+		requested := []*pb.MonitorDefinition{}
+		for _, monitor := range monitors {
+			fetched[monitor.Id] = monitor
+			requested = append(requested, proto.Clone(monitor).(*pb.MonitorDefinition))
+		}
+		changes, err := GenerateConfigChangesOverview(configId, requested, nil, fetched)
+		s.Require().NoError(err)
+		return changes
+	}
+	shouldReset := func(changes *ChangesOverview) map[string]bool {
+		return lo.SliceToMap(changes.MonitorsChangesOverview, func(change *pb.ChangeOverview) (string, bool) {
+			return change.MonitorId, change.ShouldReset
+		})
+	}
+
+	s.Run("reasons", func() {
+		monitor := newMonitor("volume")
+		changes := changesOf(monitor)
+		requested := changes.MonitorsUnchanged[0]
+		requested.Timezone = "UTC"
+		requested.Schedule = &pb.MonitorDefinition_Hourly{Hourly: &pb.ScheduleHourly{}}
+		requested.Filter = proto.String("id > 0")
+
+		changes, err := GenerateConfigChangesOverview(configId, []*pb.MonitorDefinition{requested}, nil, map[string]*pb.MonitorDefinition{monitor.Id: monitor})
+		s.Require().NoError(err)
+		s.Equal(map[string]bool{monitor.Id: true}, shouldReset(changes))
+		s.Equal(map[string][]string{monitor.Id: {"timezone", "schedule"}}, changes.MonitorsResetReasons)
+	})
+
+	s.Run("policies", func() {
+		never, always, auto := newMonitor("never"), newMonitor("always"), newMonitor("auto")
+		changes := changesOf(never, always, auto)
+		for _, monitor := range changes.MonitorsUnchanged {
+			monitor.Timezone = "UTC"
+		}
+		changes, err := GenerateConfigChangesOverview(configId, changes.MonitorsUnchanged, nil, map[string]*pb.MonitorDefinition{
+			never.Id: never, always.Id: always, auto.Id: auto,
+		})
+		s.Require().NoError(err)
+
+		changes.ApplyResetPolicies(map[string]ResetPolicy{never.Id: ResetPolicy_Never, always.Id: ResetPolicy_Always})
+		s.Equal(map[string]bool{never.Id: false, always.Id: true, auto.Id: true}, shouldReset(changes))
+		s.Equal([]string{"timezone"}, changes.MonitorsResetReasons[never.Id])
+		s.Equal([]string{"timezone", ResetReason_Policy}, changes.MonitorsResetReasons[always.Id])
+	})
+
+	s.Run("forced", func() {
+		unchanged, kept := newMonitor("unchanged"), newMonitor("kept")
+		changes := changesOf(unchanged, kept)
+		s.False(changes.HasChanges())
+
+		changes.ForceReset([]string{unchanged.Id})
+		s.True(changes.HasChanges())
+		s.Equal([]string{kept.Id}, monitorIds(changes.MonitorsUnchanged))
+		s.Equal(map[string]bool{unchanged.Id: true}, shouldReset(changes))
+		s.Equal(map[string][]string{unchanged.Id: {ResetReason_Forced}}, changes.MonitorsResetReasons)
+		// The origin is the deployed definition, not a copy of the requested one.
+		s.Same(unchanged, changes.MonitorsChangesOverview[0].OriginDefinition)
+	})
+}
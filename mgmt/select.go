@@ -0,0 +1,58 @@
+package mgmt
+
+import (
+	"maps"
+	"slices"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+)
+
+// Select offers every change of the overview to accept, in the order of the summary, and drops those it refuses.
+// Conflicts are not offered, as they are breaking changes which cannot be deployed. The skipped changes are returned.
+func (s *ChangesOverview) Select(accept func(change *Change) bool) []*Change {
+	skipped := []*Change{}
+	skippedIds := map[ChangeKind][]string{}
+	for _, change := range s.Summary().Changes {
+		if change.Action == ChangeAction_Conflict || accept(change) {
+			continue
+		}
+		skipped = append(skipped, change)
+		skippedIds[change.Kind] = append(skippedIds[change.Kind], change.Id)
+	}
+	if len(skipped) == 0 {
+		return skipped
+	}
+
+	monitorSkipped := func(monitorId string) bool {
+		return slices.Contains(skippedIds[ChangeKind_Monitor], monitorId)
+	}
+	skippedMonitor := func(monitor *pb.MonitorDefinition) bool {
+		return monitorSkipped(monitor.Id)
+	}
+	skippedSqlTest := func(sqlTest *sqltestsv1.SqlTest) bool {
+		return slices.Contains(skippedIds[ChangeKind_Test], sqlTest.Id)
+	}
+
+	s.MonitorsToCreate = slices.DeleteFunc(s.MonitorsToCreate, skippedMonitor)
+	s.MonitorsToDelete = slices.DeleteFunc(s.MonitorsToDelete, skippedMonitor)
+	s.MonitorsChangesOverview = slices.DeleteFunc(s.MonitorsChangesOverview, func(change *pb.ChangeOverview) bool {
+		return monitorSkipped(change.MonitorId)
+	})
+	s.MonitorsManagedByApp = slices.DeleteFunc(s.MonitorsManagedByApp, monitorSkipped)
+	s.MonitorsProtected = slices.DeleteFunc(s.MonitorsProtected, monitorSkipped)
+	maps.DeleteFunc(s.MonitorsMoved, func(previousId string, _ string) bool {
+		return monitorSkipped(previousId)
+	})
+	maps.DeleteFunc(s.MonitorsResetReasons, func(monitorId string, _ []string) bool {
+		return monitorSkipped(monitorId)
+	})
+
+	s.SqlTestsToCreate = slices.DeleteFunc(s.SqlTestsToCreate, skippedSqlTest)
+	s.SqlTestsToDelete = slices.DeleteFunc(s.SqlTestsToDelete, skippedSqlTest)
+	s.SqlTestsChangesOverview = slices.DeleteFunc(s.SqlTestsChangesOverview, func(change *SqlTestChangeOverview) bool {
+		return slices.Contains(skippedIds[ChangeKind_Test], change.SqlTestId)
+	})
+
+	return skipped
+}
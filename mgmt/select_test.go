@@ -0,0 +1,74 @@
+package mgmt
+
+import (
+	"strings"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *MgmtServiceTestSuite) TestSelect() {
+	configId := "config-id"
+	newMonitor := func(name string) *pb.MonitorDefinition {
+		return &pb.MonitorDefinition{
+			Id:       uuid.NewString(),
+			Name:     name,
+			ConfigId: configId,
+			Source:   pb.MonitorDefinition_SOURCE_API,
+			MonitoredId: &entitiesv1.Identifier{
+				Id: &entitiesv1.Identifier_SynqPath{
+					SynqPath: &entitiesv1.SynqPathIdentifier{Path: "mysql-host::schema::table"},
+				},
+			},
+			Monitor: &pb.MonitorDefinition_Volume{Volume: &pb.MonitorVolume{}},
+		}
+	}
+
+	toUpdate, toUpdateSkipped := newMonitor("to_update"), newMonitor("to_update_skipped")
+	updated, updatedSkipped := proto.Clone(toUpdate).(*pb.MonitorDefinition), proto.Clone(toUpdateSkipped).(*pb.MonitorDefinition)
+	updated.Timezone, updatedSkipped.Timezone = "UTC", "UTC"
+	toDelete, toDeleteSkipped := newMonitor("to_delete"), newMonitor("to_delete_skipped")
+	toCreate, toCreateSkipped := newMonitor("to_create"), newMonitor("to_create_skipped")
+	otherConfig := newMonitor("other_config")
+	claimed := proto.Clone(otherConfig).(*pb.MonitorDefinition)
+	otherConfig.ConfigId = "other"
+
+	changes, err := GenerateConfigChangesOverview(
+		configId,
+		[]*pb.MonitorDefinition{updated, updatedSkipped, toCreate, toCreateSkipped, claimed},
+		nil,
+		map[string]*pb.MonitorDefinition{
+			toUpdate.Id: toUpdate, toUpdateSkipped.Id: toUpdateSkipped, toDelete.Id: toDelete, toDeleteSkipped.Id: toDeleteSkipped, otherConfig.Id: otherConfig,
+		},
+	)
+	s.Require().NoError(err)
+	changes.PreventDestroy([]string{toDeleteSkipped.Id})
+	sqlTest := &sqltestsv1.SqlTest{Id: uuid.NewString(), Name: "not_null", ConfigId: configId, Template: &sqltestsv1.SqlTest_NotNull{}}
+	s.Require().NoError(changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{sqlTest}, map[string]*sqltestsv1.SqlTest{}))
+
+	offered := []string{}
+	skipped := changes.Select(func(change *Change) bool {
+		offered = append(offered, change.Id)
+		return change.Kind == ChangeKind_Monitor && !strings.HasSuffix(change.Name, "_skipped")
+	})
+
+	s.ElementsMatch([]string{
+		toUpdate.Id, toUpdateSkipped.Id, toDelete.Id, toDeleteSkipped.Id, toCreate.Id, toCreateSkipped.Id, sqlTest.Id,
+	}, offered)
+	s.ElementsMatch([]string{toUpdateSkipped.Id, toDeleteSkipped.Id, toCreateSkipped.Id, sqlTest.Id}, lo.Map(skipped, func(change *Change, _ int) string { return change.Id }))
+
+	s.Equal([]string{toCreate.Id}, monitorIds(changes.MonitorsToCreate))
+	s.Equal([]string{toDelete.Id}, monitorIds(changes.MonitorsToDelete))
+	s.Require().Len(changes.MonitorsChangesOverview, 1)
+	s.Equal(toUpdate.Id, changes.MonitorsChangesOverview[0].MonitorId)
+	s.Equal(map[string][]string{toUpdate.Id: {"timezone"}}, changes.MonitorsResetReasons)
+	s.Empty(changes.MonitorsProtected)
+	s.Empty(changes.SqlTestsToCreate)
+	s.Equal(map[string]string{otherConfig.Id: "other"}, changes.MonitorsManagedByOtherConfig)
+
+	s.Empty(changes.Select(func(change *Change) bool { return true }))
+}
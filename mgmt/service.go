@@ -3,24 +3,38 @@ package mgmt
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"slices"
 	"strings"
 
+	sqltestsv1grpc "buf.build/gen/go/getsynq/api/grpc/go/synq/datachecks/sqltests/v1/sqltestsv1grpc"
 	custommonitorsv1grpc "buf.build/gen/go/getsynq/api/grpc/go/synq/monitors/custom_monitors/v1/custom_monitorsv1grpc"
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	custommonitorsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/samber/lo"
 	"google.golang.org/grpc"
 )
 
 type MgmtService interface {
-	ConfigChangesOverview(protoMonitors []*custommonitorsv1.MonitorDefinition, configId string) (*ChangesOverview, error)
+	ConfigChangesOverview(
+		protoMonitors []*custommonitorsv1.MonitorDefinition,
+		moves MonitorMoves,
+		sqlTests []*sqltestsv1.SqlTest,
+		configId string,
+	) (*ChangesOverview, error)
 	DeployMonitors(changesOverview *ChangesOverview) error
+	DetectDrift(changesOverview *ChangesOverview) ([]string, error)
 	ListMonitors(scope *ListScope) ([]*custommonitorsv1.MonitorDefinition, error)
+	// WithOutput returns the service printing its progress to out instead of the standard output.
+	WithOutput(out io.Writer) MgmtService
 }
 
 type remoteMgmtService struct {
-	service custommonitorsv1grpc.CustomMonitorsServiceClient
-	ctx     context.Context
+	service         custommonitorsv1grpc.CustomMonitorsServiceClient
+	sqlTestsService sqltestsv1grpc.SqlTestsServiceClient
+	ctx             context.Context
+	out             io.Writer
 }
 
 var _ MgmtService = &remoteMgmtService{}
@@ -30,23 +44,62 @@ func NewMgmtRemoteService(
 	conn *grpc.ClientConn,
 ) MgmtService {
 	return &remoteMgmtService{
-		service: custommonitorsv1grpc.NewCustomMonitorsServiceClient(conn),
-		ctx:     ctx,
+		service:         custommonitorsv1grpc.NewCustomMonitorsServiceClient(conn),
+		sqlTestsService: sqltestsv1grpc.NewSqlTestsServiceClient(conn),
+		ctx:             ctx,
 	}
 }
 
+func (s *remoteMgmtService) WithOutput(out io.Writer) MgmtService {
+	service := *s
+	service.out = out
+	return &service
+}
+
+// logs is the output of the progress, the standard output unless WithOutput was used.
+func (s *remoteMgmtService) logs() io.Writer {
+	if s.out == nil {
+		return os.Stdout
+	}
+	return s.out
+}
+
 func (s *remoteMgmtService) ConfigChangesOverview(
 	protoMonitors []*custommonitorsv1.MonitorDefinition,
+	moves MonitorMoves,
+	sqlTests []*sqltestsv1.SqlTest,
 	configId string,
 ) (*ChangesOverview, error) {
-	requestedMonitors := map[string]*custommonitorsv1.MonitorDefinition{}
-	for _, pm := range protoMonitors {
-		requestedMonitors[pm.Id] = pm
+	allFetchedMonitors, err := s.fetchMonitors(lo.Uniq(append(monitorIds(protoMonitors), moves.previousIds()...)), configId)
+	if err != nil {
+		return nil, err
 	}
+
+	changesOverview, err := GenerateConfigChangesOverview(configId, protoMonitors, moves, allFetchedMonitors)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedSqlTests, err := s.fetchSqlTests(sqlTestIds(sqlTests), configId)
+	if err != nil {
+		return nil, err
+	}
+	err = changesOverview.AddSqlTestsChanges(sqlTests, fetchedSqlTests)
+	if err != nil {
+		return nil, err
+	}
+
+	return changesOverview, nil
+}
+
+// fetchMonitors returns all API monitors of the config, and the given monitors wherever they are managed.
+func (s *remoteMgmtService) fetchMonitors(
+	monitorIds []string,
+	configId string,
+) (map[string]*custommonitorsv1.MonitorDefinition, error) {
 	allFetchedMonitors := map[string]*custommonitorsv1.MonitorDefinition{}
 
 	// Get all monitors in config
-	monitorIdsInConfig := []string{}
 	configMonitorsResp, err := s.service.ListMonitors(s.ctx, &custommonitorsv1.ListMonitorsRequest{
 		ConfigIds: []string{configId},
 		Sources:   []custommonitorsv1.MonitorDefinition_Source{custommonitorsv1.MonitorDefinition_SOURCE_API},
@@ -56,14 +109,13 @@ func (s *remoteMgmtService) ConfigChangesOverview(
 	}
 	for _, m := range configMonitorsResp.Monitors {
 		allFetchedMonitors[m.Id] = m
-		monitorIdsInConfig = append(monitorIdsInConfig, m.Id)
 	}
 
 	// Get requested monitors not in config
 	monitorIdsNotInConfig := []string{}
-	for _, pm := range protoMonitors {
-		if !slices.Contains(monitorIdsInConfig, pm.Id) {
-			monitorIdsNotInConfig = append(monitorIdsNotInConfig, pm.Id)
+	for _, id := range monitorIds {
+		if _, ok := allFetchedMonitors[id]; !ok {
+			monitorIdsNotInConfig = append(monitorIdsNotInConfig, id)
 		}
 	}
 	if len(monitorIdsNotInConfig) > 0 {
@@ -78,14 +130,53 @@ func (s *remoteMgmtService) ConfigChangesOverview(
 		}
 	}
 
-	return GenerateConfigChangesOverview(configId, protoMonitors, allFetchedMonitors)
+	return allFetchedMonitors, nil
+}
+
+func (s *remoteMgmtService) fetchSqlTests(
+	sqlTestIds []string,
+	configId string,
+) (map[string]*sqltestsv1.SqlTest, error) {
+	allFetchedSqlTests := map[string]*sqltestsv1.SqlTest{}
+
+	// Get all tests in config
+	configSqlTestsResp, err := s.sqlTestsService.ListSqlTests(s.ctx, &sqltestsv1.ListSqlTestsRequest{
+		ConfigIds: []string{configId},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range configSqlTestsResp.SqlTests {
+		allFetchedSqlTests[t.Id] = t
+	}
+
+	// Get requested tests not in config
+	sqlTestIdsNotInConfig := []string{}
+	for _, id := range sqlTestIds {
+		if _, ok := allFetchedSqlTests[id]; !ok {
+			sqlTestIdsNotInConfig = append(sqlTestIdsNotInConfig, id)
+		}
+	}
+	if len(sqlTestIdsNotInConfig) > 0 {
+		sqlTestsResp, err := s.sqlTestsService.ListSqlTests(s.ctx, &sqltestsv1.ListSqlTestsRequest{
+			Ids: sqlTestIdsNotInConfig,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range sqlTestsResp.SqlTests {
+			allFetchedSqlTests[t.Id] = t
+		}
+	}
+
+	return allFetchedSqlTests, nil
 }
 
 func (s *remoteMgmtService) DeployMonitors(
 	changesOverview *ChangesOverview,
 ) error {
 	if len(changesOverview.MonitorsToCreate) > 0 {
-		fmt.Println("Creating monitors...")
+		fmt.Fprintln(s.logs(), "Creating monitors...")
 		_, err := s.service.BatchCreateMonitor(s.ctx, &custommonitorsv1.BatchCreateMonitorRequest{
 			Monitors: changesOverview.MonitorsToCreate,
 		})
@@ -95,11 +186,9 @@ func (s *remoteMgmtService) DeployMonitors(
 	}
 
 	if len(changesOverview.MonitorsToDelete) > 0 {
-		fmt.Println("Deleting monitors...")
+		fmt.Fprintln(s.logs(), "Deleting monitors...")
 		_, err := s.service.BatchDeleteMonitor(s.ctx, &custommonitorsv1.BatchDeleteMonitorRequest{
-			Ids: lo.Map(changesOverview.MonitorsToDelete, func(monitor *custommonitorsv1.MonitorDefinition, _ int) string {
-				return monitor.Id
-			}),
+			Ids: monitorIds(changesOverview.MonitorsToDelete),
 		})
 
 		if err != nil {
@@ -108,7 +197,7 @@ func (s *remoteMgmtService) DeployMonitors(
 	}
 
 	if len(changesOverview.MonitorsChangesOverview) > 0 {
-		fmt.Println("Updating monitors...")
+		fmt.Fprintln(s.logs(), "Updating monitors...")
 		newDefinitions := lo.Map(changesOverview.MonitorsChangesOverview, func(changeOverview *custommonitorsv1.ChangeOverview, _ int) *custommonitorsv1.MonitorDefinition {
 			return changeOverview.NewDefinition
 		})
@@ -125,9 +214,51 @@ func (s *remoteMgmtService) DeployMonitors(
 		}
 	}
 
+	sqlTestsToUpsert := append(
+		slices.Clone(changesOverview.SqlTestsToCreate),
+		lo.Map(changesOverview.SqlTestsChangesOverview, func(changeOverview *SqlTestChangeOverview, _ int) *sqltestsv1.SqlTest {
+			return changeOverview.NewDefinition
+		})...,
+	)
+	if len(sqlTestsToUpsert) > 0 {
+		fmt.Fprintln(s.logs(), "Upserting tests...")
+		_, err := s.sqlTestsService.BatchUpsertSqlTests(s.ctx, &sqltestsv1.BatchUpsertSqlTestsRequest{
+			SqlTests: sqlTestsToUpsert,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(changesOverview.SqlTestsToDelete) > 0 {
+		fmt.Fprintln(s.logs(), "Deleting tests...")
+		_, err := s.sqlTestsService.BatchDeleteSqlTests(s.ctx, &sqltestsv1.BatchDeleteSqlTestsRequest{
+			Ids: sqlTestIds(changesOverview.SqlTestsToDelete),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func (s *remoteMgmtService) DetectDrift(
+	changesOverview *ChangesOverview,
+) ([]string, error) {
+	fetchedMonitors, err := s.fetchMonitors(changesOverview.referencedMonitorIds(), changesOverview.ConfigID)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedSqlTests, err := s.fetchSqlTests(changesOverview.referencedSqlTestIds(), changesOverview.ConfigID)
+	if err != nil {
+		return nil, err
+	}
+
+	return changesOverview.Drift(fetchedMonitors, fetchedSqlTests), nil
+}
+
 type ListScope struct {
 	IntegrationIds []string
 	MonitoredPaths []string
@@ -138,7 +269,7 @@ type ListScope struct {
 func (s *remoteMgmtService) ListMonitors(
 	scope *ListScope,
 ) ([]*custommonitorsv1.MonitorDefinition, error) {
-	fmt.Printf("Listing monitors with scope: %+v\n", scope)
+	fmt.Fprintf(s.logs(), "Listing monitors with scope: %+v\n", scope)
 	req := &custommonitorsv1.ListMonitorsRequest{}
 
 	if len(scope.IntegrationIds) > 0 {
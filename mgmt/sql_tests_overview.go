@@ -0,0 +1,194 @@
+package mgmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	"github.com/fatih/color"
+	"github.com/samber/lo"
+	diff "github.com/yudai/gojsondiff"
+	"github.com/yudai/gojsondiff/formatter"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type SqlTestChangeOverview struct {
+	SqlTestId        string
+	OriginDefinition *sqltestsv1.SqlTest
+	NewDefinition    *sqltestsv1.SqlTest
+	Changes          string
+	ChangesDeltaJson string
+}
+
+// Adds the SQL tests delta to an overview produced by GenerateConfigChangesOverview.
+// Tests follow the same ownership rules as monitors, except that they are never app managed.
+func (s *ChangesOverview) AddSqlTestsChanges(
+	sqlTests []*sqltestsv1.SqlTest,
+	fetchedSqlTests map[string]*sqltestsv1.SqlTest,
+) error {
+	requestedSqlTests := map[string]*sqltestsv1.SqlTest{}
+	for _, sqlTest := range sqlTests {
+		requestedSqlTests[sqlTest.Id] = sqlTest
+	}
+
+	s.SqlTestsToCreate = []*sqltestsv1.SqlTest{}
+	s.SqlTestsToDelete = []*sqltestsv1.SqlTest{}
+	s.SqlTestsUnchanged = []*sqltestsv1.SqlTest{}
+	s.SqlTestsManagedByOtherConfig = map[string]string{}
+	s.SqlTestsChangesOverview = []*SqlTestChangeOverview{}
+
+	for id, fetched := range fetchedSqlTests {
+		if fetched.ConfigId != s.ConfigID {
+			continue
+		}
+		if _, ok := requestedSqlTests[id]; !ok {
+			s.SqlTestsToDelete = append(s.SqlTestsToDelete, fetched)
+		}
+	}
+
+	differ := diff.New()
+	deltaFormatter := formatter.NewDeltaFormatter()
+	for id, sqlTest := range requestedSqlTests {
+		fetched := fetchedSqlTests[id]
+		if fetched == nil {
+			s.SqlTestsToCreate = append(s.SqlTestsToCreate, sqlTest)
+			continue
+		}
+
+		if fetched.ConfigId != sqlTest.ConfigId {
+			s.SqlTestsManagedByOtherConfig[id] = fetched.ConfigId
+			continue
+		}
+
+		change, err := generateSqlTestChangeOverview(differ, deltaFormatter, fetched, sqlTest)
+		if err != nil {
+			return err
+		}
+		if change.Changes == "" {
+			s.SqlTestsUnchanged = append(s.SqlTestsUnchanged, sqlTest)
+		} else {
+			s.SqlTestsChangesOverview = append(s.SqlTestsChangesOverview, change)
+		}
+	}
+
+	return nil
+}
+
+func generateSqlTestChangeOverview(
+	differ *diff.Differ,
+	deltaFormatter *formatter.DeltaFormatter,
+	origin *sqltestsv1.SqlTest,
+	newDefinition *sqltestsv1.SqlTest,
+) (*SqlTestChangeOverview, error) {
+	originJson, err := protojson.Marshal(origin)
+	if err != nil {
+		return nil, err
+	}
+	var originMap map[string]interface{}
+	err = json.Unmarshal(originJson, &originMap)
+	if err != nil {
+		return nil, err
+	}
+
+	newJson, err := protojson.Marshal(newDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := differ.Compare(originJson, newJson)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := ""
+	changesDelta := "{}"
+	if diff.Modified() {
+		asciiFormatter := formatter.NewAsciiFormatter(originMap, formatter.AsciiFormatterConfig{})
+		changesDelta, err = deltaFormatter.Format(diff)
+		if err != nil {
+			return nil, err
+		}
+		changes, err = asciiFormatter.Format(diff)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &SqlTestChangeOverview{
+		SqlTestId:        origin.Id,
+		OriginDefinition: origin,
+		NewDefinition:    newDefinition,
+		Changes:          changes,
+		ChangesDeltaJson: changesDelta,
+	}, nil
+}
+
+func (s *ChangesOverview) prettyPrintSqlTests(w io.Writer, green, red, yellow, blue, gray *color.Color) {
+	printSqlTests := func(c *color.Color, title string, sqlTests []*sqltestsv1.SqlTest) {
+		if len(sqlTests) == 0 {
+			return
+		}
+		fmt.Fprintln(w)
+		c.Fprintln(w, title)
+		for i, sqlTest := range sqlTests {
+			fmt.Fprintf(w, "  %d. ", i+1)
+			c.Fprintf(w, "%s", sqlTest.Name)
+			fmt.Fprintf(w, " (%s)\n", getSqlTestType(sqlTest))
+			if sqlTest.MonitoredId != nil {
+				gray.Fprintf(w, "     → Tested: %s\n", s.formatMonitoredId(sqlTest.MonitoredId))
+			}
+		}
+	}
+
+	printSqlTests(green, "🆕 Tests to Create:", s.SqlTestsToCreate)
+	printSqlTests(red, "🗑️  Tests to Delete:", s.SqlTestsToDelete)
+
+	if len(s.SqlTestsChangesOverview) > 0 {
+		fmt.Fprintln(w)
+		yellow.Fprintln(w, "📝 Tests to Update:")
+		for i, change := range s.SqlTestsChangesOverview {
+			fmt.Fprintf(w, "  %d. ", i+1)
+			yellow.Fprintf(w, "%s", change.NewDefinition.Name)
+			fmt.Fprintf(w, " (%s)\n", getSqlTestType(change.NewDefinition))
+			printChanges(w, change.Changes, green, red, gray)
+		}
+	}
+
+	printSqlTests(blue, "✅ Tests Unchanged:", s.SqlTestsUnchanged)
+}
+
+func getSqlTestType(sqlTest *sqltestsv1.SqlTest) string {
+	switch sqlTest.GetTemplate().(type) {
+	case *sqltestsv1.SqlTest_NotNull:
+		return "not_null"
+	case *sqltestsv1.SqlTest_Empty:
+		return "empty"
+	case *sqltestsv1.SqlTest_Unique:
+		return "unique"
+	case *sqltestsv1.SqlTest_AcceptedValues:
+		return "accepted_values"
+	case *sqltestsv1.SqlTest_RejectedValues:
+		return "rejected_values"
+	case *sqltestsv1.SqlTest_MinMax:
+		return "min_max"
+	case *sqltestsv1.SqlTest_MinValue:
+		return "min_value"
+	case *sqltestsv1.SqlTest_MaxValue:
+		return "max_value"
+	case *sqltestsv1.SqlTest_Freshness:
+		return "freshness"
+	case *sqltestsv1.SqlTest_RelativeTime:
+		return "relative_time"
+	case *sqltestsv1.SqlTest_BusinessRule:
+		return "business_rule"
+	default:
+		return "unknown"
+	}
+}
+
+func sqlTestIds(sqlTests []*sqltestsv1.SqlTest) []string {
+	return lo.Map(sqlTests, func(sqlTest *sqltestsv1.SqlTest, _ int) string {
+		return sqlTest.Id
+	})
+}
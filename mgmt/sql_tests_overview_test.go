@@ -0,0 +1,99 @@
+package mgmt
+
+import (
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *MgmtServiceTestSuite) TestSqlTestsChangesOverview() {
+	configId := "config-id"
+
+	existingSqlTest := &sqltestsv1.SqlTest{
+		Id:       uuid.NewString(),
+		Name:     "not_null_id",
+		ConfigId: configId,
+		MonitoredId: &entitiesv1.Identifier{
+			Id: &entitiesv1.Identifier_SynqPath{
+				SynqPath: &entitiesv1.SynqPathIdentifier{
+					Path: "mysql-host::schema::table",
+				},
+			},
+		},
+		RecurrenceRule: "FREQ=DAILY",
+		Template: &sqltestsv1.SqlTest_NotNull{
+			NotNull: &sqltestsv1.NotNullTest{ColumnNames: []string{"id"}},
+		},
+	}
+
+	newOverview := func() *ChangesOverview {
+		changes, err := GenerateConfigChangesOverview(configId, []*pb.MonitorDefinition{}, nil, map[string]*pb.MonitorDefinition{})
+		s.Require().NoError(err)
+		return changes
+	}
+
+	s.Run("new_test", func() {
+		changes := newOverview()
+		err := changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{existingSqlTest}, map[string]*sqltestsv1.SqlTest{})
+		s.Require().NoError(err)
+		s.Require().True(changes.HasChanges())
+		s.Len(changes.SqlTestsToCreate, 1)
+		s.Len(changes.SqlTestsToDelete, 0)
+		s.Len(changes.SqlTestsChangesOverview, 0)
+		s.Len(changes.SqlTestsUnchanged, 0)
+	})
+
+	s.Run("no_changes", func() {
+		changes := newOverview()
+		err := changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{existingSqlTest}, map[string]*sqltestsv1.SqlTest{
+			existingSqlTest.Id: existingSqlTest,
+		})
+		s.Require().NoError(err)
+		s.Require().False(changes.HasChanges())
+		s.Len(changes.SqlTestsUnchanged, 1)
+	})
+
+	s.Run("deleted_test", func() {
+		changes := newOverview()
+		err := changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{}, map[string]*sqltestsv1.SqlTest{
+			existingSqlTest.Id: existingSqlTest,
+		})
+		s.Require().NoError(err)
+		s.Require().True(changes.HasChanges())
+		s.Len(changes.SqlTestsToDelete, 1)
+		s.Equal(existingSqlTest.Id, changes.SqlTestsToDelete[0].Id)
+	})
+
+	s.Run("changed_test", func() {
+		changedSqlTest := proto.Clone(existingSqlTest).(*sqltestsv1.SqlTest)
+		changedSqlTest.Template = &sqltestsv1.SqlTest_NotNull{
+			NotNull: &sqltestsv1.NotNullTest{ColumnNames: []string{"id", "name"}},
+		}
+
+		changes := newOverview()
+		err := changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{changedSqlTest}, map[string]*sqltestsv1.SqlTest{
+			existingSqlTest.Id: existingSqlTest,
+		})
+		s.Require().NoError(err)
+		s.Require().True(changes.HasChanges())
+		s.Require().Len(changes.SqlTestsChangesOverview, 1)
+		s.NotEmpty(changes.SqlTestsChangesOverview[0].Changes)
+		s.Equal(changedSqlTest, changes.SqlTestsChangesOverview[0].NewDefinition)
+	})
+
+	s.Run("test_managed_by_other_config", func() {
+		otherSqlTest := proto.Clone(existingSqlTest).(*sqltestsv1.SqlTest)
+		otherSqlTest.ConfigId = "other-config"
+
+		changes := newOverview()
+		err := changes.AddSqlTestsChanges([]*sqltestsv1.SqlTest{existingSqlTest}, map[string]*sqltestsv1.SqlTest{
+			existingSqlTest.Id: otherSqlTest,
+		})
+		s.Require().NoError(err)
+		s.Len(changes.SqlTestsManagedByOtherConfig, 1)
+		s.Equal("other-config", changes.SqlTestsManagedByOtherConfig[existingSqlTest.Id])
+		s.NotEmpty(changes.GetBreakingChanges())
+	})
+}
@@ -0,0 +1,212 @@
+package paths
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// CacheDir returns the directory in which the paths cache of every workspace is stored.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "synq-monitors", "paths"), nil
+}
+
+// ClearCache deletes the paths cache of every workspace.
+func ClearCache(dir string) error {
+	return os.RemoveAll(dir)
+}
+
+type cacheEntry struct {
+	Path     string    `json:"path"`
+	Strategy Strategy  `json:"strategy,omitempty"`
+	CachedAt time.Time `json:"cached_at"`
+}
+
+type cacheContent struct {
+	Workspace string `json:"workspace"`
+	// Resolved are the SYNQ paths of the simple paths, as SimpleToPath resolves them.
+	Resolved map[string]cacheEntry `json:"resolved"`
+	// Simplified are the simple paths of the SYNQ paths, as PathToSimple simplifies them.
+	Simplified map[string]cacheEntry `json:"simplified"`
+}
+
+// Cache stores the path conversions of a workspace in a JSON file.
+//
+// Only successful conversions are stored: unresolved and ambiguous paths may be fixed at any time
+// by creating or removing entities, so they are always converted again.
+type Cache struct {
+	mu      sync.Mutex
+	file    string
+	ttl     time.Duration
+	refresh bool
+	now     func() time.Time
+	content cacheContent
+}
+
+// OpenCache loads the cache of the workspace from the directory.
+// A missing or unreadable cache file starts an empty cache, which replaces it when saved.
+// When refresh is set, the cached conversions are ignored and replaced by new ones.
+func OpenCache(dir string, workspace string, ttl time.Duration, refresh bool) *Cache {
+	cache := &Cache{
+		file:    filepath.Join(dir, url.PathEscape(workspace)+".json"),
+		ttl:     ttl,
+		refresh: refresh,
+		now:     time.Now,
+	}
+
+	content, err := os.ReadFile(cache.file)
+	if err != nil || json.Unmarshal(content, &cache.content) != nil || cache.content.Workspace != workspace {
+		cache.content = cacheContent{}
+	}
+	cache.content.Workspace = workspace
+	if cache.content.Resolved == nil {
+		cache.content.Resolved = map[string]cacheEntry{}
+	}
+	if cache.content.Simplified == nil {
+		cache.content.Simplified = map[string]cacheEntry{}
+	}
+
+	return cache
+}
+
+// File returns the path of the cache file.
+func (c *Cache) File() string {
+	return c.file
+}
+
+func (c *Cache) lookup(entries map[string]cacheEntry, path string) (cacheEntry, bool) {
+	entry, ok := entries[path]
+	if !ok || c.refresh || c.now().Sub(entry.CachedAt) > c.ttl {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store saves the cache after adding the entries, which are timestamped with the current time.
+func (c *Cache) store(entries map[string]cacheEntry, added map[string]cacheEntry) error {
+	now := c.now()
+	for path, entry := range added {
+		entry.CachedAt = now
+		entries[path] = entry
+	}
+
+	content, err := json.MarshalIndent(c.content, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0o700); err != nil {
+		return err
+	}
+	// Written aside first, so that an interrupted write never leaves a truncated cache.
+	tmp, err := os.CreateTemp(filepath.Dir(c.file), filepath.Base(c.file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.file)
+}
+
+// WithCache returns a converter answering from the cache when it can, and from the given converter otherwise.
+// The cache is best effort, a conversion does not fail when the cache cannot be written.
+func WithCache(converter PathConverter, cache *Cache) PathConverter {
+	return &cachedPathConverter{PathConverter: converter, cache: cache}
+}
+
+type cachedPathConverter struct {
+	PathConverter
+	cache *Cache
+}
+
+func (c *cachedPathConverter) SimpleToPath(requestedPaths []string) (map[string]string, *SimpleToPathError) {
+	return simpleToPath(c.ResolvePaths, requestedPaths)
+}
+
+// ResolvePaths resolves the paths missing from the cache, and marks the others as Cached in the resolution.
+// The cache is not locked while the paths are resolved, so that concurrent conversions do not wait for each other.
+func (c *cachedPathConverter) ResolvePaths(requestedPaths []string) (*Resolution, error) {
+	c.cache.mu.Lock()
+	resolution := NewResolution()
+	missing := []string{}
+	for _, path := range lo.Uniq(requestedPaths) {
+		entry, ok := c.cache.lookup(c.cache.content.Resolved, path)
+		if !ok {
+			missing = append(missing, path)
+			continue
+		}
+		resolution.Resolved[path] = entry.Path
+		resolution.Strategies[path] = entry.Strategy
+		resolution.Cached = append(resolution.Cached, path)
+	}
+	c.cache.mu.Unlock()
+	if len(missing) == 0 {
+		return resolution, nil
+	}
+
+	resolved, err := c.PathConverter.ResolvePaths(missing)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.mu.Lock()
+	_ = c.cache.store(c.cache.content.Resolved, lo.MapValues(resolved.Resolved, func(synqPath string, path string) cacheEntry {
+		return cacheEntry{Path: synqPath, Strategy: resolved.Strategies[path]}
+	}))
+	c.cache.mu.Unlock()
+
+	for path, synqPath := range resolved.Resolved {
+		resolution.Resolved[path] = synqPath
+		resolution.Strategies[path] = resolved.Strategies[path]
+	}
+	resolution.Unresolved = append(resolution.Unresolved, resolved.Unresolved...)
+	for path, candidates := range resolved.Ambiguous {
+		resolution.Ambiguous[path] = candidates
+	}
+	return resolution, nil
+}
+
+func (c *cachedPathConverter) PathToSimple(paths []string) (map[string]string, error) {
+	c.cache.mu.Lock()
+	simplifiedPaths := map[string]string{}
+	missing := []string{}
+	for _, path := range lo.Uniq(paths) {
+		if entry, ok := c.cache.lookup(c.cache.content.Simplified, path); ok {
+			simplifiedPaths[path] = entry.Path
+		} else {
+			missing = append(missing, path)
+		}
+	}
+	c.cache.mu.Unlock()
+	if len(missing) == 0 {
+		return simplifiedPaths, nil
+	}
+
+	simplified, err := c.PathConverter.PathToSimple(missing)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.mu.Lock()
+	_ = c.cache.store(c.cache.content.Simplified, lo.MapValues(simplified, func(simple string, _ string) cacheEntry {
+		return cacheEntry{Path: simple}
+	}))
+	c.cache.mu.Unlock()
+
+	for path, simple := range simplified {
+		simplifiedPaths[path] = simple
+	}
+	return simplifiedPaths, nil
+}
@@ -0,0 +1,98 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	coordinatesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/coordinates/v1"
+	entitiesentitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/entities/v1"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *PathConverterTestSuite) TestCachedPathConverter() {
+	dir := s.T().TempDir()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	open := func(workspace string, refresh bool) PathConverter {
+		cache := OpenCache(dir, workspace, time.Hour, refresh)
+		cache.now = func() time.Time { return now }
+		return WithCache(s.converter, cache)
+	}
+	expectResolution := func() {
+		s.mockEntities.EXPECT().BatchGetEntities(gomock.Any(), gomock.Any()).Return(&entitiesentitiesv1.BatchGetEntitiesResponse{}, nil)
+		s.mockCoordinates.EXPECT().BatchIdsByCoordinates(gomock.Any(), gomock.Any()).Return(&coordinatesv1.BatchIdsByCoordinatesResponse{
+			MatchedCoordinates: []*coordinatesv1.BatchIdsByCoordinatesResponse_MatchedCoordinates{
+				{SqlFqn: "analytics.events", Candidates: []*coordinatesv1.DatabaseCoordinates{{SynqPaths: []string{"bq::analytics::events"}}}},
+				{SqlFqn: "missing"},
+			},
+		}, nil)
+	}
+	input := []string{"analytics.events", "missing"}
+
+	s.Run("resolutions", func() {
+		expectResolution()
+		resolution, err := open("acme", false).ResolvePaths(input)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"analytics.events": "bq::analytics::events"}, resolution.Resolved)
+		s.Equal([]string{"missing"}, resolution.Unresolved)
+		s.Empty(resolution.Cached)
+
+		// Only the unresolved path is resolved again, by another run.
+		s.mockEntities.EXPECT().BatchGetEntities(gomock.Any(), gomock.Any()).Return(&entitiesentitiesv1.BatchGetEntitiesResponse{}, nil)
+		s.mockCoordinates.EXPECT().BatchIdsByCoordinates(gomock.Any(), &coordinatesv1.BatchIdsByCoordinatesRequest{SqlFqn: []string{"missing"}}).
+			Return(&coordinatesv1.BatchIdsByCoordinatesResponse{}, nil)
+		resolution, err = open("acme", false).ResolvePaths(input)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"analytics.events": "bq::analytics::events"}, resolution.Resolved)
+		s.Equal(map[string]Strategy{"analytics.events": Strategy_Coordinate}, resolution.Strategies)
+		s.Equal([]string{"analytics.events"}, resolution.Cached)
+
+		resolved, resolveErr := open("acme", false).SimpleToPath([]string{"analytics.events"})
+		s.Nil(resolveErr)
+		s.Equal(map[string]string{"analytics.events": "bq::analytics::events"}, resolved)
+	})
+
+	s.Run("expired_refreshed_and_other_workspaces", func() {
+		now = now.Add(2 * time.Hour)
+		expectResolution()
+		_, err := open("acme", false).ResolvePaths(input)
+		s.Require().NoError(err)
+
+		expectResolution()
+		_, err = open("acme", true).ResolvePaths(input)
+		s.Require().NoError(err)
+
+		expectResolution()
+		_, err = open("other", false).ResolvePaths(input)
+		s.Require().NoError(err)
+	})
+
+	s.Run("simplifications", func() {
+		s.mockCoordinates.EXPECT().BatchDatabaseCoordinates(gomock.Any(), gomock.Any()).Return(&coordinatesv1.BatchDatabaseCoordinatesResponse{
+			Coordinates: []*coordinatesv1.DatabaseCoordinates{{SqlFqn: "analytics.events", SynqPaths: []string{"bq::analytics::events"}}},
+		}, nil)
+		input := []string{"bq::analytics::events", "pg::public::orders"}
+		expected := map[string]string{"bq::analytics::events": "analytics.events", "pg::public::orders": "pg.public.orders"}
+
+		simplified, err := open("acme", false).PathToSimple(input)
+		s.Require().NoError(err)
+		s.Equal(expected, simplified)
+
+		simplified, err = open("acme", false).PathToSimple(input)
+		s.Require().NoError(err)
+		s.Equal(expected, simplified)
+	})
+
+	s.Run("corrupted_and_cleared", func() {
+		cache := OpenCache(dir, "acme", time.Hour, false)
+		s.FileExists(cache.File())
+		s.Require().NoError(ClearCache(dir))
+		s.NoDirExists(dir)
+
+		s.Require().NoError(os.MkdirAll(filepath.Dir(cache.File()), 0o700))
+		s.Require().NoError(os.WriteFile(cache.File(), []byte("{not json"), 0o600))
+		cache = OpenCache(dir, "acme", time.Hour, false)
+		s.Empty(cache.content.Resolved)
+		s.Equal("acme", cache.content.Workspace)
+	})
+}
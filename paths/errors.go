@@ -11,6 +11,8 @@ type SimpleToPathError struct {
 	Err                                   error
 	UnresolvedPaths                       []string
 	MonitoredEntitiesWithMultipleEntities map[string][]string
+	// Suggestions are the SYNQ paths close to each unresolved path.
+	Suggestions map[string][]string
 }
 
 func (e *SimpleToPathError) HasErrors() bool {
@@ -27,6 +29,9 @@ func (e *SimpleToPathError) Error() string {
 		messages = append(messages, "❌ The following monitored IDs could not be resolved:")
 		for _, path := range e.UnresolvedPaths {
 			messages = append(messages, fmt.Sprintf("  - %s", path))
+			if suggestions := e.Suggestions[path]; len(suggestions) > 0 {
+				messages = append(messages, fmt.Sprintf("      did you mean %s?", strings.Join(suggestions, " or ")))
+			}
 		}
 	}
 	if len(e.MonitoredEntitiesWithMultipleEntities) > 0 {
@@ -0,0 +1,154 @@
+package paths
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"buf.build/gen/go/getsynq/api/grpc/go/synq/entities/coordinates/v1/coordinatesv1grpc"
+	"buf.build/gen/go/getsynq/api/grpc/go/synq/entities/entities/v1/entitiesv1grpc"
+	coordinatesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/coordinates/v1"
+	entitiesentitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/entities/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+)
+
+// Explanation is the resolution chain of a path: the entities it matched, and why they were or were not monitorable.
+// Entity is the entity of the path read as a SYNQ path, nil when there is none. Candidates are the entities
+// of the DB coordinate the path was read as, when it was not resolved as a SYNQ path.
+type Explanation struct {
+	Path        string      `json:"path"                  yaml:"path"`
+	Resolved    string      `json:"resolved,omitempty"    yaml:"resolved,omitempty"`
+	Strategy    Strategy    `json:"strategy,omitempty"    yaml:"strategy,omitempty"`
+	Entity      *Candidate  `json:"entity,omitempty"      yaml:"entity,omitempty"`
+	Candidates  []Candidate `json:"candidates,omitempty"  yaml:"candidates,omitempty"`
+	Ambiguous   []string    `json:"ambiguous,omitempty"   yaml:"ambiguous,omitempty"`
+	Suggestions []string    `json:"suggestions,omitempty" yaml:"suggestions,omitempty"`
+}
+
+// Candidate is an entity a path may resolve to. EntityType is empty when the entity does not exist,
+// and Rejected tells why the entity cannot be monitored.
+type Candidate struct {
+	SynqPath   string `json:"synq_path"             yaml:"synq_path"`
+	EntityType string `json:"entity_type,omitempty" yaml:"entity_type,omitempty"`
+	Rejected   string `json:"rejected,omitempty"    yaml:"rejected,omitempty"`
+}
+
+type PathExplainer interface {
+	ExplainPaths(simple []string) ([]*Explanation, error)
+}
+
+func NewPathExplainer(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+) PathExplainer {
+	return &pathConverter{
+		ctx:                ctx,
+		entitiesService:    entitiesv1grpc.NewEntitiesServiceClient(conn),
+		coordinatesService: coordinatesv1grpc.NewDatabaseCoordinatesServiceClient(conn),
+	}
+}
+
+// Explains how each path resolves, exactly like ResolvePaths, and suggests close SYNQ paths for the unresolved ones.
+// The entities and coordinates are fetched again to describe every candidate, including the rejected ones.
+func (s *pathConverter) ExplainPaths(requestedPaths []string) ([]*Explanation, error) {
+	requestedPaths = lo.Uniq(requestedPaths)
+	resolution, err := s.ResolvePaths(requestedPaths)
+	if err != nil {
+		return nil, err
+	}
+	suggestions, err := s.SuggestPaths(resolution.Unresolved)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every path is first read as a SYNQ path, and as a DB coordinate when that did not resolve it.
+	byCoordinate := lo.Filter(requestedPaths, func(path string, _ int) bool {
+		strategy := resolution.Strategies[path]
+		return strategy != Strategy_SynqPath && strategy != Strategy_DotNotation
+	})
+	candidatesByPath := map[string][]string{}
+	for _, chunk := range lo.Chunk(byCoordinate, BatchSize) {
+		resp, err := s.coordinatesService.BatchIdsByCoordinates(s.ctx, &coordinatesv1.BatchIdsByCoordinatesRequest{SqlFqn: chunk})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching coordinates for monitored paths: %w", err)
+		}
+		for _, coord := range resp.MatchedCoordinates {
+			candidatesByPath[coord.SqlFqn] = lo.Uniq(lo.FlatMap(coord.Candidates, func(cand *coordinatesv1.DatabaseCoordinates, _ int) []string {
+				return cand.SynqPaths
+			}))
+		}
+	}
+
+	entityPaths := lo.Map(requestedPaths, func(path string, _ int) string { return PathWithColons(path) })
+	entityPaths = lo.Uniq(append(entityPaths, lo.Flatten(lo.Values(candidatesByPath))...))
+	types, err := s.entityTypes(entityPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	explanations := []*Explanation{}
+	for _, path := range requestedPaths {
+		explanation := &Explanation{
+			Path:        path,
+			Resolved:    resolution.Resolved[path],
+			Strategy:    resolution.Strategies[path],
+			Ambiguous:   resolution.Ambiguous[path],
+			Suggestions: suggestions[path],
+		}
+		if entityType, ok := types[PathWithColons(path)]; ok {
+			explanation.Entity = lo.ToPtr(candidate(PathWithColons(path), entityType, true))
+		}
+		for _, synqPath := range candidatesByPath[path] {
+			entityType, ok := types[synqPath]
+			explanation.Candidates = append(explanation.Candidates, candidate(synqPath, entityType, ok))
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations, nil
+}
+
+// entityTypes returns the type of the entities of the given SYNQ paths, those without entity are missing.
+func (s *pathConverter) entityTypes(synqPaths []string) (map[string]*entitiesv1.EntityType, error) {
+	types := map[string]*entitiesv1.EntityType{}
+	for _, chunk := range lo.Chunk(synqPaths, BatchSize) {
+		resp, err := s.entitiesService.BatchGetEntities(s.ctx, &entitiesentitiesv1.BatchGetEntitiesRequest{
+			Ids: lo.Map(chunk, func(path string, _ int) *entitiesv1.Identifier {
+				return &entitiesv1.Identifier{
+					Id: &entitiesv1.Identifier_SynqPath{
+						SynqPath: &entitiesv1.SynqPathIdentifier{
+							Path: path,
+						},
+					},
+				}
+			}),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching entities for monitored paths: %w", err)
+		}
+		for _, entity := range resp.Entities {
+			types[lo.CoalesceOrEmpty(entity.Id.GetSynqPath().GetPath(), entity.SynqPath)] = entity.EntityType
+		}
+	}
+	return types, nil
+}
+
+func candidate(synqPath string, entityType *entitiesv1.EntityType, exists bool) Candidate {
+	switch {
+	case !exists:
+		return Candidate{SynqPath: synqPath, Rejected: "no such entity"}
+	case entityType == nil:
+		return Candidate{SynqPath: synqPath, Rejected: "unknown entity type"}
+	case !slices.Contains(ValidMonitoredTypes, *entityType):
+		return Candidate{SynqPath: synqPath, EntityType: EntityTypeName(*entityType), Rejected: "entity type cannot be monitored"}
+	default:
+		return Candidate{SynqPath: synqPath, EntityType: EntityTypeName(*entityType)}
+	}
+}
+
+// EntityTypeName returns the short name of an entity type, like postgres_table.
+func EntityTypeName(entityType entitiesv1.EntityType) string {
+	return strings.ToLower(strings.TrimPrefix(entityType.String(), "ENTITY_TYPE_"))
+}
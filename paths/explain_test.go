@@ -0,0 +1,138 @@
+package paths
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	coordinatesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/coordinates/v1"
+	entitiesentitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/entities/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+)
+
+// expectWorkspace serves the entities and DB coordinates of a workspace to any number of requests.
+func (s *PathConverterTestSuite) expectWorkspace(entities map[string]entitiesv1.EntityType, coordinates map[string][]string) {
+	entity := func(path string) *entitiesv1.Entity {
+		return &entitiesv1.Entity{
+			Id:         &entitiesv1.Identifier{Id: &entitiesv1.Identifier_SynqPath{SynqPath: &entitiesv1.SynqPathIdentifier{Path: path}}},
+			SynqPath:   path,
+			EntityType: entities[path].Enum(),
+		}
+	}
+	s.mockEntities.EXPECT().BatchGetEntities(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, req *entitiesentitiesv1.BatchGetEntitiesRequest, _ ...grpc.CallOption) (*entitiesentitiesv1.BatchGetEntitiesResponse, error) {
+			resp := &entitiesentitiesv1.BatchGetEntitiesResponse{}
+			for _, id := range req.Ids {
+				if _, ok := entities[id.GetSynqPath().GetPath()]; ok {
+					resp.Entities = append(resp.Entities, entity(id.GetSynqPath().GetPath()))
+				}
+			}
+			return resp, nil
+		},
+	)
+	s.mockCoordinates.EXPECT().BatchIdsByCoordinates(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, req *coordinatesv1.BatchIdsByCoordinatesRequest, _ ...grpc.CallOption) (*coordinatesv1.BatchIdsByCoordinatesResponse, error) {
+			resp := &coordinatesv1.BatchIdsByCoordinatesResponse{}
+			for _, fqn := range req.SqlFqn {
+				matched := &coordinatesv1.BatchIdsByCoordinatesResponse_MatchedCoordinates{SqlFqn: fqn}
+				if synqPaths, ok := coordinates[fqn]; ok {
+					matched.Candidates = []*coordinatesv1.DatabaseCoordinates{{SqlFqn: fqn, SynqPaths: synqPaths}}
+				}
+				resp.MatchedCoordinates = append(resp.MatchedCoordinates, matched)
+			}
+			return resp, nil
+		},
+	)
+	s.mockEntities.EXPECT().SearchEntities(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, req *entitiesentitiesv1.SearchEntitiesRequest, _ ...grpc.CallOption) (*entitiesentitiesv1.SearchEntitiesResponse, error) {
+			resp := &entitiesentitiesv1.SearchEntitiesResponse{}
+			for path, entityType := range entities {
+				if slices.Contains(req.EntityTypes, entityType) && len(req.Query) >= 3 && pathName(path)[:3] == req.Query[:3] {
+					resp.Entities = append(resp.Entities, entity(path))
+				}
+			}
+			return resp, nil
+		},
+	)
+}
+
+func (s *PathConverterTestSuite) TestExplainPaths() {
+	s.expectWorkspace(
+		map[string]entitiesv1.EntityType{
+			"pg::public::orders":    entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE,
+			"dbt::public::orders":   entitiesv1.EntityType_ENTITY_TYPE_DBT_MODEL,
+			"bq::analytics::events": entitiesv1.EntityType_ENTITY_TYPE_BQ_TABLE,
+			"pg::analytics::events": entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE,
+		},
+		map[string][]string{
+			"public.orders":    {"pg::public::orders", "dbt::public::orders"},
+			"analytics.events": {"bq::analytics::events", "pg::analytics::events"},
+		},
+	)
+
+	explanations, err := s.converter.ExplainPaths([]string{"pg::public::orders", "public.orders", "analytics.events", "public.ordrs"})
+	s.Require().NoError(err)
+	s.Equal([]*Explanation{
+		{
+			Path:     "pg::public::orders",
+			Resolved: "pg::public::orders",
+			Strategy: Strategy_SynqPath,
+			Entity:   &Candidate{SynqPath: "pg::public::orders", EntityType: "postgres_table"},
+		},
+		{
+			Path:     "public.orders",
+			Resolved: "pg::public::orders",
+			Strategy: Strategy_Coordinate,
+			Candidates: []Candidate{
+				{SynqPath: "pg::public::orders", EntityType: "postgres_table"},
+				{SynqPath: "dbt::public::orders", EntityType: "dbt_model", Rejected: "entity type cannot be monitored"},
+			},
+		},
+		{
+			Path: "analytics.events",
+			Candidates: []Candidate{
+				{SynqPath: "bq::analytics::events", EntityType: "bq_table"},
+				{SynqPath: "pg::analytics::events", EntityType: "postgres_table"},
+			},
+			Ambiguous: []string{"bq::analytics::events", "pg::analytics::events"},
+		},
+		{
+			Path:        "public.ordrs",
+			Suggestions: []string{"pg::public::orders"},
+		},
+	}, explanations)
+}
+
+func (s *PathConverterTestSuite) TestSuggestPaths() {
+	s.expectWorkspace(map[string]entitiesv1.EntityType{
+		"pg::public::orders":   entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE,
+		"pg::public::order":    entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE,
+		"pg::public::ordering": entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE,
+		"pg::sales::orders":    entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE,
+		"pg::public::payments": entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE,
+	}, nil)
+
+	suggestions, err := s.converter.SuggestPaths([]string{"public.ordrs", "pg.pubic.orders", "shipments"})
+	s.Require().NoError(err)
+	s.Equal(map[string][]string{
+		"public.ordrs":    {"pg::public::orders", "pg::public::order"},
+		"pg.pubic.orders": {"pg::public::orders", "pg::public::order"},
+	}, suggestions)
+
+	// Suggestions are only searched for the first unresolved paths.
+	typos := []string{}
+	for i := range SuggestedPathsLimit + 10 {
+		typos = append(typos, fmt.Sprintf("public.ordrs%d", i))
+	}
+	suggestions, err = s.converter.SuggestPaths(typos)
+	s.Require().NoError(err)
+	s.Len(suggestions, SuggestedPathsLimit)
+	s.Contains(suggestions, "public.ordrs0")
+	s.NotContains(suggestions, fmt.Sprintf("public.ordrs%d", SuggestedPathsLimit))
+
+	s.Equal(0, levenshtein("orders", "orders"))
+	s.Equal(1, levenshtein("ordrs", "orders"))
+	s.Equal(3, levenshtein("", "abc"))
+}
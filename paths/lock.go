@@ -0,0 +1,178 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/samber/lo"
+	goyaml "go.yaml.in/yaml/v3"
+)
+
+// LockFile is the default name of the lockfile, committed next to the YAML configuration.
+const LockFile = "synq-monitors.lock"
+
+const LockVersion = 1
+
+const lockHeader = `# Generated by synq-monitors paths lock --update, the SYNQ path every monitored path resolves to.
+# Deploy and plan use these SYNQ paths even when the paths resolve differently, and warn about it.
+# An ambiguous path can be pinned here by hand, the update keeps it.
+`
+
+// Lock records the SYNQ path each simple path of a workspace resolved to, so that the resolution is reproducible.
+type Lock struct {
+	Version   int               `yaml:"version"`
+	Workspace string            `yaml:"workspace"`
+	Paths     map[string]string `yaml:"paths"`
+}
+
+// Drift is a locked path which does not resolve to its locked SYNQ path anymore.
+// Live is the SYNQ path it resolves to now, empty when it is unresolved or ambiguous between Candidates.
+type Drift struct {
+	Locked     string   `json:"locked"               yaml:"locked"`
+	Live       string   `json:"live,omitempty"       yaml:"live,omitempty"`
+	Candidates []string `json:"candidates,omitempty" yaml:"candidates,omitempty"`
+}
+
+func NewLock(workspace string) *Lock {
+	return &Lock{
+		Version:   LockVersion,
+		Workspace: workspace,
+		Paths:     map[string]string{},
+	}
+}
+
+// ReadLock reads a lockfile, the returned error wraps os.ErrNotExist when there is none.
+func ReadLock(file string) (*Lock, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &Lock{}
+	if err := goyaml.Unmarshal(content, lock); err != nil {
+		return nil, fmt.Errorf("invalid lockfile %s: %w", file, err)
+	}
+	if lock.Version != LockVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d in %s, expected %d", lock.Version, file, LockVersion)
+	}
+	if lock.Paths == nil {
+		lock.Paths = map[string]string{}
+	}
+	return lock, nil
+}
+
+// Write writes the lockfile, with its paths sorted so that it diffs well.
+func (l *Lock) Write(file string) error {
+	content, err := goyaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append([]byte(lockHeader), content...), 0o644)
+}
+
+// Resolve resolves the paths from the lock only, the paths missing from it are unresolved.
+func (l *Lock) Resolve(paths []string) *Resolution {
+	resolution := NewResolution()
+	for _, path := range lo.Uniq(paths) {
+		if locked, ok := l.Paths[path]; ok {
+			resolution.Resolved[path] = locked
+			resolution.Strategies[path] = Strategy_Lockfile
+		} else {
+			resolution.Unresolved = append(resolution.Unresolved, path)
+		}
+	}
+	return resolution
+}
+
+// Update returns the lock of the given paths as they are resolved now.
+// A path which does not resolve anymore keeps its locked SYNQ path, so that the paths pinned by hand are kept.
+func (l *Lock) Update(workspace string, paths []string, resolution *Resolution) *Lock {
+	updated := NewLock(workspace)
+	for _, path := range paths {
+		if resolved, ok := resolution.Resolved[path]; ok {
+			updated.Paths[path] = resolved
+		} else if locked, ok := l.Paths[path]; ok && l.Workspace == workspace {
+			updated.Paths[path] = locked
+		}
+	}
+	return updated
+}
+
+// LockedPathConverter resolves paths from the lock only, without calling the API.
+func LockedPathConverter(lock *Lock) PathConverter {
+	return &lockedPathConverter{lock: lock}
+}
+
+type lockedPathConverter struct {
+	lock *Lock
+}
+
+func (c *lockedPathConverter) SimpleToPath(requestedPaths []string) (map[string]string, *SimpleToPathError) {
+	return simpleToPath(c.ResolvePaths, requestedPaths)
+}
+
+func (c *lockedPathConverter) ResolvePaths(requestedPaths []string) (*Resolution, error) {
+	return c.lock.Resolve(requestedPaths), nil
+}
+
+// PathToSimple returns the locked simple path of each SYNQ path, when there is exactly one, else its dot notation.
+func (c *lockedPathConverter) PathToSimple(paths []string) (map[string]string, error) {
+	simpleByPath := map[string][]string{}
+	for simple, path := range c.lock.Paths {
+		simpleByPath[path] = append(simpleByPath[path], simple)
+	}
+
+	simplifiedPaths := map[string]string{}
+	for _, path := range paths {
+		if simple := simpleByPath[path]; len(simple) == 1 {
+			simplifiedPaths[path] = simple[0]
+		} else {
+			simplifiedPaths[path] = PathWithDots(path)
+		}
+	}
+	return simplifiedPaths, nil
+}
+
+// SuggestPaths suggests nothing, the lock only knows the paths it resolves.
+func (c *lockedPathConverter) SuggestPaths(unresolvedPaths []string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+// WithLock returns a converter resolving the locked paths to their locked SYNQ path, and the others with the given converter.
+// The locked paths are still resolved with the given converter, those resolving differently are reported as Drifted.
+func WithLock(converter PathConverter, lock *Lock) PathConverter {
+	return &lockingPathConverter{PathConverter: converter, lock: lock}
+}
+
+type lockingPathConverter struct {
+	PathConverter
+	lock *Lock
+}
+
+func (c *lockingPathConverter) SimpleToPath(requestedPaths []string) (map[string]string, *SimpleToPathError) {
+	return simpleToPath(c.ResolvePaths, requestedPaths)
+}
+
+func (c *lockingPathConverter) ResolvePaths(requestedPaths []string) (*Resolution, error) {
+	resolution, err := c.PathConverter.ResolvePaths(requestedPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range lo.Uniq(requestedPaths) {
+		locked, ok := c.lock.Paths[path]
+		if !ok {
+			continue
+		}
+		if live := resolution.Resolved[path]; live != locked {
+			resolution.Drifted[path] = Drift{Locked: locked, Live: live, Candidates: resolution.Ambiguous[path]}
+		}
+		resolution.Resolved[path] = locked
+		resolution.Strategies[path] = Strategy_Lockfile
+		resolution.Unresolved = slices.DeleteFunc(resolution.Unresolved, func(unresolved string) bool { return unresolved == path })
+		resolution.Cached = slices.DeleteFunc(resolution.Cached, func(cached string) bool { return cached == path })
+		delete(resolution.Ambiguous, path)
+	}
+	return resolution, nil
+}
@@ -0,0 +1,87 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+
+	coordinatesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/coordinates/v1"
+	entitiesentitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/entities/v1"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *PathConverterTestSuite) TestLock() {
+	lock := NewLock("acme")
+	lock.Paths = map[string]string{
+		"analytics.events": "bq::analytics::events",
+		"public.orders":    "pg::public::orders",
+		"public.refunds":   "pg::public::refunds",
+	}
+
+	s.Run("write_and_read", func() {
+		file := filepath.Join(s.T().TempDir(), LockFile)
+		s.Require().NoError(lock.Write(file))
+		read, err := ReadLock(file)
+		s.Require().NoError(err)
+		s.Equal(lock, read)
+
+		_, err = ReadLock(filepath.Join(s.T().TempDir(), LockFile))
+		s.True(os.IsNotExist(err))
+
+		s.Require().NoError(os.WriteFile(file, []byte("version: 2\n"), 0o644))
+		_, err = ReadLock(file)
+		s.ErrorContains(err, "unsupported lockfile version 2")
+	})
+
+	s.Run("locked_only", func() {
+		converter := LockedPathConverter(lock)
+		resolved, resolveErr := converter.SimpleToPath([]string{"public.orders"})
+		s.Nil(resolveErr)
+		s.Equal(map[string]string{"public.orders": "pg::public::orders"}, resolved)
+
+		_, resolveErr = converter.SimpleToPath([]string{"public.orders", "public.payments"})
+		s.Equal([]string{"public.payments"}, resolveErr.UnresolvedPaths)
+
+		simplified, err := converter.PathToSimple([]string{"pg::public::orders", "pg::public::payments"})
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"pg::public::orders": "public.orders", "pg::public::payments": "pg.public.payments"}, simplified)
+	})
+
+	s.Run("locked_paths_win_over_live_resolution", func() {
+		s.mockEntities.EXPECT().BatchGetEntities(gomock.Any(), gomock.Any()).Return(&entitiesentitiesv1.BatchGetEntitiesResponse{}, nil)
+		s.mockCoordinates.EXPECT().BatchIdsByCoordinates(gomock.Any(), gomock.Any()).Return(&coordinatesv1.BatchIdsByCoordinatesResponse{
+			MatchedCoordinates: []*coordinatesv1.BatchIdsByCoordinatesResponse_MatchedCoordinates{
+				{SqlFqn: "analytics.events", Candidates: []*coordinatesv1.DatabaseCoordinates{{SynqPaths: []string{"bq::analytics::events"}}}},
+				{SqlFqn: "public.orders", Candidates: []*coordinatesv1.DatabaseCoordinates{{SynqPaths: []string{"pg::billing::orders"}}}},
+				{SqlFqn: "public.refunds"},
+				{SqlFqn: "public.payments", Candidates: []*coordinatesv1.DatabaseCoordinates{{SynqPaths: []string{"pg::public::payments"}}}},
+			},
+		}, nil)
+
+		resolution, err := WithLock(s.converter, lock).ResolvePaths([]string{"analytics.events", "public.orders", "public.refunds", "public.payments"})
+		s.Require().NoError(err)
+		s.Equal(map[string]string{
+			"analytics.events": "bq::analytics::events",
+			"public.orders":    "pg::public::orders",
+			"public.refunds":   "pg::public::refunds",
+			"public.payments":  "pg::public::payments",
+		}, resolution.Resolved)
+		s.Equal(Strategy_Coordinate, resolution.Strategies["public.payments"])
+		s.Equal(Strategy_Lockfile, resolution.Strategies["public.orders"])
+		s.Empty(resolution.Unresolved)
+		s.Equal(map[string]Drift{
+			"public.orders":  {Locked: "pg::public::orders", Live: "pg::billing::orders"},
+			"public.refunds": {Locked: "pg::public::refunds"},
+		}, resolution.Drifted)
+
+		// The update takes the live resolution, and keeps the locked paths which do not resolve anymore.
+		updated := lock.Update("acme", []string{"public.orders", "public.refunds", "public.payments", "missing"}, &Resolution{
+			Resolved: map[string]string{"public.orders": "pg::billing::orders", "public.payments": "pg::public::payments"},
+		})
+		s.Equal(map[string]string{
+			"public.orders":   "pg::billing::orders",
+			"public.refunds":  "pg::public::refunds",
+			"public.payments": "pg::public::payments",
+		}, updated.Paths)
+		s.Empty(lock.Update("other", []string{"public.refunds"}, NewResolution()).Paths)
+	})
+}
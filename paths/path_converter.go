@@ -16,7 +16,9 @@ import (
 
 type PathConverter interface {
 	SimpleToPath(simple []string) (map[string]string, *SimpleToPathError)
+	ResolvePaths(simple []string) (*Resolution, error)
 	PathToSimple(paths []string) (map[string]string, error)
+	SuggestPaths(unresolved []string) (map[string][]string, error)
 }
 
 type pathConverter struct {
@@ -45,15 +47,46 @@ func NewPathConverter(
 // If a simple path resolves to multiple valid SYNQ paths, it is added to the error.
 // If a simple path cannot be resolved, it is added to the error.
 func (s *pathConverter) SimpleToPath(requestedPaths []string) (map[string]string, *SimpleToPathError) {
+	return simpleToPath(s.ResolvePaths, requestedPaths)
+}
+
+// simpleToPath implements SimpleToPath with the ResolvePaths of a converter.
+func simpleToPath(
+	resolvePaths func(requestedPaths []string) (*Resolution, error),
+	requestedPaths []string,
+) (map[string]string, *SimpleToPathError) {
 	if len(requestedPaths) == 0 {
 		return map[string]string{}, nil
 	}
 
-	Err := &SimpleToPathError{
-		UnresolvedPaths:                       []string{},
-		MonitoredEntitiesWithMultipleEntities: map[string][]string{},
+	resolution, err := resolvePaths(requestedPaths)
+	if err != nil {
+		return nil, &SimpleToPathError{
+			Err:                                   err,
+			UnresolvedPaths:                       []string{},
+			MonitoredEntitiesWithMultipleEntities: map[string][]string{},
+		}
+	}
+	return resolution.SimpleToPath(requestedPaths)
+}
+
+// Resolves the given paths like SimpleToPath, BatchSize paths per request, and records how each one was resolved.
+func (s *pathConverter) ResolvePaths(requestedPaths []string) (*Resolution, error) {
+	resolution := NewResolution()
+	for _, chunk := range lo.Chunk(lo.Uniq(requestedPaths), BatchSize) {
+		if err := s.resolveChunk(chunk, resolution); err != nil {
+			return nil, err
+		}
+	}
+	return resolution, nil
+}
+
+func (s *pathConverter) resolveChunk(requestedPaths []string, resolution *Resolution) error {
+	resolvedPaths := resolution.Resolved
+	resolve := func(path, synqPath string, strategy Strategy) {
+		resolvedPaths[path] = synqPath
+		resolution.Strategies[path] = strategy
 	}
-	resolvedPaths := map[string]string{}
 
 	// fetch entities for all paths
 	{
@@ -69,20 +102,19 @@ func (s *pathConverter) SimpleToPath(requestedPaths []string) (map[string]string
 			}),
 		})
 		if err != nil {
-			Err.Err = fmt.Errorf("error fetching entities for monitored paths: %w", err)
-			return nil, Err
+			return fmt.Errorf("error fetching entities for monitored paths: %w", err)
 		}
 
 		for _, entity := range resp.Entities {
 			synqPath := entity.Id.GetSynqPath().GetPath()
 			if len(synqPath) > 0 {
-				requested := synqPath
+				requested, strategy := synqPath, Strategy_SynqPath
 				if !slices.Contains(requestedPaths, synqPath) {
-					requested = PathWithDots(synqPath)
+					requested, strategy = PathWithDots(synqPath), Strategy_DotNotation
 				}
 				if entity.EntityType != nil && slices.Contains(ValidMonitoredTypes, *entity.EntityType) {
 					// valid monitored type
-					resolvedPaths[requested] = synqPath
+					resolve(requested, synqPath, strategy)
 					continue
 				}
 			}
@@ -100,18 +132,17 @@ func (s *pathConverter) SimpleToPath(requestedPaths []string) (map[string]string
 				SqlFqn: pathsToFetchCoordinates,
 			})
 			if err != nil {
-				Err.Err = fmt.Errorf("error fetching coordinates for monitored paths: %w", err)
-				return nil, Err
+				return fmt.Errorf("error fetching coordinates for monitored paths: %w", err)
 			}
 
 			ambiguiousPathsByCoordinate := map[string][]string{}
 			for _, coord := range coordResp.MatchedCoordinates {
 				if len(coord.Candidates) == 0 || (len(coord.Candidates) == 1 && len(coord.Candidates[0].SynqPaths) == 0) {
-					Err.UnresolvedPaths = append(Err.UnresolvedPaths, coord.SqlFqn)
+					resolution.Unresolved = append(resolution.Unresolved, coord.SqlFqn)
 					continue
 				}
 				if len(coord.Candidates) == 1 && len(coord.Candidates[0].SynqPaths) == 1 {
-					resolvedPaths[coord.SqlFqn] = coord.Candidates[0].SynqPaths[0]
+					resolve(coord.SqlFqn, coord.Candidates[0].SynqPaths[0], Strategy_Coordinate)
 					continue
 				}
 				// more than one possible candidate or synq paths
@@ -126,25 +157,26 @@ func (s *pathConverter) SimpleToPath(requestedPaths []string) (map[string]string
 			}))
 			if len(allAmbiguousPaths) > 0 {
 				// fetch entities for all ambiguous paths and see if they are valid types
-				resp, err := s.entitiesService.BatchGetEntities(s.ctx, &entitiesentitiesv1.BatchGetEntitiesRequest{
-					Ids: lo.Map(allAmbiguousPaths, func(path string, _ int) *entitiesv1.Identifier {
-						return &entitiesv1.Identifier{
-							Id: &entitiesv1.Identifier_SynqPath{
-								SynqPath: &entitiesv1.SynqPathIdentifier{
-									Path: PathWithColons(path),
+				typesByPath := map[string]*entitiesv1.EntityType{}
+				for _, ambiguousPaths := range lo.Chunk(allAmbiguousPaths, BatchSize) {
+					resp, err := s.entitiesService.BatchGetEntities(s.ctx, &entitiesentitiesv1.BatchGetEntitiesRequest{
+						Ids: lo.Map(ambiguousPaths, func(path string, _ int) *entitiesv1.Identifier {
+							return &entitiesv1.Identifier{
+								Id: &entitiesv1.Identifier_SynqPath{
+									SynqPath: &entitiesv1.SynqPathIdentifier{
+										Path: PathWithColons(path),
+									},
 								},
-							},
-						}
-					}),
-				})
-				if err != nil {
-					Err.Err = fmt.Errorf("error fetching entities for ambiguous paths: %w", err)
-					return nil, Err
-				}
+							}
+						}),
+					})
+					if err != nil {
+						return fmt.Errorf("error fetching entities for ambiguous paths: %w", err)
+					}
 
-				typesByPath := map[string]*entitiesv1.EntityType{}
-				for _, entity := range resp.Entities {
-					typesByPath[entity.SynqPath] = entity.EntityType
+					for _, entity := range resp.Entities {
+						typesByPath[entity.SynqPath] = entity.EntityType
+					}
 				}
 
 				for coord, paths := range ambiguiousPathsByCoordinate {
@@ -153,21 +185,18 @@ func (s *pathConverter) SimpleToPath(requestedPaths []string) (map[string]string
 						return ok && slices.Contains(ValidMonitoredTypes, *typ)
 					})
 					if len(validPaths) == 1 {
-						resolvedPaths[coord] = validPaths[0]
+						resolve(coord, validPaths[0], Strategy_Coordinate)
 					} else if len(validPaths) > 1 {
-						Err.MonitoredEntitiesWithMultipleEntities[coord] = lo.Uniq(validPaths)
+						resolution.Ambiguous[coord] = lo.Uniq(validPaths)
 					} else {
-						Err.UnresolvedPaths = append(Err.UnresolvedPaths, coord)
+						resolution.Unresolved = append(resolution.Unresolved, coord)
 					}
 				}
 			}
 		}
 	}
 
-	if Err.HasErrors() {
-		return nil, Err
-	}
-	return resolvedPaths, nil
+	return nil
 }
 
 // Returns the simplified version of the requested paths.
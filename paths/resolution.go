@@ -0,0 +1,113 @@
+package paths
+
+import "github.com/samber/lo"
+
+// Strategy is how a path was resolved to a SYNQ path.
+type Strategy string
+
+const (
+	Strategy_SynqPath    Strategy = "synq_path"
+	Strategy_Coordinate  Strategy = "db_coordinate"
+	Strategy_DotNotation Strategy = "dot_notation"
+	Strategy_Lockfile    Strategy = "lockfile"
+	Strategy_Chosen      Strategy = "chosen"
+)
+
+var Strategies = []Strategy{Strategy_SynqPath, Strategy_Coordinate, Strategy_DotNotation, Strategy_Lockfile, Strategy_Chosen}
+
+// BatchSize is the number of paths sent in a single request, within the limits of the API.
+const BatchSize = 100
+
+// Resolution is the outcome of resolving many paths at once, for example those of every namespace,
+// from which the paths of each namespace are then looked up.
+type Resolution struct {
+	Resolved   map[string]string
+	Strategies map[string]Strategy
+	Unresolved []string
+	Ambiguous  map[string][]string
+	// Cached are the resolved paths which were served from the cache instead of the API.
+	Cached []string
+	// Drifted are the locked paths which resolve differently than their lock.
+	Drifted map[string]Drift
+	// Suggestions are the SYNQ paths close to the unresolved paths, when they were suggested.
+	Suggestions map[string][]string
+}
+
+// NewResolution returns a resolution of no paths.
+func NewResolution() *Resolution {
+	return &Resolution{
+		Resolved:    map[string]string{},
+		Strategies:  map[string]Strategy{},
+		Unresolved:  []string{},
+		Ambiguous:   map[string][]string{},
+		Cached:      []string{},
+		Drifted:     map[string]Drift{},
+		Suggestions: map[string][]string{},
+	}
+}
+
+// Covers tells whether the resolution knows the outcome of every given path.
+func (r *Resolution) Covers(paths []string) bool {
+	unresolved := lo.Keyify(r.Unresolved)
+	return lo.EveryBy(paths, func(path string) bool {
+		_, resolved := r.Resolved[path]
+		_, ambiguous := r.Ambiguous[path]
+		_, isUnresolved := unresolved[path]
+		return resolved || ambiguous || isUnresolved
+	})
+}
+
+// SimpleToPath returns what PathConverter.SimpleToPath would return for the given paths.
+func (r *Resolution) SimpleToPath(paths []string) (map[string]string, *SimpleToPathError) {
+	Err := &SimpleToPathError{
+		UnresolvedPaths:                       []string{},
+		MonitoredEntitiesWithMultipleEntities: map[string][]string{},
+		Suggestions:                           map[string][]string{},
+	}
+	resolvedPaths := map[string]string{}
+	unresolved := lo.Keyify(r.Unresolved)
+	for _, path := range lo.Uniq(paths) {
+		if resolved, ok := r.Resolved[path]; ok {
+			resolvedPaths[path] = resolved
+		} else if candidates, ok := r.Ambiguous[path]; ok {
+			Err.MonitoredEntitiesWithMultipleEntities[path] = candidates
+		} else if _, ok := unresolved[path]; ok {
+			Err.UnresolvedPaths = append(Err.UnresolvedPaths, path)
+			if suggestions, ok := r.Suggestions[path]; ok {
+				Err.Suggestions[path] = suggestions
+			}
+		}
+	}
+
+	if Err.HasErrors() {
+		return nil, Err
+	}
+	return resolvedPaths, nil
+}
+
+// CountByStrategy returns how many paths each strategy resolved.
+func (r *Resolution) CountByStrategy() map[Strategy]int {
+	counts := map[Strategy]int{}
+	for _, strategy := range r.Strategies {
+		counts[strategy]++
+	}
+	return counts
+}
+
+// WithResolution returns a converter answering SimpleToPath from the resolution, for the paths it covers,
+// and from the given converter otherwise.
+func WithResolution(converter PathConverter, resolution *Resolution) PathConverter {
+	return &resolvedPathConverter{PathConverter: converter, resolution: resolution}
+}
+
+type resolvedPathConverter struct {
+	PathConverter
+	resolution *Resolution
+}
+
+func (c *resolvedPathConverter) SimpleToPath(paths []string) (map[string]string, *SimpleToPathError) {
+	if !c.resolution.Covers(paths) {
+		return c.PathConverter.SimpleToPath(paths)
+	}
+	return c.resolution.SimpleToPath(paths)
+}
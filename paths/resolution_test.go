@@ -0,0 +1,75 @@
+package paths
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	coordinatesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/coordinates/v1"
+	entitiesentitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/entities/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+)
+
+func (s *PathConverterTestSuite) TestResolvePaths() {
+	input := []string{"pg::public::orders", "pg.public.payments", "analytics.events", "missing", "pg::public::orders"}
+	for i := range BatchSize {
+		input = append(input, fmt.Sprintf("pg::public::table_%d", i))
+	}
+
+	// Every pg entity exists, the other paths are DB coordinates.
+	s.mockEntities.EXPECT().BatchGetEntities(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, req *entitiesentitiesv1.BatchGetEntitiesRequest, _ ...grpc.CallOption) (*entitiesentitiesv1.BatchGetEntitiesResponse, error) {
+			s.LessOrEqual(len(req.Ids), BatchSize)
+			resp := &entitiesentitiesv1.BatchGetEntitiesResponse{}
+			for _, id := range req.Ids {
+				if strings.HasPrefix(id.GetSynqPath().GetPath(), "pg::") {
+					resp.Entities = append(resp.Entities, &entitiesv1.Entity{Id: id, EntityType: entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE.Enum()})
+				}
+			}
+			return resp, nil
+		},
+	)
+	s.mockCoordinates.EXPECT().BatchIdsByCoordinates(gomock.Any(), gomock.Any()).Return(&coordinatesv1.BatchIdsByCoordinatesResponse{
+		MatchedCoordinates: []*coordinatesv1.BatchIdsByCoordinatesResponse_MatchedCoordinates{
+			{SqlFqn: "analytics.events", Candidates: []*coordinatesv1.DatabaseCoordinates{{SynqPaths: []string{"bq::analytics::events"}}}},
+			{SqlFqn: "missing"},
+		},
+	}, nil)
+
+	resolution, err := s.converter.ResolvePaths(input)
+	s.Require().NoError(err)
+	s.Equal(Strategy_SynqPath, resolution.Strategies["pg::public::orders"])
+	s.Equal(Strategy_DotNotation, resolution.Strategies["pg.public.payments"])
+	s.Equal(Strategy_Coordinate, resolution.Strategies["analytics.events"])
+	s.Equal(map[Strategy]int{Strategy_SynqPath: BatchSize + 1, Strategy_DotNotation: 1, Strategy_Coordinate: 1}, resolution.CountByStrategy())
+	s.Equal([]string{"missing"}, resolution.Unresolved)
+
+	resolved, resolveErr := resolution.SimpleToPath([]string{"pg.public.payments", "analytics.events"})
+	s.Nil(resolveErr)
+	s.Equal(map[string]string{"pg.public.payments": "pg::public::payments", "analytics.events": "bq::analytics::events"}, resolved)
+	resolved, resolveErr = resolution.SimpleToPath([]string{"pg::public::orders", "missing"})
+	s.Nil(resolved)
+	s.Equal([]string{"missing"}, resolveErr.UnresolvedPaths)
+
+	// Covered paths are served without calling the API again, the others are resolved as usual.
+	converter := WithResolution(s.converter, resolution)
+	resolved, resolveErr = converter.SimpleToPath([]string{"pg::public::orders"})
+	s.Nil(resolveErr)
+	s.Equal(map[string]string{"pg::public::orders": "pg::public::orders"}, resolved)
+
+	s.mockEntities.EXPECT().BatchGetEntities(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *entitiesentitiesv1.BatchGetEntitiesRequest, _ ...grpc.CallOption) (*entitiesentitiesv1.BatchGetEntitiesResponse, error) {
+			s.Len(req.Ids, 2)
+			resp := &entitiesentitiesv1.BatchGetEntitiesResponse{}
+			for _, id := range req.Ids {
+				resp.Entities = append(resp.Entities, &entitiesv1.Entity{Id: id, EntityType: entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE.Enum()})
+			}
+			return resp, nil
+		},
+	)
+	resolved, resolveErr = converter.SimpleToPath([]string{"pg::public::orders", "pg::public::refunds"})
+	s.Nil(resolveErr)
+	s.Equal(map[string]string{"pg::public::orders": "pg::public::orders", "pg::public::refunds": "pg::public::refunds"}, resolved)
+}
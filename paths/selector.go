@@ -0,0 +1,153 @@
+package paths
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"buf.build/gen/go/getsynq/api/grpc/go/synq/entities/coordinates/v1/coordinatesv1grpc"
+	"buf.build/gen/go/getsynq/api/grpc/go/synq/entities/entities/v1/entitiesv1grpc"
+	entitiesentitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/entities/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+)
+
+// listPageSize is the number of entities listed in a single request.
+const listPageSize int32 = 1000
+
+// Selector matches the SYNQ paths of the monitorable entities selected by an entity selector.
+type Selector struct {
+	selector core.EntitySelector
+	types    []entitiesv1.EntityType
+	pattern  *regexp.Regexp
+}
+
+// NewSelector checks that the selector is valid and only selects entities which can be monitored.
+//
+// A glob is matched against as many trailing parts of a SYNQ path as it has, in dot notation, so that sales.*
+// matches the tables of the sales schema, and bq-prod.sales.* only those of the bq-prod integration.
+// A regex is matched against the SYNQ path, and against its dot notation.
+func NewSelector(selector core.EntitySelector) (*Selector, error) {
+	if err := selector.Validate(); err != nil {
+		return nil, err
+	}
+
+	types := ValidMonitoredTypes
+	if len(selector.Types) > 0 {
+		types = []entitiesv1.EntityType{}
+		for _, name := range selector.Types {
+			entityType, _ := core.ParseEntityType(name)
+			if !slices.Contains(ValidMonitoredTypes, entityType) {
+				return nil, fmt.Errorf("entity type %s cannot be monitored", name)
+			}
+			types = append(types, entityType)
+		}
+	}
+
+	pattern := selector.Regex
+	if selector.Match != "" {
+		pattern = globPattern(PathWithDots(selector.Match))
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", selector, err)
+	}
+	return &Selector{selector: selector, types: types, pattern: compiled}, nil
+}
+
+func (s *Selector) String() string {
+	return s.selector.String()
+}
+
+// Matches tells whether the selector selects an entity of the given SYNQ path and type.
+func (s *Selector) Matches(synqPath string, entityType entitiesv1.EntityType) bool {
+	if !slices.Contains(s.types, entityType) {
+		return false
+	}
+	if s.selector.Regex != "" {
+		return s.pattern.MatchString(synqPath) || s.pattern.MatchString(PathWithDots(synqPath))
+	}
+	parts := strings.Split(PathWithDots(synqPath), ".")
+	globParts := strings.Count(PathWithDots(s.selector.Match), ".") + 1
+	return len(parts) >= globParts && s.pattern.MatchString(strings.Join(parts[len(parts)-globParts:], "."))
+}
+
+// globPattern returns the regular expression of a glob in dot notation, whose wildcards match within a part.
+func globPattern(glob string) string {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, char := range glob {
+		switch char {
+		case '*':
+			pattern.WriteString(`[^.]*`)
+		case '?':
+			pattern.WriteString(`[^.]`)
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	pattern.WriteString("$")
+	return pattern.String()
+}
+
+type EntityExpander interface {
+	ExpandSelectors(selectors []*Selector) (map[string][]string, error)
+}
+
+func NewEntityExpander(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+) EntityExpander {
+	return &pathConverter{
+		ctx:                ctx,
+		entitiesService:    entitiesv1grpc.NewEntitiesServiceClient(conn),
+		coordinatesService: coordinatesv1grpc.NewDatabaseCoordinatesServiceClient(conn),
+	}
+}
+
+// Returns the sorted SYNQ paths of the entities matched by each selector, keyed by its String().
+// The entities of every selected type are listed once, whatever the number of selectors.
+func (s *pathConverter) ExpandSelectors(selectors []*Selector) (map[string][]string, error) {
+	matched := map[string][]string{}
+	types := lo.Uniq(lo.FlatMap(selectors, func(selector *Selector, _ int) []entitiesv1.EntityType { return selector.types }))
+	for _, selector := range selectors {
+		matched[selector.String()] = []string{}
+	}
+	if len(selectors) == 0 {
+		return matched, nil
+	}
+
+	request := &entitiesentitiesv1.ListEntitiesRequest{EntityTypes: types, PageSize: lo.ToPtr(listPageSize)}
+	for {
+		resp, err := s.entitiesService.ListEntities(s.ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("error listing entities: %w", err)
+		}
+		for _, entity := range resp.Entities {
+			synqPath := lo.CoalesceOrEmpty(entity.Id.GetSynqPath().GetPath(), entity.SynqPath)
+			if synqPath == "" || entity.EntityType == nil {
+				continue
+			}
+			for _, selector := range selectors {
+				if selector.Matches(synqPath, *entity.EntityType) {
+					matched[selector.String()] = append(matched[selector.String()], synqPath)
+				}
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		request.PageToken = lo.ToPtr(resp.NextPageToken)
+	}
+
+	for key, synqPaths := range matched {
+		synqPaths = lo.Uniq(synqPaths)
+		slices.Sort(synqPaths)
+		matched[key] = synqPaths
+	}
+	return matched, nil
+}
@@ -0,0 +1,62 @@
+package paths
+
+import (
+	"context"
+	"slices"
+
+	entitiesentitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/entities/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/samber/lo"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+)
+
+func (s *PathConverterTestSuite) TestExpandSelectors() {
+	catalog := []*entitiesv1.Entity{
+		{SynqPath: "bq-prod::sales::orders", EntityType: entitiesv1.EntityType_ENTITY_TYPE_BQ_TABLE.Enum()},
+		{SynqPath: "bq-prod::sales::payments", EntityType: entitiesv1.EntityType_ENTITY_TYPE_BQ_TABLE.Enum()},
+		{SynqPath: "bq-prod::billing::invoices", EntityType: entitiesv1.EntityType_ENTITY_TYPE_BQ_TABLE.Enum()},
+		{SynqPath: "bq-prod::sales::orders_daily", EntityType: entitiesv1.EntityType_ENTITY_TYPE_BQ_VIEW.Enum()},
+		{SynqPath: "pg-prod::sales::orders", EntityType: entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE.Enum()},
+		{SynqPath: "pg-prod::sales::orders::id", EntityType: entitiesv1.EntityType_ENTITY_TYPE_POSTGRES_TABLE.Enum()},
+	}
+	// Two entities per page, to list every page.
+	s.mockEntities.EXPECT().ListEntities(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(
+		func(_ context.Context, req *entitiesentitiesv1.ListEntitiesRequest, _ ...grpc.CallOption) (*entitiesentitiesv1.ListEntitiesResponse, error) {
+			entities := lo.Filter(catalog, func(entity *entitiesv1.Entity, _ int) bool {
+				return slices.Contains(req.EntityTypes, entity.GetEntityType())
+			})
+			page := lo.Slice(entities, 2*len(req.GetPageToken()), 2*len(req.GetPageToken())+2)
+			resp := &entitiesentitiesv1.ListEntitiesResponse{Entities: page}
+			if 2*len(req.GetPageToken())+2 < len(entities) {
+				resp.NextPageToken = req.GetPageToken() + "x"
+			}
+			return resp, nil
+		},
+	)
+
+	selector := func(entitySelector core.EntitySelector) *Selector {
+		selector, err := NewSelector(entitySelector)
+		s.Require().NoError(err)
+		return selector
+	}
+	matched, err := s.converter.ExpandSelectors([]*Selector{
+		selector(core.EntitySelector{Match: "bq-prod.sales.*", Types: []string{"bq_table"}}),
+		selector(core.EntitySelector{Match: "sales.order?"}),
+		selector(core.EntitySelector{Regex: `^pg-prod::.*::orders$`}),
+		selector(core.EntitySelector{Match: "snowflake::sales::*"}),
+	})
+	s.Require().NoError(err)
+	s.Equal(map[string][]string{
+		"bq-prod.sales.* [bq_table]": {"bq-prod::sales::orders", "bq-prod::sales::payments"},
+		"sales.order?":               {"bq-prod::sales::orders", "pg-prod::sales::orders"},
+		"/^pg-prod::.*::orders$/":    {"pg-prod::sales::orders"},
+		"snowflake::sales::*":        {},
+	}, matched)
+
+	_, err = NewSelector(core.EntitySelector{Match: "sales.*", Types: []string{"dbt_model"}})
+	s.EqualError(err, "entity type dbt_model cannot be monitored")
+	_, err = NewSelector(core.EntitySelector{Types: []string{"bq_table"}})
+	s.EqualError(err, "match or regex must be set")
+}
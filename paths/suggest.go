@@ -0,0 +1,128 @@
+package paths
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	entitiesentitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/entities/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	"github.com/samber/lo"
+)
+
+// SuggestionsLimit is the number of paths suggested for an unresolved path.
+const SuggestionsLimit = 3
+
+// searchLimit is the number of entities searched for an unresolved path, among which the closest are suggested.
+const searchLimit int32 = 25
+
+// SuggestedPathsLimit is the number of unresolved paths suggestions are searched for, so that a wrong workspace
+// or a mass typo does not search the entities of every path.
+const SuggestedPathsLimit = 50
+
+// searchParallelism is the number of entity searches run at once.
+const searchParallelism = 8
+
+// Returns the SYNQ paths closest to each unresolved path, searching the monitorable entities named like it.
+// Paths without close matches have no suggestions, like those beyond the first SuggestedPathsLimit.
+func (s *pathConverter) SuggestPaths(unresolvedPaths []string) (map[string][]string, error) {
+	unresolvedPaths = lo.Slice(lo.Uniq(unresolvedPaths), 0, SuggestedPathsLimit)
+	closest := make([][]string, len(unresolvedPaths))
+	errs := make([]error, len(unresolvedPaths))
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, searchParallelism)
+	for i, path := range unresolvedPaths {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			closest[i], errs[i] = s.searchClosestPaths(path)
+		}()
+	}
+	wg.Wait()
+
+	suggestions := map[string][]string{}
+	for i, path := range unresolvedPaths {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if len(closest[i]) > 0 {
+			suggestions[path] = closest[i]
+		}
+	}
+	return suggestions, nil
+}
+
+func (s *pathConverter) searchClosestPaths(path string) ([]string, error) {
+	resp, err := s.entitiesService.SearchEntities(s.ctx, &entitiesentitiesv1.SearchEntitiesRequest{
+		Query:       pathName(path),
+		EntityTypes: ValidMonitoredTypes,
+		Limit:       lo.ToPtr(searchLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error searching entities like %s: %w", path, err)
+	}
+
+	found := lo.Uniq(lo.FilterMap(resp.Entities, func(entity *entitiesv1.Entity, _ int) (string, bool) {
+		synqPath := lo.CoalesceOrEmpty(entity.Id.GetSynqPath().GetPath(), entity.SynqPath)
+		return synqPath, len(synqPath) > 0
+	}))
+	return closestPaths(path, found), nil
+}
+
+// pathName returns the last part of a path, the name of the table it monitors.
+func pathName(path string) string {
+	parts := strings.Split(PathWithDots(path), ".")
+	return parts[len(parts)-1]
+}
+
+// closestPaths returns the SuggestionsLimit found SYNQ paths closest to the path, ignoring those too different to be a typo.
+//
+// A SYNQ path is compared on as many trailing parts as the path has, so that "public.ordrs" is close to
+// "postgres::public::orders", and the integration prefix of a dot notation path is not held against it.
+func closestPaths(path string, found []string) []string {
+	requested := strings.Split(strings.ToLower(PathWithDots(path)), ".")
+	distances := map[string]int{}
+	for _, synqPath := range found {
+		parts := strings.Split(strings.ToLower(PathWithDots(synqPath)), ".")
+		compared := parts[max(0, len(parts)-len(requested)):]
+		distance := levenshtein(strings.Join(requested, "."), strings.Join(compared, "."))
+		if distance <= max(2, len(path)/4) {
+			distances[synqPath] = distance
+		}
+	}
+
+	closest := lo.Keys(distances)
+	slices.SortFunc(closest, func(a, b string) int {
+		if distances[a] != distances[b] {
+			return distances[a] - distances[b]
+		}
+		return strings.Compare(a, b)
+	})
+	return lo.Slice(closest, 0, SuggestionsLimit)
+}
+
+// levenshtein returns the number of single character edits turning a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			substitution := previous[j-1]
+			if ra[i-1] != rb[j-1] {
+				substitution++
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+		previous = current
+	}
+	return previous[len(rb)]
+}
@@ -3,6 +3,7 @@ package uuid
 import (
 	"strings"
 
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/google/uuid"
 )
@@ -21,18 +22,33 @@ func NewUUIDGenerator(workspace string) *UUIDGenerator {
 }
 
 func (g *UUIDGenerator) GenerateMonitorUUID(monitor *pb.MonitorDefinition) string {
-	// return monitor.Id if it is a valid UUID
-	parsed, err := uuid.Parse(monitor.Id)
-	if err == nil {
-		return parsed.String()
-	}
-
-	fields := []string{
+	return g.generate(
 		monitor.Id,
 		monitor.ConfigId,
 		monitor.MonitoredId.GetSynqPath().GetPath(),
+	)
+}
+
+// SQL tests are salted with their kind so that a test and a monitor
+// sharing an ID on the same entity never collide.
+func (g *UUIDGenerator) GenerateSqlTestUUID(sqlTest *sqltestsv1.SqlTest) string {
+	return g.generate(
+		sqlTest.Id,
+		sqlTest.ConfigId,
+		sqlTest.MonitoredId.GetSynqPath().GetPath(),
+		"sql_test",
+	)
+}
+
+func (g *UUIDGenerator) generate(id string, fields ...string) string {
+	// return id if it is a valid UUID
+	parsed, err := uuid.Parse(id)
+	if err == nil {
+		return parsed.String()
 	}
 
+	fields = append([]string{id}, fields...)
+
 	// Join fields with a separator
 	input := strings.Join(fields, "")
 	return uuid.NewSHA1(g.uuidSeed, []byte(input)).String()
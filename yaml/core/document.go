@@ -0,0 +1,68 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	goyaml "go.yaml.in/yaml/v3"
+)
+
+// Style is the indentation of a YAML source, so that a document edited as nodes is written back the way it was read.
+type Style struct {
+	Indent int
+	// CompactSequences is set when sequence items are not indented below their key.
+	CompactSequences bool
+}
+
+var DefaultStyle = Style{Indent: 4}
+
+// DetectStyle guesses the style of a YAML source from the indentation of its lines,
+// falling back to the style of the generators.
+func DetectStyle(content []byte) Style {
+	style := Style{}
+	previousKeyIndent := -1
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(trimmed)
+		if indent > 0 && (style.Indent == 0 || indent < style.Indent) {
+			style.Indent = indent
+		}
+		if strings.HasPrefix(trimmed, "- ") && indent == previousKeyIndent {
+			style.CompactSequences = true
+		}
+
+		previousKeyIndent = -1
+		if strings.HasSuffix(trimmed, ":") {
+			previousKeyIndent = indent
+		}
+	}
+
+	if style.Indent < 2 || style.Indent > 8 {
+		style.Indent = DefaultStyle.Indent
+	}
+	return style
+}
+
+func MarshalDocument(document *goyaml.Node, style Style) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	encoder := goyaml.NewEncoder(&buffer)
+	encoder.SetIndent(style.Indent)
+	if style.CompactSequences {
+		encoder.CompactSeqIndent()
+	}
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"strings"
+
+	goyaml "go.yaml.in/yaml/v3"
+)
+
+// Position points at a node of the YAML source, 1-based like goyaml.Node.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) IsSet() bool {
+	return p.Line > 0
+}
+
+func PositionOf(node *goyaml.Node) Position {
+	if node == nil {
+		return Position{}
+	}
+	return Position{Line: node.Line, Column: node.Column}
+}
+
+// LocatedError is implemented by conversion errors which know where in the YAML source they come from.
+type LocatedError interface {
+	error
+	GetPosition() Position
+}
+
+func ParseDocument(bytes []byte) (*goyaml.Node, error) {
+	var document goyaml.Node
+	err := goyaml.Unmarshal(bytes, &document)
+	if err != nil {
+		return nil, err
+	}
+	if document.Kind == goyaml.DocumentNode && len(document.Content) > 0 {
+		return document.Content[0], nil
+	}
+	return nil, nil
+}
+
+// MappingEntry returns the key and value nodes for key, or nils if node is not a mapping containing it.
+func MappingEntry(node *goyaml.Node, key string) (*goyaml.Node, *goyaml.Node) {
+	if node == nil || node.Kind != goyaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], resolveAlias(node.Content[i+1])
+		}
+	}
+	return nil, nil
+}
+
+func MappingValue(node *goyaml.Node, key string) *goyaml.Node {
+	_, value := MappingEntry(node, key)
+	return value
+}
+
+// SequenceItems returns the items of a sequence, with their aliases resolved, or nil if node is not a sequence.
+func SequenceItems(node *goyaml.Node) []*goyaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != goyaml.SequenceNode {
+		return nil
+	}
+	items := make([]*goyaml.Node, 0, len(node.Content))
+	for _, item := range node.Content {
+		items = append(items, resolveAlias(item))
+	}
+	return items
+}
+
+func SequenceItem(node *goyaml.Node, match func(item *goyaml.Node) bool) *goyaml.Node {
+	for _, item := range SequenceItems(node) {
+		if match(item) {
+			return item
+		}
+	}
+	return nil
+}
+
+// SequenceItemWithID finds the mapping in a sequence whose `id` equals id.
+func SequenceItemWithID(node *goyaml.Node, id string) *goyaml.Node {
+	return SequenceItem(node, func(item *goyaml.Node) bool {
+		value := MappingValue(item, "id")
+		return value != nil && value.Value == id
+	})
+}
+
+// FieldPosition locates a possibly dotted field (e.g. `mode.anomaly_engine.sensitivity`) below node,
+// falling back to the deepest node found on the way.
+func FieldPosition(node *goyaml.Node, field string) Position {
+	position := PositionOf(node)
+	if field == "" {
+		return position
+	}
+
+	current := node
+	for _, part := range strings.Split(field, ".") {
+		key, value := MappingEntry(current, part)
+		if key == nil {
+			break
+		}
+		position = PositionOf(key)
+		current = value
+	}
+	return position
+}
+
+func resolveAlias(node *goyaml.Node) *goyaml.Node {
+	for node != nil && node.Kind == goyaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	"github.com/pkg/errors"
+)
+
+// EntitySelector declares the entities whose SYNQ path or DB coordinate matches a pattern, instead of a single one.
+// Match is a glob like bq-prod.sales.*, where * and ? do not cross the dots separating the parts of a path,
+// and Regex a regular expression. Types restricts the matched entities to some types, like bq_table.
+type EntitySelector struct {
+	Match string   `yaml:"match,omitempty" jsonschema:"oneof_required=match"`
+	Regex string   `yaml:"regex,omitempty" jsonschema:"oneof_required=regex"`
+	Types []string `yaml:"types,omitempty"`
+}
+
+// SelectorParser is implemented by the parsers of the versions which declare entities by selector.
+type SelectorParser interface {
+	// EntitySelectors returns the selectors of the entities which are not expanded yet.
+	EntitySelectors() []*EntitySelector
+	// EntityIds returns the ids of the entities declared by id.
+	EntityIds() []string
+	// ExpandEntitySelectors declares an entity per SYNQ path matched by each selector, keyed by its String(),
+	// except for the tables declared by id, given the SYNQ paths their ids resolve to.
+	// Selectors missing from matched are not expanded.
+	ExpandEntitySelectors(matched map[string][]string, resolved map[string]string)
+}
+
+// String identifies the selector, like bq-prod.sales.* [bq_table].
+func (s EntitySelector) String() string {
+	selector := s.Match
+	if s.Regex != "" {
+		selector = "/" + s.Regex + "/"
+	}
+	if len(s.Types) > 0 {
+		selector += " [" + strings.Join(s.Types, ", ") + "]"
+	}
+	return selector
+}
+
+// Validate checks that the selector has a single valid pattern, and known entity types.
+func (s EntitySelector) Validate() error {
+	switch {
+	case s.Match == "" && s.Regex == "":
+		return errors.New("match or regex must be set")
+	case s.Match != "" && s.Regex != "":
+		return errors.New("match and regex cannot be both set")
+	}
+	if s.Regex != "" {
+		if _, err := regexp.Compile(s.Regex); err != nil {
+			return errors.Wrap(err, "invalid regex")
+		}
+	}
+	for _, name := range s.Types {
+		if _, ok := ParseEntityType(name); !ok {
+			return fmt.Errorf("unknown entity type: '%s'", name)
+		}
+	}
+	return nil
+}
+
+// ParseEntityType returns the entity type of a short name like bq_table.
+func ParseEntityType(name string) (entitiesv1.EntityType, bool) {
+	value, ok := entitiesv1.EntityType_value["ENTITY_TYPE_"+strings.ToUpper(strings.TrimSpace(name))]
+	return entitiesv1.EntityType(value), ok && value != 0
+}
@@ -1,6 +1,10 @@
 package core
 
 import (
+	"cmp"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 )
 
@@ -9,12 +13,24 @@ const (
 	Version_V1Beta2 = "v1beta2"
 
 	Version_DefaultParser    = Version_V1Beta1
-	Version_DefaultGenerator = Version_V1Beta1
+	Version_DefaultGenerator = Version_V1Beta2
+)
+
+// Reset policies decide whether updating a monitor resets what it learned.
+// auto resets on the changes which require it, never keeps the learned history, always resets on every update.
+const (
+	ResetPolicy_Auto   = "auto"
+	ResetPolicy_Never  = "never"
+	ResetPolicy_Always = "always"
 )
 
+var ResetPolicies = []string{ResetPolicy_Auto, ResetPolicy_Never, ResetPolicy_Always}
+
 type Config struct {
 	Version string `yaml:"version,omitempty"`
 	ID      string `yaml:"namespace,omitempty"`
+	// MovedFrom lists the namespaces the monitors were previously deployed with, so that they keep their history.
+	MovedFrom []string `yaml:"moved_from,omitempty"`
 }
 
 type MetadataProvider interface {
@@ -25,9 +41,65 @@ type MetadataProvider interface {
 type Parser interface {
 	MetadataProvider
 	ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, error)
+	ConvertToSqlTests() ([]*sqltestsv1.SqlTest, error)
+	// ConvertToMonitorMoves is only meaningful once ConvertToMonitorDefinitions succeeded.
+	ConvertToMonitorMoves() []*MonitorMove
+	// ConvertToProtectedMonitors returns the identities of the monitors declared with prevent_destroy.
+	ConvertToProtectedMonitors() []*pb.MonitorDefinition
+	// ConvertToResetPolicies returns the monitors whose reset policy is not auto.
+	ConvertToResetPolicies() []*MonitorResetPolicy
+}
+
+// MonitorResetPolicy is the reset policy of the monitor with the given identity.
+type MonitorResetPolicy struct {
+	Monitor *pb.MonitorDefinition
+	Policy  string
+}
+
+// MonitorMove lists the identities a monitor was previously deployed with.
+// Identities only set the fields a monitor UUID is generated from: its id, namespace and monitored entity.
+type MonitorMove struct {
+	Monitor  *pb.MonitorDefinition
+	Previous []*pb.MonitorDefinition
+}
+
+func MonitorIdentity(id, configId, monitoredPath string) *pb.MonitorDefinition {
+	return &pb.MonitorDefinition{
+		Id:       id,
+		ConfigId: configId,
+		MonitoredId: &entitiesv1.Identifier{
+			Id: &entitiesv1.Identifier_SynqPath{
+				SynqPath: &entitiesv1.SynqPathIdentifier{
+					Path: monitoredPath,
+				},
+			},
+		},
+	}
 }
 
 type Generator interface {
 	MetadataProvider
 	GenerateYAML() ([]byte, error)
 }
+
+// NewMonitorMove returns the move of a monitor from the identities declared on it and the namespaces its file
+// was moved from, or nil when it was not moved. Fields left empty in the identities are those of the monitor.
+func NewMonitorMove(monitor *pb.MonitorDefinition, movedFrom []*pb.MonitorDefinition, namespacesMovedFrom []string) *MonitorMove {
+	if len(movedFrom)+len(namespacesMovedFrom) == 0 {
+		return nil
+	}
+
+	path := monitor.GetMonitoredId().GetSynqPath().GetPath()
+	move := &MonitorMove{Monitor: MonitorIdentity(monitor.Id, monitor.ConfigId, path)}
+	for _, identity := range movedFrom {
+		move.Previous = append(move.Previous, MonitorIdentity(
+			cmp.Or(identity.Id, monitor.Id),
+			cmp.Or(identity.ConfigId, monitor.ConfigId),
+			cmp.Or(identity.GetMonitoredId().GetSynqPath().GetPath(), path),
+		))
+	}
+	for _, namespace := range namespacesMovedFrom {
+		move.Previous = append(move.Previous, MonitorIdentity(monitor.Id, namespace, path))
+	}
+	return move
+}
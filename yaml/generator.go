@@ -3,6 +3,7 @@ package yaml
 import (
 	"fmt"
 
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/yaml/core"
 	"github.com/getsynq/monitors_mgmt/yaml/v1beta1"
@@ -14,12 +15,17 @@ type VersionedGenerator struct {
 	core.Generator
 }
 
-var generatorConstructors = map[string]func(string, []*pb.MonitorDefinition) core.Generator{
+var generatorConstructors = map[string]func(string, []*pb.MonitorDefinition, []*sqltestsv1.SqlTest) core.Generator{
 	core.Version_V1Beta1: v1beta1.NewYAMLGenerator,
 	core.Version_V1Beta2: v1beta2.NewYAMLGenerator,
 }
 
-func NewVersionedGenerator(version string, configId string, monitors []*pb.MonitorDefinition) (*VersionedGenerator, error) {
+func NewVersionedGenerator(
+	version string,
+	configId string,
+	monitors []*pb.MonitorDefinition,
+	sqlTests []*sqltestsv1.SqlTest,
+) (*VersionedGenerator, error) {
 	if version == "" {
 		version = core.Version_DefaultGenerator
 	}
@@ -29,7 +35,7 @@ func NewVersionedGenerator(version string, configId string, monitors []*pb.Monit
 		return nil, fmt.Errorf("version %s not supported, supported versions: %v", version, lo.Keys(generatorConstructors))
 	}
 
-	generator := constructor(configId, monitors)
+	generator := constructor(configId, monitors, sqlTests)
 
 	return &VersionedGenerator{
 		Generator: generator,
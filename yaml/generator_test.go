@@ -8,9 +8,12 @@ import (
 	"runtime"
 	"testing"
 
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/uuid"
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
 )
 
 type YAMLGeneratorSuite struct {
@@ -59,9 +62,15 @@ func (s *YAMLGeneratorSuite) TestExamples() {
 			protoMonitors[i] = sanitize(protoMonitors[i], uuidGenerator)
 		}
 
+		sqlTests, err := yamlParser.ConvertToSqlTests()
+		s.Require().NoError(err)
+		for i := range sqlTests {
+			sqlTests[i] = sanitizeSqlTest(sqlTests[i], uuidGenerator)
+		}
+
 		configID := yamlParser.GetConfigID()
 		version := yamlParser.GetVersion()
-		generator, err := NewVersionedGenerator(version, configID, protoMonitors)
+		generator, err := NewVersionedGenerator(version, configID, protoMonitors, sqlTests)
 		s.Require().NoError(err)
 
 		yamlBytes, err := generator.GenerateYAML()
@@ -72,5 +81,18 @@ func (s *YAMLGeneratorSuite) TestExamples() {
 			s.T(),
 			string(yamlBytes),
 		)
+
+		// The generated YAML must convert back to the same definitions
+		generatedParser, err := NewVersionedParserForVersion(version, yamlBytes)
+		s.Require().NoError(err)
+		generatedMonitors, err := generatedParser.ConvertToMonitorDefinitions()
+		s.Require().NoError(err)
+		s.Require().Len(generatedMonitors, len(protoMonitors))
+		monitorsById := lo.SliceToMap(protoMonitors, func(monitor *pb.MonitorDefinition) (string, *pb.MonitorDefinition) {
+			return monitor.Id, monitor
+		})
+		for _, generated := range generatedMonitors {
+			s.Truef(proto.Equal(monitorsById[generated.Id], generated), "monitor %s of %s changed after generation", generated.Id, file)
+		}
 	}
 }
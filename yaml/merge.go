@@ -0,0 +1,560 @@
+package yaml
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/uuid"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/samber/lo"
+	goyaml "go.yaml.in/yaml/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+type MergeStrategy string
+
+const (
+	// MergeStrategy_Report keeps the values of the file and reports the monitors deployed differently.
+	MergeStrategy_Report MergeStrategy = "report"
+	// MergeStrategy_Update rewrites the fields of the file which differ from the deployed monitors.
+	MergeStrategy_Update MergeStrategy = "update"
+)
+
+var MergeStrategies = []MergeStrategy{MergeStrategy_Report, MergeStrategy_Update}
+
+type MergeOptions struct {
+	Workspace string
+	Strategy  MergeStrategy
+	// ResolvedPaths maps the entity paths written in the file to their SYNQ path.
+	ResolvedPaths map[string]string
+	// SimplePaths maps the SYNQ paths of the remote monitors to the path written for entities the file is missing.
+	SimplePaths map[string]string
+}
+
+// MergeConflict is a monitor of the file whose deployed definition differs and was not updated.
+type MergeConflict struct {
+	Id     string
+	Entity string
+	Fields []string
+	Reason string
+}
+
+type Merge struct {
+	Namespace string
+	Content   []byte
+	Added     int
+	Updated   int
+	Unchanged int
+	Conflicts []MergeConflict
+}
+
+func (m *Merge) HasChanges() bool {
+	return m.Added > 0 || m.Updated > 0
+}
+
+// MergeMonitors merges remote monitors into an existing YAML config, editing its nodes so that comments,
+// ordering and anchors are kept. Remote monitors missing from the file are added, and those defined
+// differently are updated or reported, depending on the strategy. Monitors of the file which are not
+// in the remote monitors are left as they are.
+//
+// Monitors are matched on the UUID the file generates for them, the merged file is converted again
+// and must produce every added and updated monitor as deployed. Monitors failing that check are reported instead.
+func MergeMonitors(content []byte, remote []*pb.MonitorDefinition, options MergeOptions) (*Merge, error) {
+	parser, err := NewVersionedParser(content)
+	if err != nil {
+		return nil, err
+	}
+	local, err := parser.ConvertToMonitorDefinitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert existing monitors: %w", err)
+	}
+
+	merger := &merger{
+		version:       parser.GetVersion(),
+		namespace:     parser.GetConfigID(),
+		moves:         parser.ConvertToMonitorMoves(),
+		options:       options,
+		uuidGenerator: uuid.NewUUIDGenerator(options.Workspace),
+		style:         core.DetectStyle(content),
+	}
+
+	excluded := map[string]MergeConflict{}
+	for {
+		merge, failed, err := merger.merge(content, local, remote, excluded)
+		if err != nil {
+			return nil, err
+		}
+		if len(failed) == 0 {
+			return merge, nil
+		}
+
+		for id, conflict := range failed {
+			if _, ok := excluded[id]; ok {
+				return nil, fmt.Errorf("monitor '%s' on '%s' could not be merged", conflict.Id, conflict.Entity)
+			}
+			excluded[id] = conflict
+		}
+	}
+}
+
+type merger struct {
+	version       string
+	namespace     string
+	moves         []*core.MonitorMove
+	options       MergeOptions
+	uuidGenerator *uuid.UUIDGenerator
+	style         core.Style
+}
+
+// localMonitor is a monitor of the file, with its id and entity as written in the file.
+type localMonitor struct {
+	id         string
+	entity     string
+	definition *pb.MonitorDefinition
+}
+
+// rendered is a monitor as the generator of the file version writes it.
+type rendered struct {
+	entity           *goyaml.Node
+	monitor          *goyaml.Node
+	timePartitioning string
+}
+
+// Keys identifying a monitor and its entity, which are never merged.
+var mergeIdentityKeys = []string{"id", "monitored_id", "monitored_ids"}
+
+// merge runs a single merge pass, leaving the excluded monitors untouched. It returns the monitors which did not convert back
+// to their remote definition, to be excluded on the next pass.
+func (m *merger) merge(
+	content []byte,
+	local []*pb.MonitorDefinition,
+	remote []*pb.MonitorDefinition,
+	excluded map[string]MergeConflict,
+) (*Merge, map[string]MergeConflict, error) {
+	var document goyaml.Node
+	if err := goyaml.Unmarshal(content, &document); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if document.Kind != goyaml.DocumentNode || len(document.Content) == 0 {
+		return nil, nil, fmt.Errorf("empty YAML document")
+	}
+	root := document.Content[0]
+
+	localByUUID := m.index(local, m.options.ResolvedPaths)
+	merge := &Merge{Namespace: m.namespace}
+	touched := []*pb.MonitorDefinition{}
+	toAdd := []*pb.MonitorDefinition{}
+	for _, monitor := range remote {
+		id := m.uuidGenerator.GenerateMonitorUUID(monitor)
+		if conflict, ok := excluded[id]; ok {
+			merge.Conflicts = append(merge.Conflicts, conflict)
+			continue
+		}
+
+		existing, ok := localByUUID[id]
+		if !ok {
+			toAdd = append(toAdd, monitor)
+			continue
+		}
+
+		fields, err := m.diff(existing, monitor)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(fields) == 0 {
+			merge.Unchanged++
+			continue
+		}
+
+		conflict := MergeConflict{Id: existing.id, Entity: existing.entity, Fields: fields, Reason: "deployed differently"}
+		if m.options.Strategy != MergeStrategy_Update {
+			merge.Conflicts = append(merge.Conflicts, conflict)
+			continue
+		}
+		reason, err := m.update(root, existing, monitor, fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		if reason != "" {
+			conflict.Reason = reason
+			merge.Conflicts = append(merge.Conflicts, conflict)
+			continue
+		}
+		merge.Updated++
+		touched = append(touched, monitor)
+	}
+
+	if err := m.add(root, toAdd); err != nil {
+		return nil, nil, err
+	}
+	merge.Added = len(toAdd)
+	touched = append(touched, toAdd...)
+
+	merged, err := core.MarshalDocument(&document, m.style)
+	if err != nil {
+		return nil, nil, err
+	}
+	merge.Content = merged
+
+	failed, err := m.verify(merged, len(local)+len(toAdd), touched)
+	if err != nil {
+		return nil, nil, err
+	}
+	return merge, failed, nil
+}
+
+// index keys the monitors of a file by the UUID they are deployed with,
+// moved monitors also by the UUIDs of their previous identities.
+func (m *merger) index(monitors []*pb.MonitorDefinition, resolvedPaths map[string]string) map[string]*localMonitor {
+	uuidOf := func(monitor *pb.MonitorDefinition) string {
+		resolved := proto.Clone(monitor).(*pb.MonitorDefinition)
+		if path, ok := resolvedPaths[monitor.MonitoredId.GetSynqPath().GetPath()]; ok && path != "" {
+			resolved.MonitoredId = synqPathIdentifier(path)
+		}
+		return m.uuidGenerator.GenerateMonitorUUID(resolved)
+	}
+
+	byUUID := map[string]*localMonitor{}
+	for _, monitor := range monitors {
+		byUUID[uuidOf(monitor)] = &localMonitor{
+			id:         monitor.Id,
+			entity:     monitor.MonitoredId.GetSynqPath().GetPath(),
+			definition: monitor,
+		}
+	}
+	for _, move := range m.moves {
+		local, ok := byUUID[uuidOf(move.Monitor)]
+		if !ok {
+			continue
+		}
+		for _, previous := range move.Previous {
+			if _, ok := byUUID[uuidOf(previous)]; !ok {
+				byUUID[uuidOf(previous)] = local
+			}
+		}
+	}
+	return byUUID
+}
+
+// diff returns the keys of the monitor which differ from the remote monitor, as written by the generator.
+func (m *merger) diff(local *localMonitor, remote *pb.MonitorDefinition) ([]string, error) {
+	localRendered, err := m.render(m.asLocal(local.definition, local))
+	if err != nil {
+		return nil, err
+	}
+	remoteRendered, err := m.render(m.asLocal(remote, local))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := lo.Uniq(append(mappingKeys(localRendered.monitor), mappingKeys(remoteRendered.monitor)...))
+	fields := lo.Filter(keys, func(key string, _ int) bool {
+		return !slices.Contains(mergeIdentityKeys, key) &&
+			nodeString(core.MappingValue(localRendered.monitor, key)) != nodeString(core.MappingValue(remoteRendered.monitor, key))
+	})
+	if localRendered.timePartitioning != remoteRendered.timePartitioning {
+		fields = append(fields, "time_partitioning_column")
+	}
+	slices.Sort(fields)
+	return fields, nil
+}
+
+// update rewrites the given fields of a monitor of the file with their remote value,
+// or returns why the monitor cannot be updated in place.
+func (m *merger) update(root *goyaml.Node, local *localMonitor, remote *pb.MonitorDefinition, fields []string) (string, error) {
+	if m.version == core.Version_V1Beta2 && slices.Contains(fields, "time_partitioning_column") {
+		return "time partitioning is set per entity, move the monitor to an entity partitioned on the deployed column", nil
+	}
+
+	node := m.findMonitorNode(root, local)
+	if node == nil {
+		return "not found in the file", nil
+	}
+	if node.Anchor != "" {
+		return "shared through an anchor", nil
+	}
+	if monitoredIds := core.MappingValue(node, "monitored_ids"); monitoredIds != nil && len(monitoredIds.Content) > 1 {
+		return "shared by several entities through monitored_ids", nil
+	}
+	for _, field := range fields {
+		// Aliased values are resolved to their anchor.
+		if value := core.MappingValue(node, field); value != nil && value.Anchor != "" {
+			return fmt.Sprintf("%s is shared through an anchor", field), nil
+		}
+	}
+
+	remoteRendered, err := m.render(m.asLocal(remote, local))
+	if err != nil {
+		return "", err
+	}
+	for _, field := range fields {
+		setMappingValue(node, field, core.MappingValue(remoteRendered.monitor, field))
+	}
+	return "", nil
+}
+
+// add appends the remote monitors missing from the file, to the entity they are on in v1beta2.
+func (m *merger) add(root *goyaml.Node, monitors []*pb.MonitorDefinition) error {
+	for _, monitor := range monitors {
+		toAdd := proto.Clone(monitor).(*pb.MonitorDefinition)
+		toAdd.MonitoredId = synqPathIdentifier(m.entityPath(monitor.MonitoredId.GetSynqPath().GetPath()))
+		toRender, err := m.render(toAdd)
+		if err != nil {
+			return err
+		}
+
+		omitDefaults(root, toRender.monitor)
+		if m.version != core.Version_V1Beta2 {
+			appendToSequence(root, "monitors", toRender.monitor)
+			continue
+		}
+
+		entityPath := toAdd.MonitoredId.GetSynqPath().GetPath()
+		entity := m.findEntityNode(root, func(id string, timePartitioning string) bool {
+			return id == entityPath && timePartitioning == toRender.timePartitioning
+		})
+		if entity == nil {
+			timePartitioning := core.MappingValue(core.MappingValue(root, "defaults"), "time_partitioning")
+			if timePartitioning != nil && scalarValue(timePartitioning) == toRender.timePartitioning {
+				setMappingValue(toRender.entity, "time_partitioning_column", nil)
+			}
+			appendToSequence(root, "entities", toRender.entity)
+			continue
+		}
+		appendToSequence(entity, "monitors", toRender.monitor)
+	}
+	return nil
+}
+
+// verify converts the merged file again, it must keep every monitor and produce the touched ones as deployed.
+func (m *merger) verify(content []byte, expected int, touched []*pb.MonitorDefinition) (map[string]MergeConflict, error) {
+	parser, err := NewVersionedParserForVersion(m.version, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse merged YAML: %w", err)
+	}
+	monitors, err := parser.ConvertToMonitorDefinitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert merged YAML: %w", err)
+	}
+	if len(monitors) != expected {
+		return nil, fmt.Errorf("merged YAML contains %d monitors, %d were expected", len(monitors), expected)
+	}
+
+	resolvedPaths := map[string]string{}
+	for simple, path := range m.options.ResolvedPaths {
+		resolvedPaths[simple] = path
+	}
+	for path, simple := range m.options.SimplePaths {
+		if _, ok := resolvedPaths[simple]; !ok {
+			resolvedPaths[simple] = path
+		}
+	}
+	byUUID := m.index(monitors, resolvedPaths)
+
+	failed := map[string]MergeConflict{}
+	for _, monitor := range touched {
+		id := m.uuidGenerator.GenerateMonitorUUID(monitor)
+		merged, ok := byUUID[id]
+		if !ok {
+			failed[id] = MergeConflict{
+				Id:     monitor.Id,
+				Entity: m.entityPath(monitor.MonitoredId.GetSynqPath().GetPath()),
+				Reason: "would get a new ID once merged",
+			}
+			continue
+		}
+		fields, err := m.diff(merged, monitor)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			failed[id] = MergeConflict{Id: merged.id, Entity: merged.entity, Fields: fields, Reason: "would inherit different values from the file"}
+		}
+	}
+	return failed, nil
+}
+
+// asLocal copies a monitor with the id and entity path of a monitor of the file, so that only their definitions compare.
+func (m *merger) asLocal(monitor *pb.MonitorDefinition, local *localMonitor) *pb.MonitorDefinition {
+	copied := proto.Clone(monitor).(*pb.MonitorDefinition)
+	copied.Id = local.id
+	copied.MonitoredId = synqPathIdentifier(local.entity)
+	if monitor.Name == "" || monitor.Name == monitor.Id {
+		copied.Name = local.id
+	}
+	return copied
+}
+
+func (m *merger) render(monitor *pb.MonitorDefinition) (*rendered, error) {
+	generator, err := NewVersionedGenerator(m.version, m.namespace, []*pb.MonitorDefinition{monitor}, nil)
+	if err != nil {
+		return nil, err
+	}
+	content, err := generator.GenerateYAML()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate monitor '%s': %w", monitor.Id, err)
+	}
+	root, err := core.ParseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.version != core.Version_V1Beta2 {
+		return &rendered{monitor: firstItem(core.MappingValue(root, "monitors"))}, nil
+	}
+	entity := firstItem(core.MappingValue(root, "entities"))
+	return &rendered{
+		entity:           entity,
+		monitor:          firstItem(core.MappingValue(entity, "monitors")),
+		timePartitioning: scalarValue(core.MappingValue(entity, "time_partitioning_column")),
+	}, nil
+}
+
+// entityPath is the path a remote entity is written with, the one already used in the file when there is one.
+func (m *merger) entityPath(path string) string {
+	written := lo.Filter(lo.Keys(m.options.ResolvedPaths), func(simple string, _ int) bool {
+		return m.options.ResolvedPaths[simple] == path
+	})
+	if len(written) > 0 {
+		slices.Sort(written)
+		return written[0]
+	}
+	if simple, ok := m.options.SimplePaths[path]; ok && simple != "" {
+		return simple
+	}
+	return path
+}
+
+func (m *merger) findMonitorNode(root *goyaml.Node, local *localMonitor) *goyaml.Node {
+	if m.version != core.Version_V1Beta2 {
+		return core.SequenceItem(core.MappingValue(root, "monitors"), func(item *goyaml.Node) bool {
+			return scalarValue(core.MappingValue(item, "id")) == local.id
+		})
+	}
+
+	var monitor *goyaml.Node
+	for _, entity := range core.SequenceItems(core.MappingValue(root, "entities")) {
+		if scalarValue(core.MappingValue(entity, "id")) != local.entity {
+			continue
+		}
+		monitor = core.SequenceItem(core.MappingValue(entity, "monitors"), func(item *goyaml.Node) bool {
+			return scalarValue(core.MappingValue(item, "id")) == local.id
+		})
+		if monitor != nil {
+			break
+		}
+	}
+	return monitor
+}
+
+// findEntityNode returns the first entity matching on its id and time partitioning, the default one when it sets none.
+func (m *merger) findEntityNode(root *goyaml.Node, match func(id string, timePartitioning string) bool) *goyaml.Node {
+	defaultTimePartitioning := scalarValue(core.MappingValue(core.MappingValue(root, "defaults"), "time_partitioning"))
+	return core.SequenceItem(core.MappingValue(root, "entities"), func(entity *goyaml.Node) bool {
+		timePartitioning := scalarValue(core.MappingValue(entity, "time_partitioning_column"))
+		if timePartitioning == "" {
+			timePartitioning = defaultTimePartitioning
+		}
+		return match(strings.TrimSpace(scalarValue(core.MappingValue(entity, "id"))), timePartitioning)
+	})
+}
+
+// omitDefaults removes the values of an added monitor which the defaults of the file already set.
+func omitDefaults(root *goyaml.Node, monitor *goyaml.Node) {
+	defaults := core.MappingValue(root, "defaults")
+	for _, key := range mappingKeys(defaults) {
+		if value := core.MappingValue(monitor, key); value != nil && nodeString(value) == nodeString(core.MappingValue(defaults, key)) {
+			setMappingValue(monitor, key, nil)
+		}
+	}
+}
+
+func synqPathIdentifier(path string) *entitiesv1.Identifier {
+	return &entitiesv1.Identifier{
+		Id: &entitiesv1.Identifier_SynqPath{
+			SynqPath: &entitiesv1.SynqPathIdentifier{
+				Path: path,
+			},
+		},
+	}
+}
+
+// The helpers below complete the core ones, setMappingValue and appendToSequence edit the nodes as written.
+
+func mappingKeys(node *goyaml.Node) []string {
+	if node == nil || node.Kind != goyaml.MappingNode {
+		return nil
+	}
+	keys := []string{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+// setMappingValue replaces the value of a key, keeping its comments, appends the key when it is missing,
+// and removes it when value is nil.
+func setMappingValue(node *goyaml.Node, key string, value *goyaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			continue
+		}
+		if value == nil {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+		previous := node.Content[i+1]
+		value.HeadComment, value.LineComment, value.FootComment = previous.HeadComment, previous.LineComment, previous.FootComment
+		node.Content[i+1] = value
+		return
+	}
+	if value != nil {
+		node.Content = append(node.Content, &goyaml.Node{Kind: goyaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+}
+
+// appendToSequence appends an item to the sequence of a key, adding the key when it is missing.
+func appendToSequence(node *goyaml.Node, key string, item *goyaml.Node) {
+	sequence := core.MappingValue(node, key)
+	if sequence == nil || sequence.Kind != goyaml.SequenceNode {
+		sequence = &goyaml.Node{Kind: goyaml.SequenceNode, Tag: "!!seq"}
+		setMappingValue(node, key, sequence)
+	}
+	sequence.Content = append(sequence.Content, item)
+}
+
+func firstItem(node *goyaml.Node) *goyaml.Node {
+	if items := core.SequenceItems(node); len(items) > 0 {
+		return items[0]
+	}
+	return nil
+}
+
+func scalarValue(node *goyaml.Node) string {
+	for node != nil && node.Kind == goyaml.AliasNode {
+		node = node.Alias
+	}
+	if node == nil || node.Kind != goyaml.ScalarNode {
+		return ""
+	}
+	return strings.TrimSpace(node.Value)
+}
+
+// nodeString compares nodes on their YAML form, ignoring comments.
+func nodeString(node *goyaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return ""
+	}
+	content, err := goyaml.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
@@ -0,0 +1,316 @@
+package yaml
+
+import (
+	"testing"
+
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const mergeWorkspace = "workspace"
+
+type MergeSuite struct {
+	suite.Suite
+}
+
+func TestMergeSuite(t *testing.T) {
+	suite.Run(t, new(MergeSuite))
+}
+
+var mergeResolvedPaths = map[string]string{
+	"shop.public.orders":   "pg::shop::public::orders",
+	"shop.public.payments": "pg::shop::public::payments",
+	"shop.public.refunds":  "pg::shop::public::refunds",
+}
+
+const mergeLocal = `# Orders monitors
+version: v1beta2
+namespace: orders
+defaults:
+  severity: ERROR
+  schedule: daily
+entities:
+  - id: shop.public.orders # the orders table
+    time_partitioning_column: created_at
+    monitors:
+      - id: orders_volume
+        type: volume
+        mode: &sensitive
+          anomaly_engine:
+            sensitivity: PRECISE
+      - id: orders_freshness
+        type: freshness
+        expression: updated_at
+        mode: *sensitive
+        timezone: UTC # local timezone
+`
+
+// remoteMonitors converts a config as the API returns it, on SYNQ paths and with UUIDs.
+func (s *MergeSuite) remoteMonitors(content string) []*pb.MonitorDefinition {
+	parser, err := NewVersionedParser([]byte(content))
+	s.Require().NoError(err)
+	monitors, err := parser.ConvertToMonitorDefinitions()
+	s.Require().NoError(err)
+
+	uuidGenerator := uuid.NewUUIDGenerator(mergeWorkspace)
+	for _, monitor := range monitors {
+		monitor.MonitoredId = &entitiesv1.Identifier{
+			Id: &entitiesv1.Identifier_SynqPath{
+				SynqPath: &entitiesv1.SynqPathIdentifier{Path: mergeResolvedPaths[monitor.MonitoredId.GetSynqPath().GetPath()]},
+			},
+		}
+		monitor.Id = uuidGenerator.GenerateMonitorUUID(monitor)
+	}
+	return monitors
+}
+
+func (s *MergeSuite) merge(local string, remote []*pb.MonitorDefinition, strategy MergeStrategy) *Merge {
+	merge, err := MergeMonitors([]byte(local), remote, MergeOptions{
+		Workspace:     mergeWorkspace,
+		Strategy:      strategy,
+		ResolvedPaths: map[string]string{"shop.public.orders": "pg::shop::public::orders"},
+		SimplePaths:   map[string]string{"pg::shop::public::payments": "shop.public.payments"},
+	})
+	s.Require().NoError(err)
+	return merge
+}
+
+const mergeRemote = `version: v1beta2
+namespace: orders
+entities:
+  - id: shop.public.orders
+    time_partitioning_column: created_at
+    monitors:
+      - id: orders_volume
+        type: volume
+        mode:
+          anomaly_engine:
+            sensitivity: PRECISE
+      - id: orders_freshness
+        type: freshness
+        expression: updated_at
+        severity: WARNING
+        mode:
+          anomaly_engine:
+            sensitivity: PRECISE
+        timezone: Europe/Paris
+      - id: orders_amount
+        type: custom_numeric
+        metric_aggregation: sum(amount)
+  - id: shop.public.payments
+    monitors:
+      - id: payments_volume
+        type: volume
+`
+
+func (s *MergeSuite) TestReportsConflicts() {
+	merge := s.merge(mergeLocal, s.remoteMonitors(mergeRemote), MergeStrategy_Report)
+
+	s.Equal(2, merge.Added)
+	s.Equal(0, merge.Updated)
+	s.Equal(1, merge.Unchanged)
+	s.Equal([]MergeConflict{
+		{Id: "orders_freshness", Entity: "shop.public.orders", Fields: []string{"severity", "timezone"}, Reason: "deployed differently"},
+	}, merge.Conflicts)
+	s.Equal(`# Orders monitors
+version: v1beta2
+namespace: orders
+defaults:
+  severity: ERROR
+  schedule: daily
+entities:
+  - id: shop.public.orders # the orders table
+    time_partitioning_column: created_at
+    monitors:
+      - id: orders_volume
+        type: volume
+        mode: &sensitive
+          anomaly_engine:
+            sensitivity: PRECISE
+      - id: orders_freshness
+        type: freshness
+        expression: updated_at
+        mode: *sensitive
+        timezone: UTC # local timezone
+      - id: 377c2823-fecf-5f00-b09b-b09961e69c2a
+        type: custom_numeric
+        name: orders_amount
+        mode:
+          anomaly_engine:
+            sensitivity: BALANCED
+        metric_aggregation: sum(amount)
+  - id: shop.public.payments
+    monitors:
+      - id: f24aab06-d415-5266-95cb-6b2f757bc021
+        type: volume
+        name: payments_volume
+        mode:
+          anomaly_engine:
+            sensitivity: BALANCED
+`, string(merge.Content))
+}
+
+func (s *MergeSuite) TestUpdatesFields() {
+	merge := s.merge(mergeLocal, s.remoteMonitors(mergeRemote), MergeStrategy_Update)
+
+	s.Equal(2, merge.Added)
+	s.Equal(1, merge.Updated)
+	s.Equal(1, merge.Unchanged)
+	s.Empty(merge.Conflicts)
+	s.Contains(string(merge.Content), `      - id: orders_freshness
+        type: freshness
+        expression: updated_at
+        mode: *sensitive
+        timezone: Europe/Paris # local timezone
+        severity: WARNING
+`)
+}
+
+func (s *MergeSuite) TestReportsAnchoredFields() {
+	merge := s.merge(mergeLocal, s.remoteMonitors(`version: v1beta2
+namespace: orders
+entities:
+  - id: shop.public.orders
+    time_partitioning_column: created_at
+    monitors:
+      - id: orders_volume
+        type: volume
+        mode:
+          anomaly_engine:
+            sensitivity: RELAXED
+`), MergeStrategy_Update)
+
+	s.False(merge.HasChanges())
+	s.Equal([]MergeConflict{
+		{Id: "orders_volume", Entity: "shop.public.orders", Fields: []string{"mode"}, Reason: "mode is shared through an anchor"},
+	}, merge.Conflicts)
+}
+
+func (s *MergeSuite) TestReportsAnchoredMonitors() {
+	local := `version: v1beta2
+namespace: orders
+entities:
+  - id: shop.public.payments
+    monitors:
+      - &volume
+        id: volume
+        type: volume
+  - id: shop.public.orders
+    monitors:
+      - *volume
+`
+	merge := s.merge(local, s.remoteMonitors(`version: v1beta2
+namespace: orders
+entities:
+  - id: shop.public.orders
+    monitors:
+      - id: volume
+        type: volume
+        severity: WARNING
+`), MergeStrategy_Update)
+
+	s.False(merge.HasChanges())
+	s.Equal([]MergeConflict{
+		{Id: "volume", Entity: "shop.public.orders", Fields: []string{"severity"}, Reason: "shared through an anchor"},
+	}, merge.Conflicts)
+}
+
+func (s *MergeSuite) TestReportsMonitorsInheritingOtherValues() {
+	local := `version: v1beta2
+namespace: orders
+defaults:
+  timezone: UTC
+entities:
+  - id: shop.public.orders
+    monitors:
+      - id: orders_volume
+        type: volume
+`
+	merge := s.merge(local, s.remoteMonitors(`version: v1beta2
+namespace: orders
+entities:
+  - id: shop.public.orders
+    monitors:
+      - id: orders_volume
+        type: volume
+      - id: orders_rows
+        type: volume
+        timezone: UTC
+  - id: shop.public.refunds
+    monitors:
+      - id: refunds_volume
+        type: volume
+`), MergeStrategy_Update)
+
+	s.Equal(1, merge.Added)
+	s.Len(merge.Conflicts, 2)
+	s.Equal("shop.public.orders", merge.Conflicts[0].Entity)
+	s.Equal([]string{"timezone"}, merge.Conflicts[0].Fields)
+	s.Equal("pg::shop::public::refunds", merge.Conflicts[1].Entity)
+	s.Equal("would inherit different values from the file", merge.Conflicts[1].Reason)
+}
+
+func (s *MergeSuite) TestV1Beta1() {
+	local := `version: v1beta1
+namespace: orders
+monitors:
+  # Volume of all tables
+  - id: volume
+    type: volume
+    time_partitioning: created_at
+    monitored_ids:
+      - shop.public.orders
+      - shop.public.payments
+`
+	merge, err := MergeMonitors([]byte(local), s.remoteMonitors(`version: v1beta1
+namespace: orders
+monitors:
+  - id: volume
+    type: volume
+    severity: WARNING
+    time_partitioning: created_at
+    monitored_id: shop.public.orders
+  - id: freshness
+    type: freshness
+    expression: updated_at
+    time_partitioning: created_at
+    monitored_id: shop.public.orders
+`), MergeOptions{
+		Workspace: mergeWorkspace,
+		Strategy:  MergeStrategy_Update,
+		ResolvedPaths: map[string]string{
+			"shop.public.orders":   "pg::shop::public::orders",
+			"shop.public.payments": "pg::shop::public::payments",
+		},
+	})
+	s.Require().NoError(err)
+
+	s.Equal(1, merge.Added)
+	s.Equal([]MergeConflict{
+		{Id: "volume", Entity: "shop.public.orders", Fields: []string{"severity"}, Reason: "shared by several entities through monitored_ids"},
+	}, merge.Conflicts)
+	s.Equal(`version: v1beta1
+namespace: orders
+monitors:
+  # Volume of all tables
+  - id: volume
+    type: volume
+    time_partitioning: created_at
+    monitored_ids:
+      - shop.public.orders
+      - shop.public.payments
+  - id: 8ab90684-e357-5870-983e-dc94460b7e40
+    name: freshness
+    type: freshness
+    expression: updated_at
+    monitored_id: shop.public.orders
+    severity: ERROR
+    time_partitioning: created_at
+    mode:
+      anomaly_engine:
+        sensitivity: BALANCED
+    daily: {}
+`, string(merge.Content))
+}
@@ -0,0 +1,253 @@
+package yaml
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
+	"github.com/getsynq/monitors_mgmt/uuid"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/getsynq/monitors_mgmt/yaml/v1beta1"
+	"github.com/getsynq/monitors_mgmt/yaml/v1beta2"
+	"github.com/samber/lo"
+	goyaml "go.yaml.in/yaml/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// The workspace only seeds the compared UUIDs, equal inputs generate equal UUIDs in every workspace.
+const migrationWorkspace = "migration"
+
+type Migration struct {
+	Namespace string
+	Entities  int
+	Monitors  int
+	Content   []byte
+}
+
+// MigrateToV1Beta2 rewrites a v1beta1 config as v1beta2 with the v1beta2 generator, grouping its monitors by entity
+// and lifting the values they have in common to the defaults.
+//
+// The migrated config is parsed again and must convert to exactly the same monitor definitions,
+// with the same generated UUIDs, so that deploying it never deletes and recreates a monitor.
+func MigrateToV1Beta2(content []byte) (*Migration, error) {
+	var versionCheck core.Config
+	if err := goyaml.Unmarshal(content, &versionCheck); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if versionCheck.Version != "" && versionCheck.Version != core.Version_V1Beta1 {
+		return nil, fmt.Errorf("only %s can be migrated, found version %s", core.Version_V1Beta1, versionCheck.Version)
+	}
+
+	parser, err := v1beta1.NewYAMLParserFromBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	monitors, err := parser.ConvertToMonitorDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	generator := v1beta2.NewYAMLGenerator(parser.GetConfigID(), monitors, nil).(*v1beta2.YAMLGenerator)
+	config, err := generator.GenerateConfig()
+	if err != nil {
+		return nil, err
+	}
+	migrateLifecycle(parser.(*v1beta1.YAMLParser).GetYAMLConfig(), config)
+
+	migrated, err := goyaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	migratedParser, err := v1beta2.NewYAMLParserFromBytes(migrated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse migrated YAML: %w", err)
+	}
+	migratedMonitors, err := migratedParser.ConvertToMonitorDefinitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert migrated YAML: %w", err)
+	}
+	if err := compareMonitors(monitors, migratedMonitors); err != nil {
+		return nil, err
+	}
+	if err := compareMoves(parser.ConvertToMonitorMoves(), migratedParser.ConvertToMonitorMoves()); err != nil {
+		return nil, err
+	}
+	if err := compareProtected(parser.ConvertToProtectedMonitors(), migratedParser.ConvertToProtectedMonitors()); err != nil {
+		return nil, err
+	}
+	if err := compareResetPolicies(parser.ConvertToResetPolicies(), migratedParser.ConvertToResetPolicies()); err != nil {
+		return nil, err
+	}
+
+	return &Migration{
+		Namespace: config.ID,
+		Entities:  len(lo.UniqBy(config.Entities, func(entity v1beta2.Entity) string { return entity.Id })),
+		Monitors:  len(migratedMonitors),
+		Content:   migrated,
+	}, nil
+}
+
+// migrateLifecycle carries the previous identities, prevent_destroy and reset policies over, the generator only
+// knows about the monitor definitions. Monitor ids are unique within a v1beta1 config, and kept by the generator.
+func migrateLifecycle(original *v1beta1.YAMLConfig, migrated *v1beta2.Config) {
+	migrated.MovedFrom = original.MovedFrom
+	if original.Defaults.ResetPolicy != "" {
+		if migrated.Defaults == nil {
+			migrated.Defaults = &v1beta2.Defaults{}
+		}
+		migrated.Defaults.ResetPolicy = original.Defaults.ResetPolicy
+	}
+
+	movedFrom := map[string][]v1beta2.MovedFrom{}
+	preventDestroy := map[string]bool{}
+	resetPolicy := map[string]string{}
+	for _, monitor := range original.Monitors {
+		for _, previous := range monitor.MovedFrom {
+			movedFrom[strings.TrimSpace(monitor.Id)] = append(movedFrom[strings.TrimSpace(monitor.Id)], v1beta2.MovedFrom{
+				ID:        previous.Id,
+				Entity:    previous.MonitoredID,
+				Namespace: previous.Namespace,
+			})
+		}
+		preventDestroy[strings.TrimSpace(monitor.Id)] = monitor.PreventDestroy
+		resetPolicy[strings.TrimSpace(monitor.Id)] = monitor.ResetPolicy
+	}
+
+	type lifecycle interface {
+		SetMonitorMovedFrom([]v1beta2.MovedFrom)
+		SetMonitorPreventDestroy(bool)
+		SetMonitorResetPolicy(string)
+	}
+	for _, entity := range migrated.Entities {
+		for _, wrapper := range entity.Monitors {
+			if monitor, ok := wrapper.Monitor.(lifecycle); ok {
+				monitor.SetMonitorMovedFrom(movedFrom[wrapper.Monitor.GetMonitorID()])
+				monitor.SetMonitorPreventDestroy(preventDestroy[wrapper.Monitor.GetMonitorID()])
+				monitor.SetMonitorResetPolicy(resetPolicy[wrapper.Monitor.GetMonitorID()])
+			}
+		}
+	}
+}
+
+func compareMoves(original, migrated []*core.MonitorMove) error {
+	uuidGenerator := uuid.NewUUIDGenerator(migrationWorkspace)
+	byUUID := func(moves []*core.MonitorMove) map[string][]string {
+		return lo.SliceToMap(moves, func(move *core.MonitorMove) (string, []string) {
+			return uuidGenerator.GenerateMonitorUUID(move.Monitor), lo.Map(move.Previous, func(previous *pb.MonitorDefinition, _ int) string {
+				return uuidGenerator.GenerateMonitorUUID(previous)
+			})
+		})
+	}
+	originalByUUID := byUUID(original)
+	migratedByUUID := byUUID(migrated)
+
+	problems := []string{}
+	for _, move := range original {
+		if id := uuidGenerator.GenerateMonitorUUID(move.Monitor); !slices.Equal(originalByUUID[id], migratedByUUID[id]) {
+			problems = append(problems, fmt.Sprintf(
+				"monitor '%s' on '%s' would not keep its moved_from", move.Monitor.Id, move.Monitor.MonitoredId.GetSynqPath().GetPath(),
+			))
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("migration would not preserve the moved monitors:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return nil
+}
+
+func compareProtected(original, migrated []*pb.MonitorDefinition) error {
+	uuidGenerator := uuid.NewUUIDGenerator(migrationWorkspace)
+	migratedUUIDs := lo.Map(migrated, func(monitor *pb.MonitorDefinition, _ int) string {
+		return uuidGenerator.GenerateMonitorUUID(monitor)
+	})
+
+	problems := []string{}
+	for _, monitor := range original {
+		if !slices.Contains(migratedUUIDs, uuidGenerator.GenerateMonitorUUID(monitor)) {
+			problems = append(problems, fmt.Sprintf(
+				"monitor '%s' on '%s' would not keep its prevent_destroy", monitor.Id, monitor.MonitoredId.GetSynqPath().GetPath(),
+			))
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("migration would not preserve the protected monitors:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return nil
+}
+
+func compareResetPolicies(original, migrated []*core.MonitorResetPolicy) error {
+	uuidGenerator := uuid.NewUUIDGenerator(migrationWorkspace)
+	migratedByUUID := lo.SliceToMap(migrated, func(policy *core.MonitorResetPolicy) (string, string) {
+		return uuidGenerator.GenerateMonitorUUID(policy.Monitor), policy.Policy
+	})
+
+	problems := []string{}
+	for _, policy := range original {
+		if migratedByUUID[uuidGenerator.GenerateMonitorUUID(policy.Monitor)] != policy.Policy {
+			problems = append(problems, fmt.Sprintf(
+				"monitor '%s' on '%s' would not keep its reset_policy %s", policy.Monitor.Id, policy.Monitor.MonitoredId.GetSynqPath().GetPath(), policy.Policy,
+			))
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("migration would not preserve the reset policies:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return nil
+}
+
+func compareMonitors(original, migrated []*pb.MonitorDefinition) error {
+	uuidGenerator := uuid.NewUUIDGenerator(migrationWorkspace)
+	byUUID := func(monitors []*pb.MonitorDefinition) map[string]*pb.MonitorDefinition {
+		return lo.SliceToMap(monitors, func(monitor *pb.MonitorDefinition) (string, *pb.MonitorDefinition) {
+			return uuidGenerator.GenerateMonitorUUID(monitor), monitor
+		})
+	}
+	originalByUUID := byUUID(original)
+	migratedByUUID := byUUID(migrated)
+
+	problems := []string{}
+	for id, monitor := range originalByUUID {
+		migratedMonitor, ok := migratedByUUID[id]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("monitor '%s' on '%s' would get a new ID", monitor.Id, monitor.MonitoredId.GetSynqPath().GetPath()))
+		} else if fields := changedFields(monitor, migratedMonitor); len(fields) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"monitor '%s' on '%s' would change %s", monitor.Id, monitor.MonitoredId.GetSynqPath().GetPath(), strings.Join(fields, ", "),
+			))
+		}
+	}
+	for id, monitor := range migratedByUUID {
+		if _, ok := originalByUUID[id]; !ok {
+			problems = append(problems, fmt.Sprintf("monitor '%s' on '%s' would be created", monitor.Id, monitor.MonitoredId.GetSynqPath().GetPath()))
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("migration would not preserve the monitors:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return nil
+}
+
+func changedFields(original, migrated proto.Message) []string {
+	originalReflect := original.ProtoReflect()
+	migratedReflect := migrated.ProtoReflect()
+
+	changed := []string{}
+	fields := originalReflect.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if !originalReflect.Get(field).Equal(migratedReflect.Get(field)) {
+			changed = append(changed, field.TextName())
+		}
+	}
+	return changed
+}
@@ -0,0 +1,71 @@
+package yaml
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/suite"
+)
+
+type MigrateSuite struct {
+	suite.Suite
+}
+
+func TestMigrateSuite(t *testing.T) {
+	suite.Run(t, new(MigrateSuite))
+}
+
+func (s *MigrateSuite) TestExamples() {
+	_, thisfile, _, ok := runtime.Caller(0)
+	s.Require().True(ok)
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(thisfile), "../examples/v1beta1/*.yaml"))
+	s.Require().NoError(err)
+	s.Require().NotEmpty(files)
+
+	for _, file := range files {
+		s.Run(filepath.Base(file), func() {
+			content, err := os.ReadFile(file)
+			s.Require().NoError(err)
+
+			migration, err := MigrateToV1Beta2(content)
+			s.Require().NoError(err)
+
+			snaps.WithConfig(snaps.Filename(filepath.Join("migrations", filepath.Base(file)))).MatchSnapshot(
+				s.T(),
+				string(migration.Content),
+			)
+		})
+	}
+}
+
+func (s *MigrateSuite) TestRejectsOtherVersions() {
+	_, err := MigrateToV1Beta2([]byte("version: v1beta2\nentities: []\n"))
+	s.ErrorContains(err, "only v1beta1 can be migrated")
+}
+
+func (s *MigrateSuite) TestDetectsChangedMonitors() {
+	original, err := NewVersionedParser([]byte(`version: v1beta1
+monitors:
+  - id: volume
+    type: volume
+    time_partitioning: created_at
+    monitored_id: db.schema.table
+`))
+	s.Require().NoError(err)
+	monitors, err := original.ConvertToMonitorDefinitions()
+	s.Require().NoError(err)
+
+	changed, err := original.ConvertToMonitorDefinitions()
+	s.Require().NoError(err)
+	changed[0].Timezone = "UTC"
+
+	s.EqualError(compareMonitors(monitors, changed), "migration would not preserve the monitors:\n  - monitor 'volume' on 'db.schema.table' would change timezone")
+
+	changed[0].Id = "renamed"
+	s.EqualError(compareMonitors(monitors, changed), "migration would not preserve the monitors:\n"+
+		"  - monitor 'renamed' on 'db.schema.table' would be created\n"+
+		"  - monitor 'volume' on 'db.schema.table' would get a new ID")
+}
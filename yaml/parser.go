@@ -32,6 +32,11 @@ func NewVersionedParser(yamlContent []byte) (*VersionedParser, error) {
 		version = core.Version_DefaultParser
 	}
 
+	return NewVersionedParserForVersion(version, yamlContent)
+}
+
+// NewVersionedParserForVersion parses the content with the parser of the given version, whatever version it declares.
+func NewVersionedParserForVersion(version string, yamlContent []byte) (*VersionedParser, error) {
 	constructor, ok := parserConstructors[version]
 	if !ok {
 		return nil, fmt.Errorf("version %s is not supported, supported versions: %s", version, lo.Keys(parserConstructors))
@@ -46,3 +51,27 @@ func NewVersionedParser(yamlContent []byte) (*VersionedParser, error) {
 		Parser: parser,
 	}, nil
 }
+
+// EntitySelectors returns the selectors of the entities declared by selector, none for the versions without selectors.
+func (p *VersionedParser) EntitySelectors() []*core.EntitySelector {
+	if selectorParser, ok := p.Parser.(core.SelectorParser); ok {
+		return selectorParser.EntitySelectors()
+	}
+	return nil
+}
+
+// EntityIds returns the ids of the entities declared by id, none for the versions without selectors.
+func (p *VersionedParser) EntityIds() []string {
+	if selectorParser, ok := p.Parser.(core.SelectorParser); ok {
+		return selectorParser.EntityIds()
+	}
+	return nil
+}
+
+// ExpandEntitySelectors declares an entity per SYNQ path matched by each selector, keyed by its String(),
+// except for the tables declared by id, given the SYNQ paths their ids resolve to.
+func (p *VersionedParser) ExpandEntitySelectors(matched map[string][]string, resolved map[string]string) {
+	if selectorParser, ok := p.Parser.(core.SelectorParser); ok {
+		selectorParser.ExpandEntitySelectors(matched, resolved)
+	}
+}
@@ -8,11 +8,14 @@ import (
 	"runtime"
 	"testing"
 
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/paths"
 	"github.com/getsynq/monitors_mgmt/uuid"
+	"github.com/getsynq/monitors_mgmt/yaml/core"
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -157,6 +160,21 @@ func (s *YAMLParserSuite) TestExamples() {
 					monitorJson,
 				)
 			}
+
+			sqlTests, err := yamlParser.ConvertToSqlTests()
+			s.Require().NoError(err)
+
+			for _, sqlTest := range sqlTests {
+				sqlTest = sanitizeSqlTest(sqlTest, s.uuidGenerator)
+				sqlTestJson, err := protojson.Marshal(sqlTest)
+				s.Require().NoError(err)
+
+				snapFileName := filepath.Join("examples", filepath.Base(file.path))
+				snaps.WithConfig(snaps.Filename(snapFileName)).MatchJSON(
+					s.T(),
+					sqlTestJson,
+				)
+			}
 		}
 	}
 }
@@ -172,3 +190,276 @@ func sanitize(monitor *pb.MonitorDefinition, uuidGenerator *uuid.UUIDGenerator)
 	monitor.Id = uuidGenerator.GenerateMonitorUUID(monitor)
 	return monitor
 }
+
+func sanitizeSqlTest(sqlTest *sqltestsv1.SqlTest, uuidGenerator *uuid.UUIDGenerator) *sqltestsv1.SqlTest {
+	sqlTest.MonitoredId = &entitiesv1.Identifier{
+		Id: &entitiesv1.Identifier_SynqPath{
+			SynqPath: &entitiesv1.SynqPathIdentifier{
+				Path: paths.PathWithColons(sqlTest.MonitoredId.GetSynqPath().GetPath()),
+			},
+		},
+	}
+	sqlTest.Id = uuidGenerator.GenerateSqlTestUUID(sqlTest)
+	return sqlTest
+}
+
+func (s *YAMLParserSuite) TestMonitorMoves() {
+	_, thisfile, _, ok := runtime.Caller(0)
+	s.Require().True(ok)
+
+	identity := func(monitor *pb.MonitorDefinition) string {
+		return fmt.Sprintf("%s/%s/%s", monitor.Id, monitor.ConfigId, monitor.MonitoredId.GetSynqPath().GetPath())
+	}
+	expected := map[string][]string{
+		"runs_volume_daily/team-Y/ch-prod.default.runs": {
+			"runs_volume/team-Y/ch-prod.default.runs",
+			"runs_volume_daily/team-X/ch-prod.default.runs",
+		},
+		"runs_freshness/team-Y/ch-prod.default.runs": {
+			"runs_freshness/team-Z/ch-prod::default::runs_v1",
+			"runs_freshness/team-X/ch-prod.default.runs",
+		},
+	}
+
+	for _, version := range []string{"v1beta1", "v1beta2"} {
+		s.Run(version, func() {
+			yamlContent, err := os.ReadFile(filepath.Join(filepath.Dir(thisfile), "../examples", version, "moved_from.yaml"))
+			s.Require().NoError(err)
+			yamlParser, err := NewVersionedParser(yamlContent)
+			s.Require().NoError(err)
+			_, err = yamlParser.ConvertToMonitorDefinitions()
+			s.Require().NoError(err)
+
+			moves := map[string][]string{}
+			for _, move := range yamlParser.ConvertToMonitorMoves() {
+				for _, previous := range move.Previous {
+					moves[identity(move.Monitor)] = append(moves[identity(move.Monitor)], identity(previous))
+				}
+			}
+			s.Equal(expected, moves)
+		})
+	}
+
+	s.Run("invalid", func() {
+		yamlParser, err := NewVersionedParser([]byte(`version: v1beta2
+namespace: team-Y
+moved_from:
+  - team-Y
+entities:
+  - id: ch-prod.default.runs
+    time_partitioning_column: created_at
+    monitors:
+      - id: runs_volume_daily
+        type: volume
+        moved_from:
+          - {}
+`))
+		s.Require().NoError(err)
+		_, err = yamlParser.ConvertToMonitorDefinitions()
+		s.Require().Error(err)
+		s.Contains(err.Error(), "moved_from - invalid previous namespace: 'team-Y'")
+		s.Contains(err.Error(), "moved_from - id, entity or namespace must be set")
+	})
+}
+
+func (s *YAMLParserSuite) TestProtectedMonitors() {
+	_, thisfile, _, ok := runtime.Caller(0)
+	s.Require().True(ok)
+
+	for _, version := range []string{"v1beta1", "v1beta2"} {
+		s.Run(version, func() {
+			yamlContent, err := os.ReadFile(filepath.Join(filepath.Dir(thisfile), "../examples", version, "prevent_destroy.yaml"))
+			s.Require().NoError(err)
+			yamlParser, err := NewVersionedParser(yamlContent)
+			s.Require().NoError(err)
+			_, err = yamlParser.ConvertToMonitorDefinitions()
+			s.Require().NoError(err)
+
+			s.Equal(
+				[]*pb.MonitorDefinition{core.MonitorIdentity("invoices_volume", "billing", "pg-prod.billing.invoices")},
+				yamlParser.ConvertToProtectedMonitors(),
+			)
+		})
+	}
+}
+
+func (s *YAMLParserSuite) TestResetPolicies() {
+	_, thisfile, _, ok := runtime.Caller(0)
+	s.Require().True(ok)
+
+	for _, version := range []string{"v1beta1", "v1beta2"} {
+		s.Run(version, func() {
+			yamlContent, err := os.ReadFile(filepath.Join(filepath.Dir(thisfile), "../examples", version, "reset_policy.yaml"))
+			s.Require().NoError(err)
+			yamlParser, err := NewVersionedParser(yamlContent)
+			s.Require().NoError(err)
+			_, err = yamlParser.ConvertToMonitorDefinitions()
+			s.Require().NoError(err)
+
+			s.Equal(
+				[]*core.MonitorResetPolicy{
+					{Monitor: core.MonitorIdentity("orders_volume", "orders", "pg-prod.public.orders"), Policy: core.ResetPolicy_Never},
+					{Monitor: core.MonitorIdentity("orders_freshness", "orders", "pg-prod.public.orders"), Policy: core.ResetPolicy_Always},
+				},
+				yamlParser.ConvertToResetPolicies(),
+			)
+		})
+	}
+
+	s.Run("invalid", func() {
+		yamlParser, err := NewVersionedParser([]byte(`version: v1beta2
+namespace: orders
+entities:
+  - id: pg-prod.public.orders
+    monitors:
+      - id: orders_volume
+        type: volume
+        reset_policy: sometimes
+`))
+		s.Require().NoError(err)
+		_, err = yamlParser.ConvertToMonitorDefinitions()
+		s.Require().Error(err)
+		s.Contains(err.Error(), "invalid reset policy: 'sometimes', must be one of auto, never, always")
+	})
+}
+
+func (s *YAMLParserSuite) TestEntitySelectors() {
+	content := []byte(`version: v1beta2
+namespace: sales
+entities:
+  - selector:
+      match: bq-prod.sales.*
+      types: [bq_table]
+    time_partitioning_column: created_at
+    monitors:
+      - id: volume
+        type: volume
+        prevent_destroy: true
+    tests:
+      - type: not_null
+        columns: [id]
+  - id: bq-prod.sales.orders
+    monitors:
+      - id: orders_freshness
+        type: freshness
+        expression: ordered_at
+`)
+	selector := core.EntitySelector{Match: "bq-prod.sales.*", Types: []string{"bq_table"}}
+
+	yamlParser, err := NewVersionedParser(content)
+	s.Require().NoError(err)
+	s.Equal([]*core.EntitySelector{&selector}, yamlParser.EntitySelectors())
+
+	// Until it is expanded, the monitors and tests of a selector are checked but not converted.
+	monitors, err := yamlParser.ConvertToMonitorDefinitions()
+	s.Require().NoError(err)
+	s.Equal([]string{"orders_freshness"}, lo.Map(monitors, func(monitor *pb.MonitorDefinition, _ int) string { return monitor.Id }))
+	sqlTests, err := yamlParser.ConvertToSqlTests()
+	s.Require().NoError(err)
+	s.Empty(sqlTests)
+	s.Empty(yamlParser.ConvertToProtectedMonitors())
+
+	yamlParser.ExpandEntitySelectors(map[string][]string{
+		selector.String(): {"bq-prod::sales::customers", "bq-prod::sales::orders", "bq-prod::sales::payments"},
+	}, nil)
+	s.Empty(yamlParser.EntitySelectors())
+
+	monitors, err = yamlParser.ConvertToMonitorDefinitions()
+	s.Require().NoError(err)
+	identities := lo.Map(monitors, func(monitor *pb.MonitorDefinition, _ int) string {
+		return monitor.Id + "/" + monitor.MonitoredId.GetSynqPath().GetPath() + "/" + monitor.GetTimePartitioning().GetExpression()
+	})
+	// The table declared by id keeps its own monitors.
+	s.Equal([]string{
+		"volume/bq-prod::sales::customers/created_at",
+		"volume/bq-prod::sales::payments/created_at",
+		"orders_freshness/bq-prod.sales.orders/",
+	}, identities)
+
+	sqlTests, err = yamlParser.ConvertToSqlTests()
+	s.Require().NoError(err)
+	s.Len(sqlTests, 2)
+	s.Equal(
+		[]*pb.MonitorDefinition{
+			core.MonitorIdentity("volume", "sales", "bq-prod::sales::customers"),
+			core.MonitorIdentity("volume", "sales", "bq-prod::sales::payments"),
+		},
+		yamlParser.ConvertToProtectedMonitors(),
+	)
+
+	// A table keeps the UUIDs of its monitors whatever the other tables the selector matches.
+	reparsed, err := NewVersionedParser(content)
+	s.Require().NoError(err)
+	reparsed.ExpandEntitySelectors(map[string][]string{selector.String(): {"bq-prod::sales::payments"}}, nil)
+	reparsedMonitors, err := reparsed.ConvertToMonitorDefinitions()
+	s.Require().NoError(err)
+	s.Equal(s.uuidGenerator.GenerateMonitorUUID(monitors[1]), s.uuidGenerator.GenerateMonitorUUID(reparsedMonitors[0]))
+
+	s.Run("declared_by_coordinate", func() {
+		yamlParser, err := NewVersionedParser([]byte(`version: v1beta2
+namespace: sales
+entities:
+  - selector:
+      match: sales.*
+    monitors:
+      - id: volume
+        type: volume
+  - id: sales.orders
+    time_partitioning_column: created_at
+    monitors:
+      - id: volume
+        type: volume
+`))
+		s.Require().NoError(err)
+		s.Equal([]string{"sales.orders"}, yamlParser.EntityIds())
+
+		yamlParser.ExpandEntitySelectors(
+			map[string][]string{"sales.*": {"bq-prod::sales::orders", "bq-prod::sales::payments"}},
+			map[string]string{"sales.orders": "bq-prod::sales::orders"},
+		)
+		monitors, err := yamlParser.ConvertToMonitorDefinitions()
+		s.Require().NoError(err)
+		s.Equal([]string{"volume/bq-prod::sales::payments", "volume/sales.orders"}, lo.Map(monitors, func(monitor *pb.MonitorDefinition, _ int) string {
+			return monitor.Id + "/" + monitor.MonitoredId.GetSynqPath().GetPath()
+		}))
+	})
+
+	s.Run("invalid", func() {
+		yamlParser, err := NewVersionedParser([]byte(`version: v1beta2
+namespace: sales
+entities:
+  - id: bq-prod.sales.orders
+    selector:
+      match: bq-prod.sales.*
+    monitors:
+      - id: volume
+        type: volume
+  - selector:
+      regex: "bq-prod::sales::("
+    monitors:
+      - id: volume
+        type: volume
+  - selector:
+      match: bq-prod.sales.*
+      types: [spreadsheet]
+    monitors:
+      - id: volume
+        type: volume
+  - selector:
+      match: bq-prod.billing.*
+    monitors:
+      - id: freshness
+        type: freshness
+        moved_from:
+          - entity: bq-prod.billing.invoices
+`))
+		s.Require().NoError(err)
+		_, err = yamlParser.ConvertToMonitorDefinitions()
+		s.Require().Error(err)
+		s.Contains(err.Error(), "Entity 'bq-prod.sales.orders': selector - id and selector cannot be both set")
+		s.Contains(err.Error(), "selector - invalid regex")
+		s.Contains(err.Error(), "selector - unknown entity type: 'spreadsheet'")
+		s.Contains(err.Error(), "Entity 'bq-prod.billing.*', Monitor 'freshness': expression - expression is required for freshness monitors")
+		s.Contains(err.Error(), "Entity 'bq-prod.billing.*', Monitor 'freshness': moved_from - entity cannot be set on the monitors of a selector")
+	})
+}
@@ -0,0 +1,108 @@
+package yaml
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/getsynq/monitors_mgmt/yaml/core"
+	"github.com/samber/lo"
+	goyaml "go.yaml.in/yaml/v3"
+)
+
+// Keys whose values are monitored paths, in every version. The ids of v1beta2 entities are paths too.
+var monitoredPathKeys = []string{"monitored_id", "monitored_ids", "entity"}
+
+// RewritePaths replaces the monitored paths written in a config by other paths, for example an ambiguous path
+// by the SYNQ path it was disambiguated to. It returns the rewritten content and the number of replaced values.
+//
+// Only the bytes of the replaced values are edited, so that everything else in the file is kept exactly as it was,
+// comments, quoting and indentation included. The rewritten content must still parse and convert.
+func RewritePaths(content []byte, replacements map[string]string) ([]byte, int, error) {
+	root, err := core.ParseDocument(content)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	nodes := []*goyaml.Node{}
+	collect := func(node *goyaml.Node) {
+		if node != nil && node.Kind == goyaml.ScalarNode {
+			if _, ok := replacements[node.Value]; ok {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	walkMappings(root, func(mapping *goyaml.Node) {
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			key, value := mapping.Content[i].Value, mapping.Content[i+1]
+			if !slices.Contains(monitoredPathKeys, key) {
+				continue
+			}
+			collect(value)
+			for _, item := range core.SequenceItems(value) {
+				collect(item)
+			}
+		}
+	})
+	for _, entity := range core.SequenceItems(core.MappingValue(root, "entities")) {
+		collect(core.MappingValue(entity, "id"))
+	}
+	// An anchored entity id is found once per alias.
+	nodes = lo.Uniq(nodes)
+	if len(nodes) == 0 {
+		return content, 0, nil
+	}
+
+	// Values are replaced from the end of each line, so that the columns of the others still match.
+	slices.SortFunc(nodes, func(a, b *goyaml.Node) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return b.Column - a.Column
+	})
+	lines := strings.SplitAfter(string(content), "\n")
+	for _, node := range nodes {
+		line, source := []rune(lines[node.Line-1]), scalarSource(node)
+		start, end := node.Column-1, node.Column-1+len([]rune(source))
+		if end > len(line) || string(line[start:end]) != source {
+			return nil, 0, fmt.Errorf("cannot rewrite '%s' at line %d, column %d", node.Value, node.Line, node.Column)
+		}
+		node.Value = replacements[node.Value]
+		lines[node.Line-1] = string(line[:start]) + scalarSource(node) + string(line[end:])
+	}
+
+	rewritten := []byte(strings.Join(lines, ""))
+	parser, err := NewVersionedParser(rewritten)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse rewritten YAML: %w", err)
+	}
+	if _, err := parser.ConvertToMonitorDefinitions(); err != nil {
+		return nil, 0, fmt.Errorf("failed to convert rewritten YAML: %w", err)
+	}
+	return rewritten, len(nodes), nil
+}
+
+// scalarSource returns how a single line scalar is written in the source, in its style.
+func scalarSource(node *goyaml.Node) string {
+	switch node.Style {
+	case goyaml.SingleQuotedStyle:
+		return "'" + strings.ReplaceAll(node.Value, "'", "''") + "'"
+	case goyaml.DoubleQuotedStyle:
+		return `"` + strings.ReplaceAll(strings.ReplaceAll(node.Value, `\`, `\\`), `"`, `\"`) + `"`
+	default:
+		return node.Value
+	}
+}
+
+// walkMappings calls visit on every mapping below node, aliases excluded since their anchor is visited.
+func walkMappings(node *goyaml.Node, visit func(mapping *goyaml.Node)) {
+	if node == nil || node.Kind == goyaml.AliasNode {
+		return
+	}
+	if node.Kind == goyaml.MappingNode {
+		visit(node)
+	}
+	for _, child := range node.Content {
+		walkMappings(child, visit)
+	}
+}
@@ -0,0 +1,95 @@
+package yaml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewritePaths(t *testing.T) {
+	replacements := map[string]string{
+		"public.orders": "pg-prod::public::orders",
+		"public.runs":   "ch-prod::public::runs",
+	}
+
+	t.Run("v1beta2", func(t *testing.T) {
+		content := `version: v1beta2
+namespace: shop
+entities:
+  - id: public.orders # ambiguous between two integrations
+    monitors:
+      - id: orders_volume   # kept as it is
+        type: volume
+  - id: "public.runs"
+    monitors:
+      - id: runs_volume
+        type: volume
+        moved_from:
+          - entity: 'public.orders'
+  - id: public.payments
+    monitors:
+      - id: public.orders
+        type: volume
+`
+		rewritten, count, err := RewritePaths([]byte(content), replacements)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, `version: v1beta2
+namespace: shop
+entities:
+  - id: pg-prod::public::orders # ambiguous between two integrations
+    monitors:
+      - id: orders_volume   # kept as it is
+        type: volume
+  - id: "ch-prod::public::runs"
+    monitors:
+      - id: runs_volume
+        type: volume
+        moved_from:
+          - entity: 'pg-prod::public::orders'
+  - id: public.payments
+    monitors:
+      - id: public.orders
+        type: volume
+`, string(rewritten))
+	})
+
+	t.Run("v1beta1", func(t *testing.T) {
+		content := `namespace: shop
+monitors:
+  - id: volume
+    type: volume
+    time_partitioning: created_at
+    monitored_ids: [public.orders, public.payments, public.runs]
+  - id: freshness
+    type: freshness
+    expression: created_at
+    time_partitioning: created_at
+    monitored_id: public.orders
+`
+		rewritten, count, err := RewritePaths([]byte(content), replacements)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, `namespace: shop
+monitors:
+  - id: volume
+    type: volume
+    time_partitioning: created_at
+    monitored_ids: [pg-prod::public::orders, public.payments, ch-prod::public::runs]
+  - id: freshness
+    type: freshness
+    expression: created_at
+    time_partitioning: created_at
+    monitored_id: pg-prod::public::orders
+`, string(rewritten))
+	})
+
+	t.Run("nothing_to_rewrite", func(t *testing.T) {
+		content := "version: v1beta2\nnamespace: shop\nentities:\n  - id: public.payments\n"
+		rewritten, count, err := RewritePaths([]byte(content), replacements)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Equal(t, content, string(rewritten))
+	})
+}
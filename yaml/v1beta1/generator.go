@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/yaml/core"
 	"github.com/samber/lo"
@@ -14,6 +15,7 @@ import (
 type YAMLGenerator struct {
 	configId string
 	monitors []*pb.MonitorDefinition
+	sqlTests []*sqltestsv1.SqlTest
 }
 
 func (p *YAMLGenerator) GetConfigID() string {
@@ -24,10 +26,11 @@ func (p *YAMLGenerator) GetVersion() string {
 	return core.Version_V1Beta1
 }
 
-func NewYAMLGenerator(configId string, monitors []*pb.MonitorDefinition) core.Generator {
+func NewYAMLGenerator(configId string, monitors []*pb.MonitorDefinition, sqlTests []*sqltestsv1.SqlTest) core.Generator {
 	return &YAMLGenerator{
 		configId: configId,
 		monitors: monitors,
+		sqlTests: sqlTests,
 	}
 }
 
@@ -49,6 +52,14 @@ func (p *YAMLGenerator) GenerateYAML() ([]byte, error) {
 		config.Monitors = append(config.Monitors, monitor)
 	}
 
+	for _, sqlTest := range p.sqlTests {
+		errors = append(errors, ConversionError{
+			Field:   "tests",
+			Message: fmt.Sprintf("SQL tests are not supported in %s, use %s", core.Version_V1Beta1, core.Version_V1Beta2),
+			Monitor: sqlTest.Name,
+		})
+	}
+
 	if len(errors) > 0 {
 		return nil, errors
 	}
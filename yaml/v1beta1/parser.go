@@ -1,19 +1,24 @@
 package v1beta1
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/yaml/core"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	goyaml "go.yaml.in/yaml/v3"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type YAMLParser struct {
 	yamlConfig *YAMLConfig
+	root       *goyaml.Node
 }
 
 func (p *YAMLParser) GetConfigID() string {
@@ -62,7 +67,15 @@ func NewYAMLParserFromBytes(bytes []byte) (core.Parser, error) {
 		return nil, errors.Wrap(err, "failed to parse YAML")
 	}
 
-	return NewYAMLParser(config), nil
+	root, err := core.ParseDocument(bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse YAML")
+	}
+
+	return &YAMLParser{
+		yamlConfig: config,
+		root:       root,
+	}, nil
 }
 
 func (p *YAMLParser) GetYAMLConfig() *YAMLConfig {
@@ -74,6 +87,9 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 	var protoMonitors []*pb.MonitorDefinition
 	existingMonitorIds := make(map[string]bool)
 
+	errors = append(errors, validateNamespacesMovedFrom(p.yamlConfig)...)
+	errors = append(errors, validateResetPolicy(p.yamlConfig.Defaults.ResetPolicy, "")...)
+
 	for _, yamlMonitor := range p.yamlConfig.Monitors {
 		id := strings.TrimSpace(yamlMonitor.Id)
 		if id == "" {
@@ -110,13 +126,10 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 		if scheduleErrors := validateScheduleConfiguration(&yamlMonitor); len(scheduleErrors) > 0 {
 			errors = append(errors, scheduleErrors...)
 		}
+		errors = append(errors, validateMovedFrom(&yamlMonitor)...)
+		errors = append(errors, validateResetPolicy(yamlMonitor.ResetPolicy, id)...)
 
-		monitoredIds := yamlMonitor.MonitoredIDs
-		if len(yamlMonitor.MonitoredID) > 0 {
-			monitoredIds = append(monitoredIds, yamlMonitor.MonitoredID)
-		}
-
-		for _, monitoredID := range monitoredIds {
+		for _, monitoredID := range monitoredIDsOf(&yamlMonitor) {
 			protoMonitor, convErrors := convertSingleMonitor(&yamlMonitor, p.yamlConfig, monitoredID)
 			if len(convErrors) > 0 {
 				errors = append(errors, convErrors...)
@@ -127,12 +140,119 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 	}
 
 	if len(errors) > 0 {
-		return protoMonitors, errors
+		return protoMonitors, p.locate(errors)
 	}
 
 	return protoMonitors, nil
 }
 
+// ConvertToMonitorMoves returns the previous identities of the monitors declared with moved_from,
+// or moved with their namespace.
+func (p *YAMLParser) ConvertToMonitorMoves() []*core.MonitorMove {
+	moves := []*core.MonitorMove{}
+	for _, yamlMonitor := range p.yamlConfig.Monitors {
+		movedFrom := lo.Map(yamlMonitor.MovedFrom, func(movedFrom YAMLMovedFrom, _ int) *pb.MonitorDefinition {
+			return core.MonitorIdentity(strings.TrimSpace(movedFrom.Id), movedFrom.Namespace, movedFrom.MonitoredID)
+		})
+
+		configID := yamlMonitor.ConfigID
+		if configID == "" {
+			configID = p.yamlConfig.ID
+		}
+		for _, monitoredID := range monitoredIDsOf(&yamlMonitor) {
+			monitor := core.MonitorIdentity(strings.TrimSpace(yamlMonitor.Id), configID, monitoredID)
+			if move := core.NewMonitorMove(monitor, movedFrom, p.yamlConfig.MovedFrom); move != nil {
+				moves = append(moves, move)
+			}
+		}
+	}
+	return moves
+}
+
+// ConvertToProtectedMonitors returns the identities of the monitors declared with prevent_destroy,
+// one per monitored entity.
+func (p *YAMLParser) ConvertToProtectedMonitors() []*pb.MonitorDefinition {
+	protected := []*pb.MonitorDefinition{}
+	for _, yamlMonitor := range p.yamlConfig.Monitors {
+		if !yamlMonitor.PreventDestroy {
+			continue
+		}
+
+		configID := yamlMonitor.ConfigID
+		if configID == "" {
+			configID = p.yamlConfig.ID
+		}
+		for _, monitoredID := range monitoredIDsOf(&yamlMonitor) {
+			protected = append(protected, core.MonitorIdentity(strings.TrimSpace(yamlMonitor.Id), configID, monitoredID))
+		}
+	}
+	return protected
+}
+
+// ConvertToResetPolicies returns the monitors whose reset policy, or the default one, is not auto.
+func (p *YAMLParser) ConvertToResetPolicies() []*core.MonitorResetPolicy {
+	policies := []*core.MonitorResetPolicy{}
+	for _, yamlMonitor := range p.yamlConfig.Monitors {
+		policy := cmp.Or(yamlMonitor.ResetPolicy, p.yamlConfig.Defaults.ResetPolicy, core.ResetPolicy_Auto)
+		if policy == core.ResetPolicy_Auto {
+			continue
+		}
+
+		configID := cmp.Or(yamlMonitor.ConfigID, p.yamlConfig.ID)
+		for _, monitoredID := range monitoredIDsOf(&yamlMonitor) {
+			policies = append(policies, &core.MonitorResetPolicy{
+				Monitor: core.MonitorIdentity(strings.TrimSpace(yamlMonitor.Id), configID, monitoredID),
+				Policy:  policy,
+			})
+		}
+	}
+	return policies
+}
+
+// A monitor is converted once per monitored entity.
+func monitoredIDsOf(yamlMonitor *YAMLMonitor) []string {
+	monitoredIds := slices.Clone(yamlMonitor.MonitoredIDs)
+	if len(yamlMonitor.MonitoredID) > 0 {
+		monitoredIds = append(monitoredIds, yamlMonitor.MonitoredID)
+	}
+	return monitoredIds
+}
+
+// locate sets the position of every error from the YAML source, when the parser was created from bytes.
+func (p *YAMLParser) locate(errors ConversionErrors) ConversionErrors {
+	if p.root == nil {
+		return errors
+	}
+
+	monitorsNode := core.MappingValue(p.root, "monitors")
+	for i := range errors {
+		node := p.root
+		if errors[i].Monitor != "" {
+			if monitorNode := findMonitorNode(monitorsNode, errors[i].Monitor); monitorNode != nil {
+				node = monitorNode
+			}
+		}
+		errors[i].Position = core.FieldPosition(node, errors[i].Field)
+	}
+	return errors
+}
+
+// Errors refer to monitors either by their id or by their name.
+func findMonitorNode(monitorsNode *goyaml.Node, monitor string) *goyaml.Node {
+	if node := core.SequenceItemWithID(monitorsNode, monitor); node != nil {
+		return node
+	}
+	return core.SequenceItem(monitorsNode, func(item *goyaml.Node) bool {
+		name := core.MappingValue(item, "name")
+		return name != nil && name.Value == monitor
+	})
+}
+
+// SQL tests are only supported from v1beta2 onwards.
+func (p *YAMLParser) ConvertToSqlTests() ([]*sqltestsv1.SqlTest, error) {
+	return nil, nil
+}
+
 func convertSingleMonitor(
 	yamlMonitor *YAMLMonitor,
 	config *YAMLConfig,
@@ -426,6 +546,55 @@ func validateScheduleConfiguration(monitor *YAMLMonitor) ConversionErrors {
 	return errors
 }
 
+func validateMovedFrom(monitor *YAMLMonitor) ConversionErrors {
+	var errors ConversionErrors
+
+	for _, movedFrom := range monitor.MovedFrom {
+		if strings.TrimSpace(movedFrom.Id) == "" && movedFrom.MonitoredID == "" && movedFrom.Namespace == "" {
+			errors = append(errors, ConversionError{
+				Field:   "moved_from",
+				Message: "id, monitored_id or namespace must be set",
+				Monitor: monitor.Id,
+			})
+		}
+		if movedFrom.MonitoredID != "" && len(monitoredIDsOf(monitor)) > 1 {
+			errors = append(errors, ConversionError{
+				Field:   "moved_from",
+				Message: "monitored_id cannot be set when the monitor has multiple monitored_ids",
+				Monitor: monitor.Id,
+			})
+		}
+	}
+
+	return errors
+}
+
+func validateResetPolicy(policy string, monitorId string) ConversionErrors {
+	if policy == "" || slices.Contains(core.ResetPolicies, policy) {
+		return nil
+	}
+	return ConversionErrors{{
+		Field:   "reset_policy",
+		Message: fmt.Sprintf("invalid reset policy: '%s', must be one of %s", policy, strings.Join(core.ResetPolicies, ", ")),
+		Monitor: monitorId,
+	}}
+}
+
+func validateNamespacesMovedFrom(config *YAMLConfig) ConversionErrors {
+	var errors ConversionErrors
+
+	for _, namespace := range config.MovedFrom {
+		if namespace == "" || namespace == config.ID {
+			errors = append(errors, ConversionError{
+				Field:   "moved_from",
+				Message: fmt.Sprintf("invalid previous namespace: '%s'", namespace),
+			})
+		}
+	}
+
+	return errors
+}
+
 func convertDailySchedule(daily *YAMLSchedule) *pb.MonitorDefinition_Daily {
 	schedule := &pb.ScheduleDaily{
 		DelayNumDays: daily.IgnoreLast,
@@ -18,6 +18,7 @@ type YAMLConfig struct {
 		Hourly           *YAMLSchedule `yaml:"hourly,omitempty"`
 		Mode             *YAMLMode     `yaml:"mode,omitempty"`
 		Timezone         string        `yaml:"timezone,omitempty"`
+		ResetPolicy      string        `yaml:"reset_policy,omitempty"`
 	} `yaml:"defaults,omitempty"`
 	Monitors []YAMLMonitor `yaml:"monitors"`
 }
@@ -39,9 +40,19 @@ type YAMLMonitor struct {
 	Daily             *YAMLSchedule     `yaml:"daily,omitempty"`
 	Hourly            *YAMLSchedule     `yaml:"hourly,omitempty"`
 	Timezone          string            `yaml:"timezone,omitempty"`
+	MovedFrom         []YAMLMovedFrom   `yaml:"moved_from,omitempty"`
+	PreventDestroy    bool              `yaml:"prevent_destroy,omitempty"`
+	ResetPolicy       string            `yaml:"reset_policy,omitempty"`
 	ConfigID          string            `yaml:"-"`
 }
 
+// YAMLMovedFrom is an identity the monitor was previously deployed with, unset fields are those of the monitor.
+type YAMLMovedFrom struct {
+	Id          string `yaml:"id,omitempty"`
+	MonitoredID string `yaml:"monitored_id,omitempty"`
+	Namespace   string `yaml:"namespace,omitempty"`
+}
+
 type YAMLSegmentation struct {
 	Expression    string    `yaml:"expression"`
 	IncludeValues *[]string `yaml:"include_values,omitempty"`
@@ -69,9 +80,14 @@ type YAMLSchedule struct {
 }
 
 type ConversionError struct {
-	Field   string
-	Message string
-	Monitor string
+	Field    string
+	Message  string
+	Monitor  string
+	Position core.Position
+}
+
+func (e ConversionError) GetPosition() core.Position {
+	return e.Position
 }
 
 func (e ConversionError) Error() string {
@@ -83,6 +99,14 @@ func (e ConversionError) Error() string {
 
 type ConversionErrors []ConversionError
 
+func (e ConversionErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i := range e {
+		errs[i] = e[i]
+	}
+	return errs
+}
+
 func (e ConversionErrors) Error() string {
 	if len(e) == 0 {
 		return ""
@@ -0,0 +1,127 @@
+package v1beta2
+
+import (
+	"github.com/samber/lo"
+	"go.yaml.in/yaml/v3"
+)
+
+// LiftDefaults moves the most common severity, mode, schedule and timezone of the monitors,
+// and the most common time partitioning of the entities, to the defaults of a config which has none.
+// Monitors and entities using a lifted value omit it, so that the config converts to the same definitions.
+//
+// A value is only lifted when it is shared by at least two monitors or entities, and when all of them
+// set one explicitly, as an omitted value would otherwise start inheriting the default.
+func (c *Config) LiftDefaults() {
+	if c.Defaults != nil {
+		return
+	}
+
+	monitors := []*BaseMonitor{}
+	for _, entity := range c.Entities {
+		for _, wrapper := range entity.Monitors {
+			if base := baseMonitorOf(wrapper.Monitor); base != nil {
+				monitors = append(monitors, base)
+			}
+		}
+	}
+
+	defaults := &Defaults{}
+	defaults.Severity = liftDefault(
+		monitors,
+		func(m *BaseMonitor) *string { return &m.Severity },
+		func(severity string) bool { return severity != "" },
+	)
+	defaults.Mode = liftDefault(
+		monitors,
+		func(m *BaseMonitor) **Mode { return &m.Mode },
+		func(mode *Mode) bool { return mode != nil },
+	)
+	defaults.Schedule = liftDefault(
+		monitors,
+		func(m *BaseMonitor) **Schedule { return &m.Schedule },
+		func(schedule *Schedule) bool { return schedule != nil },
+	)
+	defaults.Timezone = liftDefault(
+		monitors,
+		func(m *BaseMonitor) *string { return &m.Timezone },
+		func(timezone string) bool { return timezone != "" },
+	)
+	defaults.TimePartitioning = liftDefault(
+		lo.Map(c.Entities, func(_ Entity, i int) *Entity { return &c.Entities[i] }),
+		func(e *Entity) *string { return &e.TimePartitioningColumn },
+		func(column string) bool { return column != "" },
+	)
+
+	if *defaults != (Defaults{}) {
+		c.Defaults = defaults
+	}
+}
+
+// liftDefault returns the most common value of a field, the first seen on ties, and clears it where it is used.
+// The zero value is returned, and nothing cleared, when the value cannot be lifted.
+func liftDefault[I any, V any](items []I, field func(I) *V, isSet func(V) bool) V {
+	var zero V
+	counts := map[string]int{}
+	values := map[string]V{}
+	keys := []string{}
+	order := []string{}
+	for _, item := range items {
+		value := *field(item)
+		if !isSet(value) {
+			return zero
+		}
+
+		key, ok := defaultKey(value)
+		if !ok {
+			return zero
+		}
+		if _, seen := values[key]; !seen {
+			values[key] = value
+			order = append(order, key)
+		}
+		counts[key]++
+		keys = append(keys, key)
+	}
+
+	best := ""
+	for _, key := range order {
+		if counts[key] > counts[best] {
+			best = key
+		}
+	}
+	if counts[best] < 2 {
+		return zero
+	}
+
+	for i, item := range items {
+		if keys[i] == best {
+			*field(item) = zero
+		}
+	}
+	return values[best]
+}
+
+// Values are compared on their YAML form, which is what ends up in the file.
+// Values which cannot be marshalled are not lifted.
+func defaultKey(value any) (string, bool) {
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+func baseMonitorOf(monitor MonitorInline) *BaseMonitor {
+	switch t := monitor.(type) {
+	case *FreshnessMonitor:
+		return &t.BaseMonitor
+	case *VolumeMonitor:
+		return &t.BaseMonitor
+	case *CustomNumericMonitor:
+		return &t.BaseMonitor
+	case *FieldStatsMonitor:
+		return &t.BaseMonitor
+	default:
+		return nil
+	}
+}
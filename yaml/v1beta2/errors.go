@@ -3,16 +3,27 @@ package v1beta2
 import (
 	"fmt"
 	"strings"
+
+	"github.com/getsynq/monitors_mgmt/yaml/core"
 )
 
 type ConversionError struct {
-	Field   string
-	Message string
-	Monitor string
-	Entity  string
+	Field    string
+	Message  string
+	Monitor  string
+	Test     string
+	Entity   string
+	Position core.Position
+}
+
+func (e ConversionError) GetPosition() core.Position {
+	return e.Position
 }
 
 func (e ConversionError) Error() string {
+	if e.Entity != "" && e.Test != "" {
+		return fmt.Sprintf("Entity '%s', Test '%s': %s - %s", e.Entity, e.Test, e.Field, e.Message)
+	}
 	if e.Entity != "" && e.Monitor != "" {
 		return fmt.Sprintf("Entity '%s', Monitor '%s': %s - %s", e.Entity, e.Monitor, e.Field, e.Message)
 	}
@@ -34,6 +45,14 @@ func (e ConversionErrors) Coalesce() error {
 	return nil
 }
 
+func (e ConversionErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i := range e {
+		errs[i] = e[i]
+	}
+	return errs
+}
+
 func (e ConversionErrors) Error() string {
 	if len(e) == 0 {
 		return ""
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/yaml/core"
 	"github.com/samber/lo"
@@ -15,6 +16,7 @@ import (
 type YAMLGenerator struct {
 	configId string
 	monitors []*pb.MonitorDefinition
+	sqlTests []*sqltestsv1.SqlTest
 }
 
 func (p *YAMLGenerator) GetConfigID() string {
@@ -25,14 +27,24 @@ func (p *YAMLGenerator) GetVersion() string {
 	return core.Version_V1Beta2
 }
 
-func NewYAMLGenerator(configId string, monitors []*pb.MonitorDefinition) core.Generator {
+func NewYAMLGenerator(configId string, monitors []*pb.MonitorDefinition, sqlTests []*sqltestsv1.SqlTest) core.Generator {
 	return &YAMLGenerator{
 		configId: configId,
 		monitors: monitors,
+		sqlTests: sqlTests,
 	}
 }
 
 func (p *YAMLGenerator) GenerateYAML() ([]byte, error) {
+	config, err := p.GenerateConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(config)
+}
+
+func (p *YAMLGenerator) GenerateConfig() (*Config, error) {
 	var errors ConversionErrors
 
 	config := &Config{
@@ -42,48 +54,76 @@ func (p *YAMLGenerator) GenerateYAML() ([]byte, error) {
 		},
 	}
 
+	// Time partitioning is set per entity, monitors of the same entity partitioned differently
+	// are listed under separate entries of that entity. Tests join the first entry of their entity.
+	entities := []*Entity{}
+	entitiesByKey := make(map[string]*Entity)
 	entitiesByPath := make(map[string]*Entity)
+	entityFor := func(entityPath, timePartitioning string) *Entity {
+		key := entityPath + "\x00" + timePartitioning
+		if entity, exists := entitiesByKey[key]; exists {
+			return entity
+		}
+		entity := &Entity{
+			Id:                     entityPath,
+			TimePartitioningColumn: timePartitioning,
+		}
+		entities = append(entities, entity)
+		entitiesByKey[key] = entity
+		if _, exists := entitiesByPath[entityPath]; !exists {
+			entitiesByPath[entityPath] = entity
+		}
+		return entity
+	}
 
 	for _, protoMonitor := range p.monitors {
 		entityPath := protoMonitor.MonitoredId.GetSynqPath().GetPath()
+		entity := entityFor(entityPath, protoMonitor.GetTimePartitioning().GetExpression())
 
-		entity, exists := entitiesByPath[entityPath]
-		if !exists {
-			entity = &Entity{
-				Id: entityPath,
-			}
-			entitiesByPath[entityPath] = entity
+		monitor, convErrors := p.generateSingleMonitor(protoMonitor)
+		if convErrors.HasErrors() {
+			errors = append(errors, convErrors...)
+			continue
 		}
 
-		if protoMonitor.GetTimePartitioning() != nil {
-			timePartCol := protoMonitor.GetTimePartitioning().GetExpression()
-			if entity.TimePartitioningColumn == "" {
-				entity.TimePartitioningColumn = timePartCol
-			}
+		entity.Monitors = append(entity.Monitors, Monitor{Monitor: monitor})
+	}
+
+	for _, sqlTest := range p.sqlTests {
+		entityPath := sqlTest.MonitoredId.GetSynqPath().GetPath()
+
+		entity, exists := entitiesByPath[entityPath]
+		if !exists {
+			entity = entityFor(entityPath, "")
 		}
 
-		monitor, convErrors := p.generateSingleMonitor(protoMonitor)
+		test, convErrors := p.generateSingleTest(sqlTest)
 		if convErrors.HasErrors() {
 			errors = append(errors, convErrors...)
 			continue
 		}
 
-		entity.Monitors = append(entity.Monitors, Monitor{Monitor: monitor})
+		entity.Tests = append(entity.Tests, Test{Test: test})
 	}
 
-	for _, entity := range entitiesByPath {
+	for _, entity := range entities {
 		config.Entities = append(config.Entities, *entity)
 	}
 
 	sort.SliceStable(config.Entities, func(i, j int) bool {
-		return config.Entities[i].Id < config.Entities[j].Id
+		if config.Entities[i].Id != config.Entities[j].Id {
+			return config.Entities[i].Id < config.Entities[j].Id
+		}
+		return config.Entities[i].TimePartitioningColumn < config.Entities[j].TimePartitioningColumn
 	})
 
 	if len(errors) > 0 {
 		return nil, errors
 	}
 
-	return yaml.Marshal(config)
+	config.LiftDefaults()
+
+	return config, nil
 }
 
 func (p *YAMLGenerator) generateSingleMonitor(
@@ -93,9 +133,12 @@ func (p *YAMLGenerator) generateSingleMonitor(
 
 	base := BaseMonitor{
 		ID:          protoMonitor.Id,
-		Name:        protoMonitor.Name,
 		Description: protoMonitor.Description,
 	}
+	// The name defaults to the ID when parsed
+	if protoMonitor.Name != protoMonitor.Id {
+		base.Name = protoMonitor.Name
+	}
 
 	if protoMonitor.Mode != nil {
 		switch t := protoMonitor.Mode.(type) {
@@ -202,6 +245,126 @@ func (p *YAMLGenerator) generateSingleMonitor(
 	return monitor, errors
 }
 
+func (p *YAMLGenerator) generateSingleTest(
+	sqlTest *sqltestsv1.SqlTest,
+) (TestInline, ConversionErrors) {
+	var errors ConversionErrors
+
+	base := TestBase{
+		ID:          sqlTest.Id,
+		Description: sqlTest.Description,
+	}
+	if sqlTest.Name != sqlTest.Id {
+		base.Name = sqlTest.Name
+	}
+
+	switch sqlTest.RecurrenceRule {
+	case RecurrenceRule_Daily, "":
+		base.Schedule.Type = "daily"
+	case RecurrenceRule_Hourly:
+		base.Schedule.Type = "hourly"
+	default:
+		errors = append(errors, ConversionError{
+			Field:   "schedule",
+			Message: fmt.Sprintf("unsupported recurrence rule: %s", sqlTest.RecurrenceRule),
+			Test:    sqlTest.Name,
+		})
+	}
+
+	var test TestInline
+	switch t := sqlTest.Template.(type) {
+	case *sqltestsv1.SqlTest_NotNull:
+		base.Type = "not_null"
+		test = &NotNullTest{
+			TestBase:        base,
+			TestWithColumns: TestWithColumns{Columns: t.NotNull.ColumnNames},
+		}
+	case *sqltestsv1.SqlTest_Empty:
+		base.Type = "empty"
+		test = &EmptyTest{
+			TestBase:        base,
+			TestWithColumns: TestWithColumns{Columns: t.Empty.ColumnNames},
+		}
+	case *sqltestsv1.SqlTest_Unique:
+		base.Type = "unique"
+		test = &UniqueTest{
+			TestBase:        base,
+			TestWithColumns: TestWithColumns{Columns: t.Unique.ColumnNames},
+			TestWithTime: TestWithTime{
+				TimePartitionColumn: t.Unique.GetTimePartitionColumnName(),
+				TimeWindowSeconds:   t.Unique.GetTimeWindowSeconds(),
+			},
+		}
+	case *sqltestsv1.SqlTest_AcceptedValues:
+		base.Type = "accepted_values"
+		test = &AcceptedValuesTest{
+			TestBase: base,
+			Column:   t.AcceptedValues.ColumnName,
+			Values:   t.AcceptedValues.AcceptedValues,
+		}
+	case *sqltestsv1.SqlTest_RejectedValues:
+		base.Type = "rejected_values"
+		test = &RejectedValuesTest{
+			TestBase: base,
+			Column:   t.RejectedValues.ColumnName,
+			Values:   t.RejectedValues.RejectedValues,
+		}
+	case *sqltestsv1.SqlTest_MinMax:
+		base.Type = "min_max"
+		test = &MinMaxTest{
+			TestBase: base,
+			Column:   t.MinMax.ColumnName,
+			MinValue: t.MinMax.MinValue,
+			MaxValue: t.MinMax.MaxValue,
+		}
+	case *sqltestsv1.SqlTest_MinValue:
+		base.Type = "min_value"
+		test = &MinValueTest{
+			TestBase: base,
+			Column:   t.MinValue.ColumnName,
+			MinValue: t.MinValue.MinValue,
+		}
+	case *sqltestsv1.SqlTest_MaxValue:
+		base.Type = "max_value"
+		test = &MaxValueTest{
+			TestBase: base,
+			Column:   t.MaxValue.ColumnName,
+			MaxValue: t.MaxValue.MaxValue,
+		}
+	case *sqltestsv1.SqlTest_Freshness:
+		base.Type = "freshness"
+		test = &FreshnessTest{
+			TestBase: base,
+			TestWithTime: TestWithTime{
+				TimePartitionColumn: t.Freshness.TimePartitionColumnName,
+				TimeWindowSeconds:   t.Freshness.TimeWindowSeconds,
+			},
+		}
+	case *sqltestsv1.SqlTest_RelativeTime:
+		base.Type = "relative_time"
+		test = &RelativeTimeTest{
+			TestBase:       base,
+			Column:         t.RelativeTime.ColumnName,
+			RelativeColumn: t.RelativeTime.RelativeColumnName,
+		}
+	case *sqltestsv1.SqlTest_BusinessRule:
+		base.Type = "business_rule"
+		test = &BusinessRuleTest{
+			TestBase:      base,
+			SQLExpression: t.BusinessRule.SqlExpression,
+		}
+	default:
+		errors = append(errors, ConversionError{
+			Field:   "type",
+			Message: fmt.Sprintf("unsupported test type: %T", t),
+			Test:    sqlTest.Name,
+			Entity:  sqlTest.MonitoredId.String(),
+		})
+	}
+
+	return test, errors
+}
+
 func convertProtoToDailySchedule(daily *pb.ScheduleDaily) *Schedule {
 	schedule := &Schedule{}
 	schedule.Type = "daily"
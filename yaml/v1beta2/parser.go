@@ -1,19 +1,23 @@
 package v1beta2
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 
 	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
 	pb "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/monitors/custom_monitors/v1"
 	"github.com/getsynq/monitors_mgmt/yaml/core"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	goyaml "go.yaml.in/yaml/v3"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type YAMLParser struct {
 	yamlConfig *Config
+	root       *goyaml.Node
 }
 
 func NewYAMLParser(config *Config) core.Parser {
@@ -37,7 +41,15 @@ func NewYAMLParserFromBytes(bytes []byte) (core.Parser, error) {
 		return nil, errors.Wrap(err, "failed to parse YAML")
 	}
 
-	return NewYAMLParser(config), nil
+	root, err := core.ParseDocument(bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse YAML")
+	}
+
+	return &YAMLParser{
+		yamlConfig: config,
+		root:       root,
+	}, nil
 }
 
 func (p *YAMLParser) GetYAMLConfig() *Config {
@@ -48,13 +60,15 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 	var errors ConversionErrors
 	var monitors []*pb.MonitorDefinition
 
+	errors = append(errors, p.validateNamespacesMovedFrom()...)
+	if p.yamlConfig.Defaults != nil {
+		errors = append(errors, validateResetPolicy(p.yamlConfig.Defaults.ResetPolicy, "", "")...)
+	}
+
 	for _, entity := range p.yamlConfig.Entities {
-		entityId := strings.TrimSpace(entity.Id)
+		entityId, selected, errs := checkEntity(entity)
+		errors = append(errors, errs...)
 		if entityId == "" {
-			errors = append(errors, ConversionError{
-				Field:   "id",
-				Message: "must be set",
-			})
 			continue
 		}
 
@@ -74,7 +88,7 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 				if t.Expression == "" {
 					errors = append(
 						errors,
-						ConversionError{Field: "expression", Message: "expression is required for freshness monitors", Monitor: monitorID, Entity: entity.Id},
+						ConversionError{Field: "expression", Message: "expression is required for freshness monitors", Monitor: monitorID, Entity: entityId},
 					)
 				}
 				monitor.Monitor = &pb.MonitorDefinition_Freshness{Freshness: &pb.MonitorFreshness{Expression: t.Expression}}
@@ -84,7 +98,7 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 				if t.MetricAggregation == "" {
 					errors = append(
 						errors,
-						ConversionError{Field: "sql", Message: "sql is required for custom_numeric monitors", Monitor: monitorID, Entity: entity.Id},
+						ConversionError{Field: "sql", Message: "sql is required for custom_numeric monitors", Monitor: monitorID, Entity: entityId},
 					)
 				}
 				monitor.Monitor = &pb.MonitorDefinition_CustomNumeric{CustomNumeric: &pb.MonitorCustomNumeric{MetricAggregation: t.MetricAggregation}}
@@ -96,7 +110,7 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 							Field:   "columns",
 							Message: "columns are required for field_stats monitors",
 							Monitor: monitorID,
-							Entity:  entity.Id,
+							Entity:  entityId,
 						},
 					)
 				}
@@ -108,14 +122,14 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 						Field:   "type",
 						Message: fmt.Sprintf("unsupported monitor type: %v", t),
 						Monitor: monitorID,
-						Entity:  entity.Id,
+						Entity:  entityId,
 					},
 				)
 			}
 
 			p.applySeverity(monitor, yamlMonitor.GetMonitorSeverity())
 
-			err := p.applyMode(monitor, yamlMonitor.GetMonitorMode(), &entity)
+			err := p.applyMode(monitor, yamlMonitor.GetMonitorMode(), entityId)
 			if err.HasErrors() {
 				errors = append(errors, err...)
 			}
@@ -125,6 +139,8 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 			if err.HasErrors() {
 				errors = append(errors, err...)
 			}
+			errors = append(errors, validateMovedFrom(yamlMonitor, entityId, selected)...)
+			errors = append(errors, validateResetPolicy(yamlMonitor.GetMonitorResetPolicy(), monitorID, entityId)...)
 
 			if _, ok := existingMonitorIds[monitor.Id]; ok {
 				errors = append(errors, ConversionError{
@@ -135,12 +151,270 @@ func (p *YAMLParser) ConvertToMonitorDefinitions() ([]*pb.MonitorDefinition, err
 				})
 			} else {
 				existingMonitorIds[monitor.Id] = true
-				monitors = append(monitors, monitor)
+				// The monitors of a selector are only checked until it is expanded into its entities.
+				if entity.Selector == nil {
+					monitors = append(monitors, monitor)
+				}
+			}
+		}
+	}
+
+	return monitors, p.locate(lo.Uniq(errors)).Coalesce()
+}
+
+// checkEntity returns the id the errors of an entity are reported with, its selector when it is declared or expanded
+// from one, and what is wrong with how it is declared. The id is empty when its monitors and tests cannot be converted.
+// The errors of the entities expanded from a selector are reported once, on the selector.
+func checkEntity(entity Entity) (string, *core.EntitySelector, ConversionErrors) {
+	entityId := strings.TrimSpace(entity.Id)
+	switch {
+	case entityId != "" && entity.Selector != nil:
+		return "", nil, ConversionErrors{{Field: "selector", Message: "id and selector cannot be both set", Entity: entityId}}
+	case entity.Selector != nil:
+		if err := entity.Selector.Validate(); err != nil {
+			return "", nil, ConversionErrors{{Field: "selector", Message: err.Error(), Entity: entity.Selector.String()}}
+		}
+		return entity.Selector.String(), entity.Selector, nil
+	case entityId == "":
+		return "", nil, ConversionErrors{{Field: "id", Message: "must be set"}}
+	case entity.expandedFrom != nil:
+		return entity.expandedFrom.String(), entity.expandedFrom, nil
+	default:
+		return entityId, nil, nil
+	}
+}
+
+// EntitySelectors returns the selectors of the entities declared by selector, which are not expanded yet.
+func (p *YAMLParser) EntitySelectors() []*core.EntitySelector {
+	return lo.FilterMap(p.yamlConfig.Entities, func(entity Entity, _ int) (*core.EntitySelector, bool) {
+		return entity.Selector, entity.Selector != nil
+	})
+}
+
+// EntityIds returns the ids of the entities declared by id, which are not expanded from a selector.
+func (p *YAMLParser) EntityIds() []string {
+	return lo.Uniq(lo.FilterMap(p.yamlConfig.Entities, func(entity Entity, _ int) (string, bool) {
+		entityId := strings.TrimSpace(entity.Id)
+		return entityId, entityId != "" && entity.Selector == nil && entity.expandedFrom == nil
+	}))
+}
+
+// ExpandEntitySelectors replaces every entity declared by selector with an entity per SYNQ path it matched,
+// with the same time partitioning, monitors and tests. A table declared by id keeps its own monitors and tests,
+// and a table matched by several selectors gets those of the first one.
+//
+// Declared ids are compared with the matched SYNQ paths once resolved, so that an id like sales.orders overrides
+// the selector matching bq-prod::sales::orders. Ids missing from resolved are compared as SYNQ paths.
+func (p *YAMLParser) ExpandEntitySelectors(matched map[string][]string, resolved map[string]string) {
+	declared := map[string]bool{}
+	for _, entityId := range p.EntityIds() {
+		declared[strings.ReplaceAll(lo.CoalesceOrEmpty(resolved[entityId], entityId), "::", ".")] = true
+	}
+
+	entities := []Entity{}
+	for _, entity := range p.yamlConfig.Entities {
+		synqPaths, ok := matched[lo.FromPtr(entity.Selector).String()]
+		if entity.Selector == nil || !ok {
+			entities = append(entities, entity)
+			continue
+		}
+		for _, synqPath := range synqPaths {
+			if declared[strings.ReplaceAll(synqPath, "::", ".")] {
+				continue
+			}
+			declared[strings.ReplaceAll(synqPath, "::", ".")] = true
+			entities = append(entities, Entity{
+				Id:                     synqPath,
+				TimePartitioningColumn: entity.TimePartitioningColumn,
+				Tests:                  entity.Tests,
+				Monitors:               entity.Monitors,
+				expandedFrom:           entity.Selector,
+			})
+		}
+	}
+	p.yamlConfig.Entities = entities
+}
+
+// ConvertToMonitorMoves returns the previous identities of the monitors declared with moved_from,
+// or moved with their namespace.
+func (p *YAMLParser) ConvertToMonitorMoves() []*core.MonitorMove {
+	moves := []*core.MonitorMove{}
+	for _, entity := range p.yamlConfig.Entities {
+		if entity.Selector != nil {
+			continue
+		}
+		for _, wrapper := range entity.Monitors {
+			yamlMonitor := wrapper.Monitor
+			movedFrom := lo.Map(yamlMonitor.GetMonitorMovedFrom(), func(movedFrom MovedFrom, _ int) *pb.MonitorDefinition {
+				return core.MonitorIdentity(movedFrom.ID, movedFrom.Namespace, strings.TrimSpace(movedFrom.Entity))
+			})
+
+			monitor := core.MonitorIdentity(yamlMonitor.GetMonitorID(), p.yamlConfig.ID, entity.Id)
+			if move := core.NewMonitorMove(monitor, movedFrom, p.yamlConfig.MovedFrom); move != nil {
+				moves = append(moves, move)
+			}
+		}
+	}
+	return moves
+}
+
+// ConvertToProtectedMonitors returns the identities of the monitors declared with prevent_destroy.
+func (p *YAMLParser) ConvertToProtectedMonitors() []*pb.MonitorDefinition {
+	protected := []*pb.MonitorDefinition{}
+	for _, entity := range p.yamlConfig.Entities {
+		if entity.Selector != nil {
+			continue
+		}
+		for _, wrapper := range entity.Monitors {
+			if wrapper.Monitor.GetMonitorPreventDestroy() {
+				protected = append(protected, core.MonitorIdentity(wrapper.Monitor.GetMonitorID(), p.yamlConfig.ID, entity.Id))
+			}
+		}
+	}
+	return protected
+}
+
+// ConvertToResetPolicies returns the monitors whose reset policy, or the default one, is not auto.
+func (p *YAMLParser) ConvertToResetPolicies() []*core.MonitorResetPolicy {
+	defaultPolicy := ""
+	if p.yamlConfig.Defaults != nil {
+		defaultPolicy = p.yamlConfig.Defaults.ResetPolicy
+	}
+
+	policies := []*core.MonitorResetPolicy{}
+	for _, entity := range p.yamlConfig.Entities {
+		if entity.Selector != nil {
+			continue
+		}
+		for _, wrapper := range entity.Monitors {
+			policy := cmp.Or(wrapper.Monitor.GetMonitorResetPolicy(), defaultPolicy, core.ResetPolicy_Auto)
+			if policy != core.ResetPolicy_Auto {
+				policies = append(policies, &core.MonitorResetPolicy{
+					Monitor: core.MonitorIdentity(wrapper.Monitor.GetMonitorID(), p.yamlConfig.ID, entity.Id),
+					Policy:  policy,
+				})
 			}
 		}
 	}
+	return policies
+}
+
+func validateResetPolicy(policy, monitorId, entityId string) ConversionErrors {
+	if policy == "" || slices.Contains(core.ResetPolicies, policy) {
+		return nil
+	}
+	return ConversionErrors{{
+		Field:   "reset_policy",
+		Message: fmt.Sprintf("invalid reset policy: '%s', must be one of %s", policy, strings.Join(core.ResetPolicies, ", ")),
+		Monitor: monitorId,
+		Entity:  entityId,
+	}}
+}
+
+func validateMovedFrom(yamlMonitor MonitorInline, entityId string, selector *core.EntitySelector) ConversionErrors {
+	var errors ConversionErrors
+
+	for _, movedFrom := range yamlMonitor.GetMonitorMovedFrom() {
+		if movedFrom.ID == "" && strings.TrimSpace(movedFrom.Entity) == "" && movedFrom.Namespace == "" {
+			errors = append(errors, ConversionError{
+				Field:   "moved_from",
+				Message: "id, entity or namespace must be set",
+				Monitor: yamlMonitor.GetMonitorID(),
+				Entity:  entityId,
+			})
+		}
+		// Every table of a selector would move from the same one.
+		if selector != nil && strings.TrimSpace(movedFrom.Entity) != "" {
+			errors = append(errors, ConversionError{
+				Field:   "moved_from",
+				Message: "entity cannot be set on the monitors of a selector",
+				Monitor: yamlMonitor.GetMonitorID(),
+				Entity:  entityId,
+			})
+		}
+	}
 
-	return monitors, errors.Coalesce()
+	return errors
+}
+
+func (p *YAMLParser) validateNamespacesMovedFrom() ConversionErrors {
+	var errors ConversionErrors
+
+	for _, namespace := range p.yamlConfig.MovedFrom {
+		if namespace == "" || namespace == p.yamlConfig.ID {
+			errors = append(errors, ConversionError{
+				Field:   "moved_from",
+				Message: fmt.Sprintf("invalid previous namespace: '%s'", namespace),
+			})
+		}
+	}
+
+	return errors
+}
+
+// locate sets the position of every error from the YAML source, when the parser was created from bytes.
+func (p *YAMLParser) locate(errors ConversionErrors) ConversionErrors {
+	if p.root == nil {
+		return errors
+	}
+
+	entitiesNode := core.MappingValue(p.root, "entities")
+	for i := range errors {
+		node := p.root
+		entityNode := core.SequenceItemWithID(entitiesNode, errors[i].Entity)
+		if entityNode == nil && errors[i].Entity != "" {
+			entityNode = findSelectorNode(entitiesNode, errors[i].Entity)
+		}
+		if entityNode != nil {
+			node = entityNode
+			if errors[i].Monitor != "" {
+				if monitorNode := core.SequenceItemWithID(core.MappingValue(entityNode, "monitors"), errors[i].Monitor); monitorNode != nil {
+					node = monitorNode
+				}
+			}
+			if errors[i].Test != "" {
+				if testNode := findTestNode(core.MappingValue(entityNode, "tests"), errors[i].Test); testNode != nil {
+					node = testNode
+				}
+			}
+		}
+		if errors[i].Entity == "" && errors[i].Field == "id" {
+			if entityNode := core.SequenceItem(entitiesNode, hasNoID); entityNode != nil {
+				errors[i].Position = core.PositionOf(entityNode)
+				continue
+			}
+		}
+		errors[i].Position = core.FieldPosition(node, errors[i].Field)
+	}
+	return errors
+}
+
+func hasNoID(node *goyaml.Node) bool {
+	id := core.MappingValue(node, "id")
+	return (id == nil || strings.TrimSpace(id.Value) == "") && core.MappingValue(node, "selector") == nil
+}
+
+// findSelectorNode finds an entity declared by the selector with the given String().
+func findSelectorNode(entitiesNode *goyaml.Node, selector string) *goyaml.Node {
+	return core.SequenceItem(entitiesNode, func(item *goyaml.Node) bool {
+		var entitySelector core.EntitySelector
+		node := core.MappingValue(item, "selector")
+		return node != nil && node.Decode(&entitySelector) == nil && entitySelector.String() == selector
+	})
+}
+
+// findTestNode matches tests on their explicit id first, then on the id derived from their content.
+func findTestNode(testsNode *goyaml.Node, testID string) *goyaml.Node {
+	if node := core.SequenceItemWithID(testsNode, testID); node != nil {
+		return node
+	}
+	return core.SequenceItem(testsNode, func(item *goyaml.Node) bool {
+		var test Test
+		if err := item.Decode(&test); err != nil {
+			return false
+		}
+		return core.MappingValue(item, "id") == nil && defaultTestID(test.Test) == testID
+	})
 }
 
 func (p *YAMLParser) createBaseMonitor(id, name, description, entityId, timePartitioning string) *pb.MonitorDefinition {
@@ -183,7 +457,7 @@ func (p *YAMLParser) applySeverity(monitor *pb.MonitorDefinition, severity strin
 	}
 }
 
-func (p *YAMLParser) applyMode(monitor *pb.MonitorDefinition, mode *Mode, entity *Entity) ConversionErrors {
+func (p *YAMLParser) applyMode(monitor *pb.MonitorDefinition, mode *Mode, entityId string) ConversionErrors {
 	var errors ConversionErrors
 	if p.yamlConfig.Defaults != nil && mode == nil {
 		mode = p.yamlConfig.Defaults.Mode
@@ -206,7 +480,7 @@ func (p *YAMLParser) applyMode(monitor *pb.MonitorDefinition, mode *Mode, entity
 				Field:   "mode.anomaly_engine.sensitivity",
 				Message: fmt.Sprintf("invalid sensitivity: %s", mode.AnomalyEngine.Sensitivity),
 				Monitor: monitor.Id,
-				Entity:  entity.Id,
+				Entity:  entityId,
 			})
 		}
 
@@ -364,7 +638,8 @@ func (p *YAMLParser) GetYAMLSummary() map[string]any {
 
 	for _, entity := range p.yamlConfig.Entities {
 		totalTests += len(entity.Tests)
-		for range entity.Tests {
+		for _, wrapper := range entity.Tests {
+			testTypeCount[wrapper.Test.GetTestType()]++
 		}
 
 		totalMonitors += len(entity.Monitors)
@@ -0,0 +1,263 @@
+package v1beta2
+
+import (
+	"fmt"
+	"strings"
+
+	sqltestsv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/datachecks/sqltests/v1"
+	entitiesv1 "buf.build/gen/go/getsynq/api/protocolbuffers/go/synq/entities/v1"
+	"github.com/samber/lo"
+)
+
+const (
+	RecurrenceRule_Daily  = "FREQ=DAILY"
+	RecurrenceRule_Hourly = "FREQ=HOURLY"
+)
+
+func (p *YAMLParser) ConvertToSqlTests() ([]*sqltestsv1.SqlTest, error) {
+	var errors ConversionErrors
+	var sqlTests []*sqltestsv1.SqlTest
+
+	for _, entity := range p.yamlConfig.Entities {
+		// how the entity is declared is reported by ConvertToMonitorDefinitions
+		entityId, _, _ := checkEntity(entity)
+		if entityId == "" {
+			continue
+		}
+
+		timePartitioning := entity.TimePartitioningColumn
+		if p.yamlConfig.Defaults != nil && timePartitioning == "" {
+			timePartitioning = p.yamlConfig.Defaults.TimePartitioning
+		}
+
+		existingTestIds := make(map[string]bool)
+
+		for _, wrapper := range entity.Tests {
+			yamlTest := wrapper.Test
+			testID := strings.TrimSpace(yamlTest.GetTestID())
+			if testID == "" {
+				testID = defaultTestID(yamlTest)
+			}
+			if testID == "" {
+				errors = append(errors, ConversionError{
+					Field:   "id",
+					Message: fmt.Sprintf("must be set for %s tests", yamlTest.GetTestType()),
+					Entity:  entityId,
+				})
+				continue
+			}
+
+			sqlTest := p.createBaseSqlTest(testID, yamlTest.GetTestName(), yamlTest.GetTestDescription(), entity.Id)
+			sqlTest.RecurrenceRule = p.recurrenceRule(yamlTest.GetTestSchedule())
+
+			convErrors := p.applyTestTemplate(sqlTest, yamlTest, timePartitioning)
+			for i := range convErrors {
+				convErrors[i].Test = testID
+				convErrors[i].Entity = entityId
+			}
+			errors = append(errors, convErrors...)
+
+			if _, ok := existingTestIds[testID]; ok {
+				errors = append(errors, ConversionError{
+					Field:   "id",
+					Message: "must be unique within entity",
+					Test:    testID,
+					Entity:  entityId,
+				})
+			} else {
+				existingTestIds[testID] = true
+				// The tests of a selector are only checked until it is expanded into its entities.
+				if entity.Selector == nil {
+					sqlTests = append(sqlTests, sqlTest)
+				}
+			}
+		}
+	}
+
+	return sqlTests, p.locate(lo.Uniq(errors)).Coalesce()
+}
+
+func (p *YAMLParser) createBaseSqlTest(id, name, description, entityId string) *sqltestsv1.SqlTest {
+	sqlTest := &sqltestsv1.SqlTest{
+		Id:          id,
+		Name:        name,
+		Description: description,
+		ConfigId:    p.yamlConfig.ID,
+		MonitoredId: &entitiesv1.Identifier{
+			Id: &entitiesv1.Identifier_SynqPath{
+				SynqPath: &entitiesv1.SynqPathIdentifier{
+					Path: entityId,
+				},
+			},
+		},
+	}
+
+	if name == "" {
+		sqlTest.Name = id
+	}
+
+	return sqlTest
+}
+
+func (p *YAMLParser) recurrenceRule(schedule *Schedule) string {
+	if schedule == nil && p.yamlConfig.Defaults != nil {
+		schedule = p.yamlConfig.Defaults.Schedule
+	}
+
+	if schedule != nil && schedule.Type == "hourly" {
+		return RecurrenceRule_Hourly
+	}
+	return RecurrenceRule_Daily
+}
+
+func (p *YAMLParser) applyTestTemplate(sqlTest *sqltestsv1.SqlTest, yamlTest TestInline, timePartitioning string) ConversionErrors {
+	var errors ConversionErrors
+
+	requireColumns := func(columns []string) {
+		if len(columns) == 0 {
+			errors = append(errors, ConversionError{Field: "columns", Message: "at least one column is required"})
+		}
+	}
+	requireColumn := func(column string) {
+		if strings.TrimSpace(column) == "" {
+			errors = append(errors, ConversionError{Field: "column", Message: "column is required"})
+		}
+	}
+	requireValues := func(values []string) {
+		if len(values) == 0 {
+			errors = append(errors, ConversionError{Field: "values", Message: "at least one value is required"})
+		}
+	}
+
+	switch t := yamlTest.(type) {
+	case *NotNullTest:
+		requireColumns(t.Columns)
+		sqlTest.Template = &sqltestsv1.SqlTest_NotNull{NotNull: &sqltestsv1.NotNullTest{ColumnNames: t.Columns}}
+	case *EmptyTest:
+		requireColumns(t.Columns)
+		sqlTest.Template = &sqltestsv1.SqlTest_Empty{Empty: &sqltestsv1.EmptyTest{ColumnNames: t.Columns}}
+	case *UniqueTest:
+		requireColumns(t.Columns)
+		unique := &sqltestsv1.UniqueTest{ColumnNames: t.Columns}
+		if t.TimePartitionColumn != "" {
+			unique.TimePartitionColumnName = &t.TimePartitionColumn
+		}
+		if t.TimeWindowSeconds != 0 {
+			unique.TimeWindowSeconds = &t.TimeWindowSeconds
+		}
+		sqlTest.Template = &sqltestsv1.SqlTest_Unique{Unique: unique}
+	case *AcceptedValuesTest:
+		requireColumn(t.Column)
+		requireValues(t.Values)
+		sqlTest.Template = &sqltestsv1.SqlTest_AcceptedValues{
+			AcceptedValues: &sqltestsv1.AcceptedValuesTest{ColumnName: t.Column, AcceptedValues: t.Values},
+		}
+	case *RejectedValuesTest:
+		requireColumn(t.Column)
+		requireValues(t.Values)
+		sqlTest.Template = &sqltestsv1.SqlTest_RejectedValues{
+			RejectedValues: &sqltestsv1.RejectedValuesTest{ColumnName: t.Column, RejectedValues: t.Values},
+		}
+	case *MinMaxTest:
+		requireColumn(t.Column)
+		if t.MinValue > t.MaxValue {
+			errors = append(errors, ConversionError{
+				Field:   "min_value",
+				Message: fmt.Sprintf("min_value %v must not be greater than max_value %v", t.MinValue, t.MaxValue),
+			})
+		}
+		sqlTest.Template = &sqltestsv1.SqlTest_MinMax{
+			MinMax: &sqltestsv1.MinMaxTest{ColumnName: t.Column, MinValue: t.MinValue, MaxValue: t.MaxValue},
+		}
+	case *MinValueTest:
+		requireColumn(t.Column)
+		sqlTest.Template = &sqltestsv1.SqlTest_MinValue{
+			MinValue: &sqltestsv1.MinValueTest{ColumnName: t.Column, MinValue: t.MinValue},
+		}
+	case *MaxValueTest:
+		requireColumn(t.Column)
+		sqlTest.Template = &sqltestsv1.SqlTest_MaxValue{
+			MaxValue: &sqltestsv1.MaxValueTest{ColumnName: t.Column, MaxValue: t.MaxValue},
+		}
+	case *FreshnessTest:
+		column := t.TimePartitionColumn
+		if column == "" {
+			column = timePartitioning
+		}
+		if column == "" {
+			errors = append(errors, ConversionError{
+				Field:   "time_partition_column",
+				Message: "time_partition_column is required for freshness tests when the entity has no time_partitioning_column",
+			})
+		}
+		if t.TimeWindowSeconds <= 0 {
+			errors = append(errors, ConversionError{
+				Field:   "time_partition_seconds",
+				Message: "time_partition_seconds must be greater than 0 for freshness tests",
+			})
+		}
+		sqlTest.Template = &sqltestsv1.SqlTest_Freshness{
+			Freshness: &sqltestsv1.FreshnessTest{TimePartitionColumnName: column, TimeWindowSeconds: t.TimeWindowSeconds},
+		}
+	case *RelativeTimeTest:
+		requireColumn(t.Column)
+		if strings.TrimSpace(t.RelativeColumn) == "" {
+			errors = append(errors, ConversionError{Field: "relative_column", Message: "relative_column is required"})
+		}
+		sqlTest.Template = &sqltestsv1.SqlTest_RelativeTime{
+			RelativeTime: &sqltestsv1.RelativeTimeTest{ColumnName: t.Column, RelativeColumnName: t.RelativeColumn},
+		}
+	case *BusinessRuleTest:
+		if strings.TrimSpace(t.SQLExpression) == "" {
+			errors = append(errors, ConversionError{Field: "sql_expression", Message: "sql_expression is required for business_rule tests"})
+		}
+		sqlTest.Template = &sqltestsv1.SqlTest_BusinessRule{
+			BusinessRule: &sqltestsv1.BusinessRuleTest{SqlExpression: t.SQLExpression},
+		}
+	default:
+		errors = append(errors, ConversionError{
+			Field:   "type",
+			Message: fmt.Sprintf("unsupported test type: %T", t),
+		})
+	}
+
+	return errors
+}
+
+// defaultTestID derives an ID from the test type and its columns,
+// so that simple tests do not need an explicit `id`.
+// Business rules have no natural key and must set one.
+func defaultTestID(yamlTest TestInline) string {
+	parts := []string{yamlTest.GetTestType()}
+
+	switch t := yamlTest.(type) {
+	case *NotNullTest:
+		parts = append(parts, t.Columns...)
+	case *EmptyTest:
+		parts = append(parts, t.Columns...)
+	case *UniqueTest:
+		parts = append(parts, t.Columns...)
+	case *AcceptedValuesTest:
+		parts = append(parts, t.Column)
+	case *RejectedValuesTest:
+		parts = append(parts, t.Column)
+	case *MinMaxTest:
+		parts = append(parts, t.Column)
+	case *MinValueTest:
+		parts = append(parts, t.Column)
+	case *MaxValueTest:
+		parts = append(parts, t.Column)
+	case *FreshnessTest:
+		parts = append(parts, t.TimePartitionColumn)
+	case *RelativeTimeTest:
+		parts = append(parts, t.Column, t.RelativeColumn)
+	default:
+		return ""
+	}
+
+	parts = lo.Compact(parts)
+	for i := range parts {
+		parts[i] = sanitizeIdPart(parts[i])
+	}
+	return strings.Join(parts, "_")
+}
@@ -11,29 +11,42 @@ import (
 )
 
 type Defaults struct {
-	Severity         string    `yaml:"severity,omitempty"          jsonschema:"enum=WARNING,enum=ERROR"`
+	Severity         string    `yaml:"severity,omitempty"     jsonschema:"enum=WARNING,enum=ERROR"`
 	TimePartitioning string    `yaml:"time_partitioning,omitempty"`
 	Schedule         *Schedule `yaml:"schedule,omitempty"`
 	Mode             *Mode     `yaml:"mode,omitempty"`
 	Timezone         string    `yaml:"timezone,omitempty"`
+	ResetPolicy      string    `yaml:"reset_policy,omitempty" jsonschema:"enum=auto,enum=never,enum=always"`
 }
 
 type Config struct {
 	core.Config `yaml:",inline"`
 
 	Defaults *Defaults `yaml:"defaults,omitempty"`
-	Entities []Entity  `yaml:"entities"           jsonschema:"required,minItems=1"`
+	Entities []Entity  `yaml:"entities" jsonschema:"required,minItems=1"`
 }
 
+// Entity declares the monitors and tests of a table, by its id, or of every table matched by its selector.
+// An entity expanded from a selector keeps it in expandedFrom.
 type Entity struct {
-	Id                     string    `yaml:"id"                                 jsonschema:"required"`
-	TimePartitioningColumn string    `yaml:"time_partitioning_column,omitempty"`
-	Tests                  []Test    `yaml:"tests,omitempty"`
-	Monitors               []Monitor `yaml:"monitors,omitempty"`
+	Id                     string               `yaml:"id,omitempty"       jsonschema:"oneof_required=id"`
+	Selector               *core.EntitySelector `yaml:"selector,omitempty" jsonschema:"oneof_required=selector"`
+	TimePartitioningColumn string               `yaml:"time_partitioning_column,omitempty"`
+	Tests                  []Test               `yaml:"tests,omitempty"`
+	Monitors               []Monitor            `yaml:"monitors,omitempty"`
+
+	expandedFrom *core.EntitySelector
+}
+
+// MovedFrom is an identity the monitor was previously deployed with, unset fields are those of the monitor.
+type MovedFrom struct {
+	ID        string `yaml:"id,omitempty"`
+	Entity    string `yaml:"entity,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
 }
 
 type Segmentation struct {
-	Expression    string    `yaml:"expression"               jsonschema:"required"`
+	Expression    string    `yaml:"expression" jsonschema:"required"`
 	IncludeValues *[]string `yaml:"include_values,omitempty"`
 	ExcludeValues *[]string `yaml:"exclude_values,omitempty"`
 }
@@ -57,7 +70,7 @@ type Schedule struct {
 }
 
 type ScheduleInline struct {
-	Type                  string         `yaml:"type"                              jsonschema:"required,enum=daily,enum=hourly"`
+	Type                  string         `yaml:"type" jsonschema:"required,enum=daily,enum=hourly"`
 	TimePartitioningShift *time.Duration `yaml:"time_partitioning_shift,omitempty"`
 	QueryDelay            *time.Duration `yaml:"query_delay,omitempty"`
 	IgnoreLast            *int32         `yaml:"ignore_last,omitempty"`
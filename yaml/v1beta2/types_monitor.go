@@ -18,6 +18,9 @@ type MonitorInline interface {
 	GetMonitorMode() *Mode
 	GetMonitorSegmentation() *Segmentation
 	GetMonitorSchedule() *Schedule
+	GetMonitorMovedFrom() []MovedFrom
+	GetMonitorPreventDestroy() bool
+	GetMonitorResetPolicy() string
 }
 
 var builder = schemautils.DiscriminatedUnionBuilder[MonitorInline]{
@@ -87,16 +90,19 @@ func (w Monitor) MarshalYAML() (any, error) {
 }
 
 type BaseMonitor struct {
-	ID           string        `yaml:"id"                     jsonschema:"required"`
-	Type         string        `yaml:"type"                   jsonschema:"required"`
-	Name         string        `yaml:"name,omitempty"`
-	Description  string        `yaml:"description,omitempty"`
-	Filter       string        `yaml:"filter,omitempty"`
-	Severity     string        `yaml:"severity,omitempty"     jsonschema:"enum=WARNING,enum=ERROR"`
-	Timezone     string        `yaml:"timezone,omitempty"`
-	Mode         *Mode         `yaml:"mode,omitempty"`
-	Segmentation *Segmentation `yaml:"segmentation,omitempty"`
-	Schedule     *Schedule     `yaml:"schedule,omitempty"`
+	ID             string        `yaml:"id"                     jsonschema:"required"`
+	Type           string        `yaml:"type"                   jsonschema:"required"`
+	Name           string        `yaml:"name,omitempty"`
+	Description    string        `yaml:"description,omitempty"`
+	Filter         string        `yaml:"filter,omitempty"`
+	Severity       string        `yaml:"severity,omitempty"     jsonschema:"enum=WARNING,enum=ERROR"`
+	Timezone       string        `yaml:"timezone,omitempty"`
+	Mode           *Mode         `yaml:"mode,omitempty"`
+	Segmentation   *Segmentation `yaml:"segmentation,omitempty"`
+	Schedule       *Schedule     `yaml:"schedule,omitempty"`
+	MovedFrom      []MovedFrom   `yaml:"moved_from,omitempty"`
+	PreventDestroy bool          `yaml:"prevent_destroy,omitempty"`
+	ResetPolicy    string        `yaml:"reset_policy,omitempty" jsonschema:"enum=auto,enum=never,enum=always"`
 }
 
 func (b BaseMonitor) GetMonitorID() string {
@@ -135,6 +141,30 @@ func (b BaseMonitor) GetMonitorSchedule() *Schedule {
 	return b.Schedule
 }
 
+func (b BaseMonitor) GetMonitorMovedFrom() []MovedFrom {
+	return b.MovedFrom
+}
+
+func (b *BaseMonitor) SetMonitorMovedFrom(movedFrom []MovedFrom) {
+	b.MovedFrom = movedFrom
+}
+
+func (b BaseMonitor) GetMonitorPreventDestroy() bool {
+	return b.PreventDestroy
+}
+
+func (b *BaseMonitor) SetMonitorPreventDestroy(preventDestroy bool) {
+	b.PreventDestroy = preventDestroy
+}
+
+func (b BaseMonitor) GetMonitorResetPolicy() string {
+	return b.ResetPolicy
+}
+
+func (b *BaseMonitor) SetMonitorResetPolicy(resetPolicy string) {
+	b.ResetPolicy = resetPolicy
+}
+
 type (
 	FreshnessMonitor struct {
 		BaseMonitor `       yaml:",inline"`
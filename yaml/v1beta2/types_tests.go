@@ -1,12 +1,20 @@
 package v1beta2
 
 import (
+	"fmt"
+
 	schemautils "github.com/getsynq/monitors_mgmt/schema_utils"
 	"github.com/invopop/jsonschema"
+	goyaml "go.yaml.in/yaml/v3"
 )
 
 type TestInline interface {
 	isTest()
+	GetTestID() string
+	GetTestType() string
+	GetTestName() string
+	GetTestDescription() string
+	GetTestSchedule() *Schedule
 }
 type isTestImpl struct{}
 
@@ -39,9 +47,69 @@ func (Test) JSONSchema() *jsonschema.Schema {
 	return testBuilder.Build()
 }
 
+func decodeTest[T TestInline](n *goyaml.Node) (TestInline, error) {
+	var t T
+	err := n.Decode(&t)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (w *Test) UnmarshalYAML(n *goyaml.Node) error {
+	type Typed struct {
+		Type string `yaml:"type"`
+	}
+
+	var t Typed
+	err := n.Decode(&t)
+	if err != nil {
+		return err
+	}
+
+	var test TestInline
+	switch t.Type {
+	case "not_null":
+		test, err = decodeTest[*NotNullTest](n)
+	case "empty":
+		test, err = decodeTest[*EmptyTest](n)
+	case "unique":
+		test, err = decodeTest[*UniqueTest](n)
+	case "accepted_values":
+		test, err = decodeTest[*AcceptedValuesTest](n)
+	case "rejected_values":
+		test, err = decodeTest[*RejectedValuesTest](n)
+	case "min_max":
+		test, err = decodeTest[*MinMaxTest](n)
+	case "min_value":
+		test, err = decodeTest[*MinValueTest](n)
+	case "max_value":
+		test, err = decodeTest[*MaxValueTest](n)
+	case "freshness":
+		test, err = decodeTest[*FreshnessTest](n)
+	case "relative_time":
+		test, err = decodeTest[*RelativeTimeTest](n)
+	case "business_rule":
+		test, err = decodeTest[*BusinessRuleTest](n)
+	default:
+		return fmt.Errorf("unsupported test type: %s", t.Type)
+	}
+	if err != nil {
+		return err
+	}
+	w.Test = test
+
+	return nil
+}
+
+func (w Test) MarshalYAML() (any, error) {
+	return w.Test, nil
+}
+
 type (
 	TestBase struct {
-		isTestImpl
+		isTestImpl `yaml:"-"`
 
 		ID          string   `yaml:"id,omitempty"`
 		Type        string   `yaml:"type"`
@@ -58,6 +126,29 @@ type (
 	}
 )
 
+func (b TestBase) GetTestID() string {
+	return b.ID
+}
+
+func (b TestBase) GetTestType() string {
+	return b.Type
+}
+
+func (b TestBase) GetTestName() string {
+	return b.Name
+}
+
+func (b TestBase) GetTestDescription() string {
+	return b.Description
+}
+
+func (b TestBase) GetTestSchedule() *Schedule {
+	if b.Schedule.Type == "" {
+		return nil
+	}
+	return &b.Schedule
+}
+
 type (
 	NotNullTest struct {
 		TestBase        `yaml:",inline"`